@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// benchBatch is the number of values each run of a benchmark processes.
+const benchBatch = 4096
+
+// benchTime is how long each benchmark runs for.
+const benchTime = 300 * time.Millisecond
+
+// benchCase is a benchmark of "span bench": run processes a batch of values in
+// [0, 1), and lines holds the same values as text lines. An error aborts the
+// benchmarks, whose figures would not be those of the operation.
+type benchCase struct {
+	name string
+	run  func(values []float64, lines []string) error
+}
+
+// benchCases lists the benchmarks: the streaming operations on the batch
+// functions of the interval package, then the I/O modes.
+func benchCases() []benchCase {
+	out := make([]float64, benchBatch)
+	expr, exprErr := interval.ParseExpr("clamp(0, 1) | remap(0, 1, 0, 100) | ease(inOutCubic)")
+	text := streamOptions{format: "%g"}
+	f64, _ := parseBinaryFormat("f64")
+	var packed bytes.Buffer
+	return []benchCase{
+		{"remap", func(values []float64, _ []string) error { return interval.RemapSlice(out, values, 0, 1, 0, 100) }},
+		{"limit", func(values []float64, _ []string) error {
+			interval.LimitSlice(out, values, 0.25, 0.75)
+			return nil
+		}},
+		{"eval", func(values []float64, _ []string) error {
+			interval.EvalSlice(out, values, 10, 20)
+			return nil
+		}},
+		{"deval", func(values []float64, _ []string) error { return interval.DevalSlice(out, values, 0, 2) }},
+		{"snap", func(values []float64, _ []string) error { return interval.SnapSlice(out, values, 10, 0, 1) }},
+		{"expr", func(values []float64, _ []string) error {
+			if exprErr != nil {
+				return exprErr
+			}
+			for i, val := range values {
+				var err error
+				if out[i], err = expr.Apply(val); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"stats", func(values []float64, _ []string) error {
+			interval.Describe(values)
+			return nil
+		}},
+		{"parse-text", func(_ []float64, lines []string) error {
+			for i, line := range lines {
+				var err error
+				if out[i], err = text.parseValue(line); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"format-text", func(values []float64, _ []string) error {
+			for _, val := range values {
+				if _, err := text.formatValue(val); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"remap-lines", func(_ []float64, lines []string) error {
+			remap := func(val float64) (float64, error) { return interval.Remap(val, 0, 1, 0, 100) }
+			for _, line := range lines {
+				if o := processLine(line, text, remap); o.err != nil {
+					return o.err
+				}
+			}
+			return nil
+		}},
+		{"binary-in", func(values []float64, _ []string) error {
+			if packed.Len() == 0 {
+				for _, val := range values {
+					if err := f64.write(&packed, val); err != nil {
+						return err
+					}
+				}
+			}
+			i := 0
+			return f64.read(bytes.NewReader(packed.Bytes()), func(val float64) {
+				out[i] = val
+				i++
+			})
+		}},
+		{"binary-out", func(values []float64, _ []string) error {
+			for _, val := range values {
+				if err := f64.write(io.Discard, val); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+	}
+}
+
+// benchNames returns the names of the benchmarks.
+func benchNames() []string {
+	var names []string
+	for _, c := range benchCases() {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// runBench runs the benchmarks named in names, or all of them, and prints the
+// values each one processes per second on this machine.
+func runBench(names []string) {
+	cases, known := benchCases(), benchNames()
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			fmt.Fprintf(os.Stderr, "Error: unknown benchmark '%s' (expected %s)\n", name, strings.Join(known, ", "))
+			exit(exitUsage)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, benchBatch)
+	lines := make([]string, benchBatch)
+	for i := range values {
+		values[i] = r.Float64()
+		lines[i] = strconv.FormatFloat(values[i], 'g', -1, 64)
+	}
+
+	fmt.Printf("span %s, %d values per batch, %v per benchmark\n", Version, benchBatch, benchTime)
+	for _, c := range cases {
+		if len(names) > 0 && !slices.Contains(names, c.name) {
+			continue
+		}
+		count := 0
+		start := time.Now()
+		for time.Since(start) < benchTime {
+			if err := c.run(values, lines); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: benchmark '%s' failed: %v\n", c.name, err)
+				exit(exitFailure)
+			}
+			count += benchBatch
+		}
+		rate := float64(count) / time.Since(start).Seconds()
+		fmt.Printf("%-12s %8s values/s\n", c.name, interval.FormatHuman(rate, "%.1f", false))
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestBenchCases(t *testing.T) {
+	values := make([]float64, benchBatch)
+	lines := make([]string, benchBatch)
+	for i := range values {
+		values[i] = float64(i) / benchBatch
+		lines[i] = "0.5"
+	}
+	for _, c := range benchCases() {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.run(values, lines); err != nil {
+				t.Errorf("benchmark %s returned an unexpected error: %v", c.name, err)
+			}
+		})
+	}
+}
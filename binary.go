@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// binaryFormat describes a stream of packed floats, read or written instead of text lines.
+type binaryFormat struct {
+	size  int // Bytes per value: 1 for u8, 4 for f32, 8 for f64.
+	order binary.ByteOrder
+}
+
+// parseBinaryFormat parses a binary format spec: "f32", "f64" or "u8" (one
+// unsigned byte per value), optionally followed by ":le" (the default) or ":be"
+// for the byte order.
+func parseBinaryFormat(spec string) (*binaryFormat, error) {
+	kind, order, hasOrder := strings.Cut(strings.ToLower(spec), ":")
+	f := &binaryFormat{order: binary.LittleEndian}
+	switch kind {
+	case "f32":
+		f.size = 4
+	case "f64":
+		f.size = 8
+	case "u8":
+		f.size = 1
+	default:
+		return nil, fmt.Errorf("unknown binary format '%s' (expected f32, f64 or u8)", spec)
+	}
+	if hasOrder {
+		switch order {
+		case "le":
+		case "be":
+			f.order = binary.BigEndian
+		default:
+			return nil, fmt.Errorf("unknown byte order '%s' (expected le or be)", order)
+		}
+	}
+	return f, nil
+}
+
+// read reads values from r until EOF and calls fn for each of them.
+// A truncated trailing value is reported as an error.
+func (f *binaryFormat) read(r io.Reader, fn func(float64)) error {
+	reader := bufio.NewReader(r)
+	buf := make([]byte, f.size)
+	for {
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			if err == io.ErrUnexpectedEOF {
+				return fmt.Errorf("truncated value at end of input")
+			}
+			return err
+		}
+		switch f.size {
+		case 1:
+			fn(float64(buf[0]))
+		case 4:
+			fn(float64(math.Float32frombits(f.order.Uint32(buf))))
+		default:
+			fn(math.Float64frombits(f.order.Uint64(buf)))
+		}
+	}
+}
+
+// write appends a value to w. In u8, the value is clamped and rounded to a byte.
+func (f *binaryFormat) write(w io.Writer, val float64) error {
+	buf := make([]byte, f.size)
+	switch f.size {
+	case 1:
+		buf[0] = toByte(val)
+	case 4:
+		f.order.PutUint32(buf, math.Float32bits(float32(val)))
+	default:
+		f.order.PutUint64(buf, math.Float64bits(val))
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// toByte clamps a value to [0, 255] and rounds it to the nearest integer, as
+// pixel and PWM values are. NaN gives 0.
+func toByte(val float64) uint8 {
+	if math.IsNaN(val) {
+		return 0
+	}
+	return uint8(math.Round(interval.Limit(val, 0, 255)))
+}
+
+// readBinaryStream reads packed floats from stdin, in the format of
+// opts.binaryIn, and calls fn for each of them.
+func readBinaryStream(opts streamOptions, fn func(float64)) {
+	if err := opts.binaryIn.read(os.Stdin, fn); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", err)
+		opts.exit(exitFailure)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseBinaryFormat(t *testing.T) {
+	for _, spec := range []string{"f16", "f32:me", "", "u8:"} {
+		if _, err := parseBinaryFormat(spec); err == nil {
+			t.Errorf("parseBinaryFormat(%q) returned no error", spec)
+		}
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	values := []float64{0, 1, -2.5, 0.1, 1e300, math.Inf(-1)}
+	tests := []struct {
+		spec  string
+		first []byte // The encoding of the value 1.
+		want  []float64
+	}{
+		{"f64", []byte{0, 0, 0, 0, 0, 0, 0xF0, 0x3F}, values},
+		{"f64:le", []byte{0, 0, 0, 0, 0, 0, 0xF0, 0x3F}, values},
+		{"f64:be", []byte{0x3F, 0xF0, 0, 0, 0, 0, 0, 0}, values},
+		{"f32", []byte{0, 0, 0x80, 0x3F}, []float64{0, 1, -2.5, float64(float32(0.1)), math.Inf(1), math.Inf(-1)}},
+		{"F32:BE", []byte{0x3F, 0x80, 0, 0}, []float64{0, 1, -2.5, float64(float32(0.1)), math.Inf(1), math.Inf(-1)}},
+		{"u8", []byte{1}, []float64{0, 1, 0, 0, 255, 0}},
+		{"u8:be", []byte{1}, []float64{0, 1, 0, 0, 255, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			f, err := parseBinaryFormat(tt.spec)
+			if err != nil {
+				t.Fatalf("parseBinaryFormat(%q) returned an unexpected error: %v", tt.spec, err)
+			}
+			var packed bytes.Buffer
+			for _, val := range values {
+				if err := f.write(&packed, val); err != nil {
+					t.Fatalf("write() returned an unexpected error: %v", err)
+				}
+			}
+			if packed.Len() != len(values)*f.size {
+				t.Fatalf("wrote %d bytes, want %d", packed.Len(), len(values)*f.size)
+			}
+			if got := packed.Bytes()[f.size : 2*f.size]; !bytes.Equal(got, tt.first) {
+				t.Errorf("encoding of 1 = % x, want % x", got, tt.first)
+			}
+			var got []float64
+			if err := f.read(&packed, func(val float64) { got = append(got, val) }); err != nil {
+				t.Fatalf("read() returned an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinaryTruncated(t *testing.T) {
+	for _, spec := range []string{"f32", "f64:be"} {
+		f, _ := parseBinaryFormat(spec)
+		var packed bytes.Buffer
+		f.write(&packed, 1)
+		f.write(&packed, 2)
+		packed.Truncate(packed.Len() - 1)
+		var got []float64
+		err := f.read(&packed, func(val float64) { got = append(got, val) })
+		if err == nil {
+			t.Errorf("read() of a truncated %s value returned no error", spec)
+		}
+		if !reflect.DeepEqual(got, []float64{1}) {
+			t.Errorf("read() of a truncated %s stream = %v, want the whole values %v", spec, got, []float64{1})
+		}
+	}
+
+	// The stream still exits with an error after the values before the cut.
+	got, _, code := runSpan(t, "\x00\x00\x80\x3F\x00\x00", "--binary-in", "f32", "-l", "0", "10")
+	if code != exitFailure || got != "1\n" {
+		t.Errorf("span --binary-in f32 of a truncated stream = %q, exit code %d, want %q, exit code %d", got, code, "1\n", exitFailure)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gregory-chatelier/span/interval"
+	flag "github.com/spf13/pflag"
+)
+
+// operation describes an operation flag. Each one may also be given as a
+// subcommand, the first argument without its dashes: "span remap 0 10 0 1" runs
+// "span --remap 0 10 0 1".
+type operation struct {
+	name   string
+	args   string   // Arguments, as written in the usage, optional ones in brackets.
+	values []string // Values the first argument may take, when it is one of a list.
+}
+
+// operations lists the operations, in the order of the usage.
+var operations = []operation{
+	{name: "remap", args: "<src_a> <src_b> <dst_a> <dst_b>"},
+	{name: "remap-dynamic", args: "<dst_a> <dst_b>"},
+	{name: "remap2", args: "<sx0> <sx1> <sy0> <sy1> <dx0> <dx1> <dy0> <dy1>"},
+	{name: "geo-tile", args: "<zoom>"},
+	{name: "limit", args: "<min> <max>"},
+	{name: "encompass"},
+	{name: "stats"},
+	{name: "percentile", args: "<p>"},
+	{name: "cdf"},
+	{name: "icdf", args: "<p-file>"},
+	{name: "hist", args: "<bins> [<a> <b>]"},
+	{name: "outliers", args: "<drop|keep|mark>", values: []string{"drop", "keep", "mark"}},
+	{name: "downsample", args: "<n>"},
+	{name: "divide", args: "<steps> <a> <b>"},
+	{name: "divide-ease", args: "<name> <steps> <a> <b>", values: interval.EaseNames()},
+	{name: "nice", args: "[<a> <b>]"},
+	{name: "ticks", args: "<count> <a> <b>"},
+	{name: "eval", args: "<a> <b>"},
+	{name: "lerp-columns", args: "<t-column> [<from> <to>]"},
+	{name: "deval", args: "<a> <b>"},
+	{name: "random", args: "<count> <a> <b>"},
+	{name: "random-int", args: "<count> <a> <b>"},
+	{name: "random-normal", args: "<count> <mean> <stddev> [<a> <b>]"},
+	{name: "random-weighted", args: "<count> <file>"},
+	{name: "quasi", args: "<count> <a> <b>"},
+	{name: "jitter", args: "<steps> <a> <b>"},
+	{name: "smooth", args: "<window>"},
+	{name: "ema", args: "<alpha>"},
+	{name: "rolling-normalize", args: "<window> [<a> <b>]"},
+	{name: "diff"},
+	{name: "fill", args: "<linear|previous|value:<x>>", values: []string{"linear", "previous", "value:"}},
+	{name: "snap", args: "<steps> <a> <b>"},
+	{name: "expr", args: "<expression>"},
+	{name: "map-cmd", args: "<command>"},
+	{name: "serve", args: "<addr>"},
+	{name: "intersect", args: "<a> <b>"},
+	{name: "clip", args: "<a> <b>"},
+	{name: "split", args: "<p1,p2,...>"},
+	{name: "hull", args: "<a> <b>"},
+	{name: "relate", args: "<a> <b>"},
+	{name: "merge"},
+	{name: "gaps", args: "[<a> <b>]"},
+	{name: "coverage", args: "[<a> <b>]"},
+	{name: "subintervals", args: "<steps> <a> <b>"},
+	{name: "golden", args: "<n> <a> <b>"},
+	{name: "fibonacci", args: "<n> <a> <b>"},
+	{name: "spark", args: "[<min> <max>]"},
+	{name: "heat", args: "[<min> <max>]"},
+	{name: "quantize", args: "<a> <b> <n|out1,out2,...>"},
+	{name: "threshold", args: "<t1,t2,...> [<out0,out1,...>]"},
+	{name: "colorize", args: "<a> <b>"},
+	{name: "lerp-color", args: "<from> <to>"},
+	{name: "dashboard", args: "[<min> <max>]"},
+	{name: "gauge", args: "<a> <b>"},
+	{name: "boxplot", args: "[<min> <max>]"},
+}
+
+// operationOptions lists the options specific to some operations, by option.
+// Options not listed here are global and apply to every operation. With a
+// subcommand, an option specific to other operations is an error, and the help
+// of the subcommand only shows its own options and the global ones.
+var operationOptions = map[string][]string{
+	"spark-width":    {"spark"},
+	"spark-color":    {"spark", "dashboard"},
+	"color-gradient": {"spark"},
+	"ascii":          {"spark", "gauge", "dashboard", "serve"},
+	"chars":          {"spark", "dashboard", "serve"},
+	"gap-char":       {"spark"},
+	"series":         {"spark"},
+	"shared-scale":   {"spark"},
+	"labels":         {"spark", "boxplot"},
+	"label":          {"spark", "heat", "gauge", "boxplot", "hist", "dashboard"},
+	"log":            {"spark"},
+	"baseline":       {"spark"},
+	"follow":         {"spark"},
+	"interval":       {"spark", "gauge", "dashboard"},
+	"height":         {"spark"},
+	"style":          {"spark"},
+	"file":           {"spark"},
+	"size":           {"spark"},
+	"chart":          {"hist"},
+	"chart-width":    {"hist", "gauge", "boxplot"},
+	"warn":           {"gauge"},
+	"crit":           {"gauge"},
+	"palette":        {"colorize", "heat"},
+	"color-format":   {"colorize", "lerp-color"},
+	"color-space":    {"lerp-color"},
+	"clamp-axes":     {"remap2"},
+	"geo-pixel":      {"geo-tile"},
+	"geo-inverse":    {"geo-tile"},
+	"dist":           {"random"},
+	"base":           {"quasi"},
+	"every":          {"encompass"},
+	"k":              {"outliers", "boxplot"},
+	"outlier-method": {"outliers"},
+	"method":         {"downsample"},
+	"diff-first":     {"diff"},
+	"scale":          {"remap", "eval", "deval"},
+	"clamp":          {"remap", "eval", "deval"},
+	"overlap":        {"subintervals"},
+	"chunk":          {"map-cmd"},
+	"parallel":       {"remap", "limit", "eval", "deval", "snap", "expr"},
+}
+
+// command is the operation given as a subcommand, if any.
+var command string
+
+// flagValues lists the values of the options that take one of a list, for
+// completion.
+var flagValues = map[string][]string{
+	"color":          {"always", "never", "auto"},
+	"human":          {"si", "binary"},
+	"nan":            {"skip", "zero", "clamp", "propagate", "error"},
+	"as":             {"duration", "time"},
+	"unit":           {"ns", "us", "ms", "s", "m", "h"},
+	"layout":         {"rfc3339", "rfc1123", "datetime", "date"},
+	"output":         {"csv", "tsv", "prom", "svg", "png"},
+	"binary-in":      {"f32", "f64", "u8", "f32:le", "f32:be", "f64:le", "f64:be"},
+	"binary-out":     {"f32", "f64", "u8", "f32:le", "f32:be", "f64:le", "f64:be"},
+	"bytes":          {"raw", "dec"},
+	"scale":          {"linear", "log", "log:", "pow:", "sqrt", "symlog", "symlog:"},
+	"record-delim":   {"nul"},
+	"spark-width":    {"auto"},
+	"spark-color":    {"red", "green", "yellow", "blue", "magenta", "cyan"},
+	"style":          {"block", "braille", "winloss"},
+	"palette":        {"viridis", "heat"},
+	"color-format":   {"hex", "rgb"},
+	"color-space":    {"srgb", "hsl", "oklab"},
+	"clamp-axes":     {"x", "y", "xy"},
+	"dist":           {"uniform", "exponential", "lognormal", "triangular", "beta"},
+	"outlier-method": {"iqr", "zscore"},
+	"method":         {"lttb", "uniform"},
+	"diff-first":     {"drop", "zero"},
+}
+
+// lookupOperation returns the operation named name, and false if there is none.
+func lookupOperation(name string) (operation, bool) {
+	for _, op := range operations {
+		if op.name == name {
+			return op, true
+		}
+	}
+	return operation{}, false
+}
+
+// isOperation reports whether name is the name of an operation flag.
+func isOperation(name string) bool {
+	_, ok := lookupOperation(name)
+	return ok
+}
+
+// parseCommand handles a subcommand in the first of args: it sets command to
+// the operation it names, and returns args with the subcommand replaced by its
+// flag, so the subcommand and the flag are aliases. "span help [<operation>]"
+// prints the usage, "span completion <shell>" a completion script and "span
+// bench [<benchmark>...]" the throughput of span on this machine, and they exit.
+func parseCommand(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	switch name := args[0]; {
+	case name == "help":
+		switch {
+		case len(args) == 1:
+			usage()
+		case len(args) == 2 && isOperation(args[1]):
+			command = args[1]
+			usage()
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown operation '%s'\n", strings.Join(args[1:], " "))
+			exit(exitUsage)
+		}
+		exit(0)
+	case name == "completion":
+		if len(args) != 2 {
+			fmt.Fprintf(os.Stderr, "Error: completion requires 1 argument: <%s>\n", strings.Join(shells, "|"))
+			exit(exitUsage)
+		}
+		if err := writeCompletion(os.Stdout, args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		exit(0)
+	case name == "bench":
+		runBench(args[1:])
+		exit(0)
+	case isOperation(name):
+		command = name
+		return append([]string{"--" + name}, args[1:]...)
+	}
+	return args
+}
+
+// checkScope exits with an error when an option specific to other operations is
+// given with the subcommand.
+func checkScope() {
+	flag.Visit(func(f *flag.Flag) {
+		if scope, ok := operationOptions[f.Name]; ok && !slices.Contains(scope, command) {
+			fmt.Fprintf(os.Stderr, "Error: --%s does not apply to %s (only to %s)\n", f.Name, command, strings.Join(scope, ", "))
+			exit(exitUsage)
+		}
+	})
+}
+
+// commandUsage prints the usage of the subcommand: its own options, then the
+// global ones.
+func commandUsage() {
+	own := flag.NewFlagSet(command, flag.ContinueOnError)
+	global := flag.NewFlagSet("global", flag.ContinueOnError)
+	flag.VisitAll(func(f *flag.Flag) {
+		scope, ok := operationOptions[f.Name]
+		switch {
+		case ok && slices.Contains(scope, command):
+			own.AddFlag(f)
+		case !ok && !isOperation(f.Name):
+			global.AddFlag(f)
+		}
+	})
+
+	op, _ := lookupOperation(command)
+	synopsis := strings.TrimSpace("span " + command + " [flags] " + op.args)
+	fmt.Fprintf(os.Stderr, `NAME:
+    span %s - %s
+
+SYNOPSIS:
+    %s
+    command | %s
+
+`, command, flag.Lookup(command).Usage, synopsis, synopsis)
+	if own.HasFlags() {
+		fmt.Fprintln(os.Stderr, "OPTIONS:")
+		own.PrintDefaults()
+		fmt.Fprintln(os.Stderr)
+	}
+	fmt.Fprintln(os.Stderr, "GLOBAL OPTIONS:")
+	global.PrintDefaults()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+// definedFlags returns the names of the flags of span, from its usage.
+func definedFlags(t *testing.T) map[string]bool {
+	t.Helper()
+	_, usage, code := runSpan(t, "", "help")
+	if code != 0 {
+		t.Fatalf("span help exit code = %d, want 0", code)
+	}
+	flags := make(map[string]bool)
+	for _, m := range regexp.MustCompile(`(?m)^\s+(?:-\w, )?--([\w-]+)`).FindAllStringSubmatch(usage, -1) {
+		flags[m[1]] = true
+	}
+	return flags
+}
+
+func TestOperationTables(t *testing.T) {
+	flags := definedFlags(t)
+	for _, op := range operations {
+		if !flags[op.name] {
+			t.Errorf("operation %s is not a flag", op.name)
+		}
+	}
+	for name, scope := range operationOptions {
+		if !flags[name] {
+			t.Errorf("operationOptions lists %s, which is not a flag", name)
+		}
+		for _, op := range scope {
+			if !isOperation(op) {
+				t.Errorf("operationOptions scopes %s to %s, which is not an operation", name, op)
+			}
+		}
+	}
+	for name := range flagValues {
+		if !flags[name] {
+			t.Errorf("flagValues lists %s, which is not a flag", name)
+		}
+	}
+}
+
+func TestCheckScope(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"own option", []string{"gauge", "--warn", "1", "0", "10"}, 0},
+		{"global option", []string{"limit", "--format", "%.1f", "0", "10"}, 0},
+		{"option of another operation", []string{"spark", "--warn", "1"}, exitUsage},
+		{"option of another operation as a flag", []string{"--spark", "--warn", "1"}, 0},
+		{"unknown operation", []string{"help", "nope"}, exitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, code := runSpan(t, "5\n", tt.args...); code != tt.want {
+				t.Errorf("span %q exit code = %d, want %d", tt.args, code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// shells lists the shells "span completion" writes scripts for.
+var shells = []string{"bash", "zsh", "fish"}
+
+// writeCompletion writes the completion script for shell to w. The scripts are
+// generated from the operations and the flags, so they know the subcommands,
+// their arguments and the options specific to each one, and the values of the
+// options that take one of a list.
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w)
+	case "zsh":
+		writeZshCompletion(w)
+	case "fish":
+		writeFishCompletion(w)
+	default:
+		return fmt.Errorf("unknown shell: %s (expected %s)", shell, strings.Join(shells, ", "))
+	}
+	return nil
+}
+
+// completionFlags returns the flags offered with the subcommand op, or with no
+// subcommand when op is empty: all of them, or those of op and the global ones.
+func completionFlags(op string) []*flag.Flag {
+	var flags []*flag.Flag
+	flag.VisitAll(func(f *flag.Flag) {
+		scope, scoped := operationOptions[f.Name]
+		if op == "" || (!scoped && !isOperation(f.Name)) || slices.Contains(scope, op) {
+			flags = append(flags, f)
+		}
+	})
+	return flags
+}
+
+// flagWords returns the words completing the flags: --name, and -s for those
+// with a shorthand.
+func flagWords(flags []*flag.Flag) string {
+	var words []string
+	for _, f := range flags {
+		words = append(words, "--"+f.Name)
+		if f.Shorthand != "" {
+			words = append(words, "-"+f.Shorthand)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// takesValue reports whether f is given a value, rather than being a switch.
+func takesValue(f *flag.Flag) bool {
+	return f.Value.Type() != "bool"
+}
+
+// argValues returns the values the argument of the flag or operation name may
+// take, when it is one of a list.
+func argValues(name string) []string {
+	if op, ok := lookupOperation(name); ok {
+		return op.values
+	}
+	return flagValues[name]
+}
+
+func writeBashCompletion(w io.Writer) {
+	var names []string
+	for _, op := range operations {
+		names = append(names, op.name)
+	}
+
+	fmt.Fprint(w, `# bash completion for span. Load it with: source <(span completion bash)
+_span() {
+    local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}
+    case $prev in
+`)
+	flag.VisitAll(func(f *flag.Flag) {
+		values := argValues(f.Name)
+		switch {
+		case f.Name == "file":
+			fmt.Fprintf(w, "        --file) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n")
+		case len(values) > 0:
+			fmt.Fprintf(w, "        --%s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", f.Name, strings.Join(values, " "))
+		}
+	})
+	fmt.Fprintf(w, `    esac
+    if [[ $COMP_CWORD -eq 1 && $cur != -* ]]; then
+        COMPREPLY=($(compgen -W %q -- "$cur"))
+        return
+    fi
+    case ${COMP_WORDS[1]} in
+        help) [[ $COMP_CWORD -eq 2 ]] && COMPREPLY=($(compgen -W %q -- "$cur")); return ;;
+        completion) [[ $COMP_CWORD -eq 2 ]] && COMPREPLY=($(compgen -W %q -- "$cur")); return ;;
+        bench) COMPREPLY=($(compgen -W %q -- "$cur")); return ;;
+`, "help completion bench "+strings.Join(names, " "), strings.Join(names, " "), strings.Join(shells, " "), strings.Join(benchNames(), " "))
+	for _, op := range operations {
+		fmt.Fprintf(w, "        %s)\n", op.name)
+		if len(op.values) > 0 {
+			fmt.Fprintf(w, "            if [[ $COMP_CWORD -eq 2 && $cur != -* ]]; then\n")
+			fmt.Fprintf(w, "                COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(op.values, " "))
+			fmt.Fprintf(w, "                return\n")
+			fmt.Fprintf(w, "            fi\n")
+		}
+		fmt.Fprintf(w, "            COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", flagWords(completionFlags(op.name)))
+	}
+	fmt.Fprintf(w, `    esac
+    COMPREPLY=($(compgen -W %q -- "$cur"))
+}
+complete -F _span span
+`, flagWords(completionFlags("")))
+}
+
+// zshQuote escapes s for a single-quoted _arguments spec.
+func zshQuote(s string) string {
+	return strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`).Replace(s)
+}
+
+func writeZshCompletion(w io.Writer) {
+	fmt.Fprint(w, `#compdef span
+# zsh completion for span. Load it with: source <(span completion zsh)
+
+_span() {
+    local -a operations
+    operations=(
+        'help:Prints the usage of span or of an operation'
+        'completion:Writes a shell completion script'
+        'bench:Measures the values per second of the operations and I/O modes'
+`)
+	for _, op := range operations {
+		fmt.Fprintf(w, "        '%s:%s'\n", op.name, zshQuote(strings.TrimSpace(op.args+" "+flag.Lookup(op.name).Usage)))
+	}
+	fmt.Fprint(w, `    )
+    if (( CURRENT == 2 )) && [[ $words[2] != -* ]]; then
+        _describe operation operations
+        return
+    fi
+    case $words[2] in
+        help) (( CURRENT == 3 )) && _describe operation operations; return ;;
+`)
+	fmt.Fprintf(w, "        completion) (( CURRENT == 3 )) && compadd %s; return ;;\n", strings.Join(shells, " "))
+	fmt.Fprintf(w, "        bench) compadd %s; return ;;\n", strings.Join(benchNames(), " "))
+	for _, op := range operations {
+		if len(op.values) > 0 {
+			fmt.Fprintf(w, "        %s) (( CURRENT == 3 )) && [[ $words[3] != -* ]] && { compadd %s; return } ;;\n", op.name, strings.Join(op.values, " "))
+		}
+	}
+	fmt.Fprint(w, "    esac\n    _arguments -s \\\n")
+	for _, f := range completionFlags("") {
+		names := []string{"--" + f.Name}
+		if f.Shorthand != "" {
+			names = append(names, "-"+f.Shorthand)
+		}
+		for _, name := range names {
+			spec := name
+			if takesValue(f) {
+				spec += "="
+				if f.NoOptDefVal != "" {
+					spec += "-"
+				}
+			}
+			spec += "[" + zshQuote(f.Usage) + "]"
+			if takesValue(f) {
+				action := " "
+				if values := argValues(f.Name); len(values) > 0 {
+					action = "(" + strings.Join(values, " ") + ")"
+				} else if f.Name == "file" {
+					action = "_files"
+				}
+				spec += ":" + f.Name + ":" + action
+			}
+			fmt.Fprintf(w, "        '%s' \\\n", spec)
+		}
+	}
+	fmt.Fprint(w, `        '*: :'
+}
+
+compdef _span span
+`)
+}
+
+// fishQuote quotes s for fish, in single quotes.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprint(w, `# fish completion for span. Load it with: span completion fish | source
+complete -c span -f
+complete -c span -n __fish_use_subcommand -a help -d 'Prints the usage of span or of an operation'
+complete -c span -n __fish_use_subcommand -a completion -d 'Writes a shell completion script'
+complete -c span -n __fish_use_subcommand -a bench -d 'Measures the values per second of the operations and I/O modes'
+`)
+	var names []string
+	for _, op := range operations {
+		names = append(names, op.name)
+		fmt.Fprintf(w, "complete -c span -n __fish_use_subcommand -a %s -d %s\n", op.name, fishQuote(strings.TrimSpace(op.args+" "+flag.Lookup(op.name).Usage)))
+	}
+	fmt.Fprintf(w, "complete -c span -n '__fish_seen_subcommand_from help' -a %s\n", fishQuote(strings.Join(names, " ")))
+	fmt.Fprintf(w, "complete -c span -n '__fish_seen_subcommand_from completion' -a %s\n", fishQuote(strings.Join(shells, " ")))
+	fmt.Fprintf(w, "complete -c span -n '__fish_seen_subcommand_from bench' -a %s\n", fishQuote(strings.Join(benchNames(), " ")))
+	for _, op := range operations {
+		if len(op.values) > 0 {
+			fmt.Fprintf(w, "complete -c span -n '__fish_seen_subcommand_from %s; or __fish_contains_opt %s' -a %s\n", op.name, op.name, fishQuote(strings.Join(op.values, " ")))
+		}
+	}
+
+	flag.VisitAll(func(f *flag.Flag) {
+		line := "complete -c span -l " + f.Name
+		if f.Shorthand != "" {
+			line += " -s " + f.Shorthand
+		}
+		if takesValue(f) {
+			switch values := argValues(f.Name); {
+			case len(values) > 0:
+				line += " -x -a " + fishQuote(strings.Join(values, " "))
+			case f.Name == "file":
+				line += " -r -F"
+			case f.NoOptDefVal == "":
+				line += " -x"
+			}
+		}
+		fmt.Fprintln(w, line+" -d "+fishQuote(f.Usage))
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	for _, shell := range shells {
+		t.Run(shell, func(t *testing.T) {
+			script, stderr, code := runSpan(t, "", "completion", shell)
+			if code != 0 {
+				t.Fatalf("span completion %s exit code = %d, want 0 (stderr %q)", shell, code, stderr)
+			}
+			words := make(map[string]bool)
+			for _, word := range strings.FieldsFunc(script, func(r rune) bool { return strings.ContainsRune(" \t\n'\"()[]{}|,;=", r) }) {
+				name, _, _ := strings.Cut(word, ":") // zsh describes words as name:description.
+				words[word], words[name] = true, true
+			}
+			for _, op := range operations {
+				if !words[op.name] {
+					t.Errorf("the %s script does not complete the operation %s", shell, op.name)
+				}
+			}
+			for name, values := range flagValues {
+				for _, value := range values {
+					if !words[value] {
+						t.Errorf("the %s script does not complete %s for --%s", shell, value, name)
+					}
+				}
+			}
+			// The shell, when installed, checks the syntax of the script.
+			if path, err := exec.LookPath(shell); err == nil {
+				file := filepath.Join(t.TempDir(), "span."+shell)
+				if err := os.WriteFile(file, []byte(script), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				check := exec.Command(path, "-n", file)
+				if out, err := check.CombinedOutput(); err != nil {
+					t.Errorf("%s -n rejects the script: %v\n%s", shell, err, out)
+				}
+			}
+		})
+	}
+
+	if _, _, code := runSpan(t, "", "completion", "powershell"); code != exitUsage {
+		t.Errorf("span completion powershell exit code = %d, want %d", code, exitUsage)
+	}
+	if _, _, code := runSpan(t, "", "completion"); code != exitUsage {
+		t.Errorf("span completion exit code = %d, want %d", code, exitUsage)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// ANSI codes of the dashboard. The alternate screen buffer keeps the shell
+// history intact while the dashboard redraws the whole screen.
+const (
+	enterAltScreen = "\033[?1049h\033[?25l" // Also hides the cursor.
+	leaveAltScreen = "\033[?25h\033[?1049l"
+	clearScreen    = "\033[H\033[2J"
+	clearLine      = "\033[K" // Clears the rest of the line.
+)
+
+// formatLabel returns a formatter for the numbers printed next to charts,
+// following the output format options.
+func formatLabel(opts streamOptions) func(float64) string {
+	return func(val float64) string {
+		output, err := opts.formatValue(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return output
+	}
+}
+
+// runDashboard reads stdin and, when stdout is a terminal, redraws its columns
+// as labeled sparklines with statistics on the alternate screen, at most once
+// per refresh. When the input ends or the dashboard is interrupted, the last
+// frame is printed on the normal screen, so it stays in view.
+func runDashboard(opts streamOptions, config interval.SparkConfig, title string, refresh time.Duration) {
+	dashboard := interval.NewDashboard(config, formatLabel(opts))
+	stat, err := os.Stdout.Stat()
+	live := err == nil && stat.Mode()&os.ModeCharDevice != 0
+
+	frame := func() string {
+		width := columns()
+		if width == 0 {
+			width = 80
+		}
+		output := dashboard.Render(width)
+		if title != "" {
+			output = title + "\n" + output
+		}
+		return output
+	}
+
+	lines := make(chan string)
+	done := make(chan error, 1)
+	scanner := opts.newScanner(os.Stdin)
+	go func() {
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+		done <- scanner.Err()
+	}()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	if live {
+		stdout.WriteString(enterAltScreen)
+		flushOutput()
+	}
+	finish := func() {
+		if live {
+			stdout.WriteString(leaveAltScreen)
+		}
+		printLine(opts, frame())
+	}
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+	dirty := false
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				finish()
+				if err := <-done; err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading from input: %v\n", scanError(err))
+					exit(exitFailure)
+				}
+				return
+			}
+			dashboard.Add(line)
+			dirty = true
+		case <-ticker.C:
+			if live && dirty {
+				stdout.WriteString(clearScreen + frame())
+				flushOutput()
+				dirty = false
+			}
+		case <-interrupt:
+			finish()
+			return
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// emitter sends the output values over UDP, to a metrics backend or an OSC
+// receiver, as well as printing them.
+type emitter struct {
+	conn   net.Conn
+	encode func(label string, val float64) []byte // the datagram carrying val, printed under label
+	failed bool                                   // a send already failed and was reported
+}
+
+// statsdName matches the metric names accepted by StatsD: no separators of its
+// line protocol nor whitespace.
+func statsdName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, ":|@# \t\r\n")
+}
+
+// oscAddress reports whether address is a valid OSC address pattern to send to:
+// a path from /, without the characters OSC reserves for matching.
+func oscAddress(address string) bool {
+	return strings.HasPrefix(address, "/") && !strings.ContainsAny(address, " #*,?[]{}\x00")
+}
+
+// parseEmit parses the --emit target, as <protocol>:<host:port>, and connects to
+// it. metric names the values for statsd, and address is where they are sent for
+// osc.
+func parseEmit(spec, metric, address string) (*emitter, error) {
+	protocol, addr, ok := strings.Cut(spec, ":")
+	if !ok || addr == "" {
+		return nil, fmt.Errorf("invalid target '%s' (expected statsd:<host:port> or osc:<host:port>)", spec)
+	}
+	e := &emitter{}
+	switch protocol {
+	case "statsd":
+		if !statsdName(metric) {
+			return nil, fmt.Errorf("statsd requires a valid --metric name (got '%s')", metric)
+		}
+		e.encode = func(label string, val float64) []byte {
+			if label != "" && statsdName(label) {
+				return statsdGauge(metric+"."+label, val)
+			}
+			return statsdGauge(metric, val)
+		}
+	case "osc":
+		if !oscAddress(address) {
+			return nil, fmt.Errorf("osc requires a valid --address (got '%s')", address)
+		}
+		e.encode = func(label string, val float64) []byte {
+			if label != "" && oscAddress("/"+label) && !strings.Contains(label, "/") {
+				return oscMessage(address+"/"+label, val)
+			}
+			return oscMessage(address, val)
+		}
+	default:
+		return nil, fmt.Errorf("unknown protocol '%s' (expected statsd or osc)", protocol)
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	e.conn = conn
+	return e, nil
+}
+
+// statsdGauge returns the StatsD message setting the gauge name to val. As a
+// signed value is taken as a change of the gauge, a negative value is sent
+// after resetting the gauge to 0.
+func statsdGauge(name string, val float64) []byte {
+	msg := name + ":" + strconv.FormatFloat(val, 'f', -1, 64) + "|g"
+	if val < 0 {
+		msg = name + ":0|g\n" + msg
+	}
+	return []byte(msg)
+}
+
+// oscMessage returns the OSC message sending val to address, as a 32-bit float:
+// the address and the type tag string, NUL-terminated and padded to 4 bytes,
+// then the big-endian argument.
+func oscMessage(address string, val float64) []byte {
+	msg := oscString(nil, address)
+	msg = oscString(msg, ",f")
+	return binary.BigEndian.AppendUint32(msg, math.Float32bits(float32(val)))
+}
+
+// oscString appends s to msg as an OSC string.
+func oscString(msg []byte, s string) []byte {
+	msg = append(msg, s...)
+	return append(msg, make([]byte, 4-len(s)%4)...)
+}
+
+// send sends values, one datagram each. Values printed under a label, such as
+// the statistics of --stats, go to a metric or address of their own, suffixed
+// with the label, unless it is not a valid name. NaN and infinite values, which
+// the backends cannot take, are not sent. A failure is reported once and does
+// not stop the output: the values are still printed.
+func (e *emitter) send(label string, values ...float64) {
+	if e == nil {
+		return
+	}
+	for _, val := range values {
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			continue
+		}
+		if _, err := e.conn.Write(e.encode(label, val)); err != nil && !e.failed {
+			fmt.Fprintf(os.Stderr, "Warning: --emit: %v\n", err)
+			e.failed = true
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+// receiveDatagrams sends values through an emitter of protocol, with label, to a
+// local UDP socket, and returns the datagrams it receives.
+func receiveDatagrams(t *testing.T, protocol, metric, address, label string, values ...float64) [][]byte {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.ListenPacket() returned an unexpected error: %v", err)
+	}
+	defer conn.Close()
+	e, err := parseEmit(protocol+":"+conn.LocalAddr().String(), metric, address)
+	if err != nil {
+		t.Fatalf("parseEmit() returned an unexpected error: %v", err)
+	}
+	defer e.conn.Close()
+	e.send(label, values...)
+
+	var datagrams [][]byte
+	buf := make([]byte, 1024)
+	for {
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return datagrams
+		}
+		datagrams = append(datagrams, append([]byte(nil), buf[:n]...))
+	}
+}
+
+func TestParseEmit(t *testing.T) {
+	tests := []struct {
+		spec, metric, address string
+	}{
+		{"statsd", "m", "/a"},
+		{"statsd:", "m", "/a"},
+		{"graphite:localhost:2003", "m", "/a"},
+		{"statsd:localhost:8125", "", "/a"},
+		{"statsd:localhost:8125", "a:b", "/a"},
+		{"statsd:localhost:8125", "a b", "/a"},
+		{"osc:localhost:9000", "m", "a"},
+		{"osc:localhost:9000", "m", "/a b"},
+		{"osc:localhost:9000", "m", "/a*"},
+	}
+	for _, tt := range tests {
+		if _, err := parseEmit(tt.spec, tt.metric, tt.address); err == nil {
+			t.Errorf("parseEmit(%q, %q, %q) returned no error", tt.spec, tt.metric, tt.address)
+		}
+	}
+}
+
+func TestStatsdGauge(t *testing.T) {
+	tests := []struct {
+		val  float64
+		want string
+	}{
+		{1.5, "span.latency:1.5|g"},
+		{0, "span.latency:0|g"},
+		{1e21, "span.latency:1000000000000000000000|g"},
+		{0.000001, "span.latency:0.000001|g"},
+		{-2, "span.latency:0|g\nspan.latency:-2|g"},
+	}
+	for _, tt := range tests {
+		if got := string(statsdGauge("span.latency", tt.val)); got != tt.want {
+			t.Errorf("statsdGauge(%v) = %q, want %q", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestEmitStatsd(t *testing.T) {
+	tests := []struct {
+		name   string
+		label  string
+		values []float64
+		want   []string
+	}{
+		{"values", "", []float64{1.5, math.NaN(), -2, math.Inf(1)}, []string{"span.latency:1.5|g", "span.latency:0|g\nspan.latency:-2|g"}},
+		{"label", "p99", []float64{42}, []string{"span.latency.p99:42|g"}},
+		{"invalid label", "a|b", []float64{42}, []string{"span.latency:42|g"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			datagrams := receiveDatagrams(t, "statsd", "span.latency", "", tt.label, tt.values...)
+			if len(datagrams) != len(tt.want) {
+				t.Fatalf("received %q, want %q", datagrams, tt.want)
+			}
+			for i, d := range datagrams {
+				if string(d) != tt.want[i] {
+					t.Errorf("datagram %d = %q, want %q", i, d, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOSCMessage(t *testing.T) {
+	tests := []struct {
+		address string
+		val     float64
+		want    []byte
+	}{
+		{"/span/value", 1.5, []byte("/span/value\x00,f\x00\x00\x3f\xc0\x00\x00")},
+		// An address of a multiple of 4 bytes is still terminated, by 4 NULs.
+		{"/abc", -2, []byte("/abc\x00\x00\x00\x00,f\x00\x00\xc0\x00\x00\x00")},
+		{"/ab", 0.1, []byte("/ab\x00,f\x00\x00\x3d\xcc\xcc\xcd")},
+	}
+	for _, tt := range tests {
+		got := oscMessage(tt.address, tt.val)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("oscMessage(%q, %v) = %q, want %q", tt.address, tt.val, got, tt.want)
+		}
+		if len(got)%4 != 0 {
+			t.Errorf("oscMessage(%q, %v) has %d bytes, want a multiple of 4", tt.address, tt.val, len(got))
+		}
+	}
+}
+
+func TestEmitOSC(t *testing.T) {
+	tests := []struct {
+		name   string
+		label  string
+		values []float64
+		want   [][]byte
+	}{
+		{"values", "", []float64{1.5, math.NaN(), math.Inf(-1)}, [][]byte{[]byte("/span\x00\x00\x00,f\x00\x00\x3f\xc0\x00\x00")}},
+		{"label", "max", []float64{1}, [][]byte{[]byte("/span/max\x00\x00\x00,f\x00\x00\x3f\x80\x00\x00")}},
+		{"invalid label", "a/b", []float64{1}, [][]byte{[]byte("/span\x00\x00\x00,f\x00\x00\x3f\x80\x00\x00")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			datagrams := receiveDatagrams(t, "osc", "", "/span", tt.label, tt.values...)
+			if len(datagrams) != len(tt.want) {
+				t.Fatalf("received %q, want %q", datagrams, tt.want)
+			}
+			for i, d := range datagrams {
+				if !bytes.Equal(d, tt.want[i]) {
+					t.Errorf("datagram %d = %q, want %q", i, d, tt.want[i])
+				}
+			}
+		})
+	}
+}
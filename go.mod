@@ -2,4 +2,4 @@ module github.com/gregory-chatelier/span
 
 go 1.24.3
 
-require github.com/spf13/pflag v1.0.10 // indirect
+require github.com/spf13/pflag v1.0.10
@@ -0,0 +1,66 @@
+package interval
+
+import "fmt"
+
+// The batch functions apply an operation to every value of src and store the
+// results in dst, which must be at least as long and may be src itself. They
+// spare callers processing values in bulk a loop of their own, and behave as
+// the function they batch on each value. Those that can fail stop at the first
+// value they fail on, with an error that wraps the error of the function and
+// names the index of the value ("value 3: ..."); the values before it are
+// stored.
+
+// RemapSlice remaps the values of src from [srcA, srcB] to [dstA, dstB].
+func RemapSlice(dst, src []float64, srcA, srcB, dstA, dstB float64) error {
+	dst = dst[:len(src)]
+	for i, val := range src {
+		r, err := Remap(val, srcA, srcB, dstA, dstB)
+		if err != nil {
+			return fmt.Errorf("value %d: %w", i, err)
+		}
+		dst[i] = r
+	}
+	return nil
+}
+
+// EvalSlice evaluates the parameters of src within [a, b].
+func EvalSlice(dst, src []float64, a, b float64) {
+	dst = dst[:len(src)]
+	for i, t := range src {
+		dst[i] = Eval(t, a, b)
+	}
+}
+
+// DevalSlice returns the parameters of the values of src within [a, b].
+func DevalSlice(dst, src []float64, a, b float64) error {
+	dst = dst[:len(src)]
+	for i, val := range src {
+		t, err := Deval(val, a, b)
+		if err != nil {
+			return fmt.Errorf("value %d: %w", i, err)
+		}
+		dst[i] = t
+	}
+	return nil
+}
+
+// LimitSlice clamps the values of src to [min, max].
+func LimitSlice(dst, src []float64, min, max float64) {
+	dst = dst[:len(src)]
+	for i, val := range src {
+		dst[i] = Limit(val, min, max)
+	}
+}
+
+// SnapSlice snaps the values of src to the grid of steps over [a, b].
+func SnapSlice(dst, src []float64, steps int, a, b float64) error {
+	dst = dst[:len(src)]
+	for i, val := range src {
+		r, err := Snap(val, steps, a, b)
+		if err != nil {
+			return fmt.Errorf("value %d: %w", i, err)
+		}
+		dst[i] = r
+	}
+	return nil
+}
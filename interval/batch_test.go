@@ -0,0 +1,55 @@
+package interval
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	src := []float64{0, 2.5, 7.8, 10}
+	testCases := []struct {
+		name  string
+		apply func(dst, src []float64) error
+		want  []float64
+	}{
+		{"RemapSlice", func(dst, src []float64) error { return RemapSlice(dst, src, 0, 10, 100, 200) }, []float64{100, 125, 178, 200}},
+		{"EvalSlice", func(dst, src []float64) error { EvalSlice(dst, src, 0, -2); return nil }, []float64{0, -5, -15.6, -20}},
+		{"DevalSlice", func(dst, src []float64) error { return DevalSlice(dst, src, 0, 20) }, []float64{0, 0.125, 0.39, 0.5}},
+		{"LimitSlice", func(dst, src []float64) error { LimitSlice(dst, src, 8, 1); return nil }, []float64{1, 2.5, 7.8, 8}},
+		{"SnapSlice", func(dst, src []float64) error { return SnapSlice(dst, src, 2, 0, 10) }, []float64{0, 5, 10, 10}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := make([]float64, len(src))
+			if err := tc.apply(dst, src); err != nil {
+				t.Fatalf("%s() returned an unexpected error: %v", tc.name, err)
+			}
+			if !slicesAlmostEqual(dst, tc.want) {
+				t.Errorf("%s() = %v, want %v", tc.name, dst, tc.want)
+			}
+		})
+	}
+
+	t.Run("in place", func(t *testing.T) {
+		values := []float64{1, 2, 3}
+		EvalSlice(values, values, 10, 20)
+		if want := []float64{20, 30, 40}; !reflect.DeepEqual(values, want) {
+			t.Errorf("EvalSlice() in place = %v, want %v", values, want)
+		}
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		values := []float64{1, math.NaN(), 3}
+		err := RemapSlice(values, values, 0, 1, 0, 10)
+		if err == nil || err.Error() != "value 1: cannot remap: NaN values are not supported" {
+			t.Errorf("RemapSlice() error = %v, want one for value 1", err)
+		}
+		if values[0] != 10 || values[2] != 3 {
+			t.Errorf("RemapSlice() stored %v, want only the values before the error", values)
+		}
+		if err := SnapSlice(values, []float64{1}, 0, 0, 1); err == nil {
+			t.Error("SnapSlice() expected an error for zero steps, but got nil")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package interval
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// BoxPlot holds the statistics drawn by a box plot: the quartiles, the
+// whiskers, which reach the farthest values within Tukey's fences, and the
+// values beyond them.
+type BoxPlot struct {
+	LowerWhisker, Q1, Median, Q3, UpperWhisker float64
+	Outliers                                   []float64
+}
+
+// NewBoxPlot computes the box plot of values, with fences k times the
+// interquartile range away from the quartiles (conventionally 1.5). NaN
+// values are ignored.
+func NewBoxPlot(values []float64, k float64) (BoxPlot, error) {
+	fence, err := IQRFence(values, k)
+	if err != nil {
+		return BoxPlot{}, err
+	}
+	summary := Describe(values)
+	box := BoxPlot{
+		LowerWhisker: math.Inf(1),
+		Q1:           summary.P25,
+		Median:       summary.Median,
+		Q3:           summary.P75,
+		UpperWhisker: math.Inf(-1),
+	}
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+		case fence.IsOutlier(v):
+			box.Outliers = append(box.Outliers, v)
+		default:
+			box.LowerWhisker = math.Min(box.LowerWhisker, v)
+			box.UpperWhisker = math.Max(box.UpperWhisker, v)
+		}
+	}
+	sort.Float64s(box.Outliers)
+	return box, nil
+}
+
+// Render draws the box plot on a line of width characters spanning [min, max],
+// as in "|---[==|====]-----|  o": the whiskers end with '|', the box spans the
+// quartiles between '[' and ']' with the median as '|', and each outlier is
+// an 'o'. Values outside of [min, max] are drawn at its edges.
+func (b BoxPlot) Render(width int, min, max float64) string {
+	if width < 1 {
+		width = 1
+	}
+	// column returns the position of val on the line.
+	column := func(val float64) int {
+		if max <= min {
+			return 0
+		}
+		pos, _ := Remap(val, min, max, 0, float64(width-1))
+		return int(math.Round(Limit(pos, 0, float64(width-1))))
+	}
+	line := []rune(strings.Repeat(" ", width))
+	fill := func(from, to int, r rune) {
+		for i := from; i <= to; i++ {
+			line[i] = r
+		}
+	}
+
+	lower, q1, median, q3, upper := column(b.LowerWhisker), column(b.Q1), column(b.Median), column(b.Q3), column(b.UpperWhisker)
+	fill(lower, upper, '-')
+	fill(q1, q3, '=')
+	line[lower], line[upper] = '|', '|'
+	line[q1], line[q3] = '[', ']'
+	line[median] = '|'
+	for _, v := range b.Outliers {
+		line[column(v)] = 'o'
+	}
+	return string(line)
+}
@@ -0,0 +1,85 @@
+package interval
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewBoxPlot(t *testing.T) {
+	got, err := NewBoxPlot([]float64{20, 1, 2, 3, 4, 5, 6, 7, 8, 9}, 1.5)
+	if err != nil {
+		t.Fatalf("NewBoxPlot() returned an unexpected error: %v", err)
+	}
+	want := BoxPlot{LowerWhisker: 1, Q1: 3.25, Median: 5.5, Q3: 7.75, UpperWhisker: 9, Outliers: []float64{20}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBoxPlot() = %+v, want %+v", got, want)
+	}
+
+	if _, err := NewBoxPlot(nil, 1.5); !errors.Is(err, ErrNoValues) {
+		t.Errorf("NewBoxPlot(nil) error = %v, want %v", err, ErrNoValues)
+	}
+}
+
+func TestBoxPlotRender(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []float64
+		width    int
+		min, max float64
+		want     string
+	}{
+		{
+			name:   "Whiskers and box",
+			values: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			width:  9,
+			min:    1,
+			max:    9,
+			want:   "|-[=|=]-|",
+		},
+		{
+			name:   "Outlier",
+			values: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 20},
+			width:  20,
+			min:    1,
+			max:    20,
+			want:   "|-[==|=]|          o",
+		},
+		{
+			name:   "Wider interval",
+			values: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			width:  11,
+			min:    0,
+			max:    10,
+			want:   " |-[=|=]-| ",
+		},
+		{
+			name:   "Clamped to the interval",
+			values: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9},
+			width:  5,
+			min:    3,
+			max:    7,
+			want:   "[=|=]",
+		},
+		{
+			name:   "Constant values",
+			values: []float64{4, 4, 4},
+			width:  3,
+			min:    4,
+			max:    4,
+			want:   "|  ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			box, err := NewBoxPlot(tc.values, 1.5)
+			if err != nil {
+				t.Fatalf("NewBoxPlot() returned an unexpected error: %v", err)
+			}
+			if got := box.Render(tc.width, tc.min, tc.max); got != tc.want {
+				t.Errorf("Render()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
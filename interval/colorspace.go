@@ -0,0 +1,143 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ColorSpace is a space colors are interpolated in.
+type ColorSpace string
+
+const (
+	// SpaceSRGB interpolates the sRGB components, as Gradient does. It is the
+	// cheapest, but the middle of two saturated colors can look dull.
+	SpaceSRGB ColorSpace = "srgb"
+	// SpaceHSL interpolates hue, saturation and lightness, the hue along the
+	// shorter way around the color wheel, so the colors in between stay vivid.
+	SpaceHSL ColorSpace = "hsl"
+	// SpaceOKLab interpolates in the OKLab perceptual space, so the steps look
+	// evenly spaced in lightness and tint.
+	SpaceOKLab ColorSpace = "oklab"
+)
+
+// ParseColorSpace translates a string name into a ColorSpace.
+func ParseColorSpace(s string) (ColorSpace, error) {
+	switch space := ColorSpace(strings.ToLower(s)); space {
+	case SpaceSRGB, SpaceHSL, SpaceOKLab:
+		return space, nil
+	case "":
+		return SpaceSRGB, nil
+	}
+	return SpaceSRGB, fmt.Errorf("unknown color space: %s (expected srgb, hsl or oklab)", s)
+}
+
+// LerpColor returns the color at parameter t between from and to, interpolated
+// in space: Eval applied to colors. t is clamped to [0, 1]; NaN gives from.
+func LerpColor(from, to RGB, t float64, space ColorSpace) RGB {
+	if math.IsNaN(t) {
+		return from
+	}
+	t = Limit(t, 0, 1)
+	switch space {
+	case SpaceHSL:
+		h1, s1, l1 := from.hsl()
+		h2, s2, l2 := to.hsl()
+		// A gray has no hue: keep the hue of the other color.
+		if s1 == 0 {
+			h1 = h2
+		} else if s2 == 0 {
+			h2 = h1
+		}
+		if h2-h1 > 180 {
+			h1 += 360
+		} else if h1-h2 > 180 {
+			h2 += 360
+		}
+		return hslToRGB(math.Mod(Eval(t, h1, h2), 360), Eval(t, s1, s2), Eval(t, l1, l2))
+	case SpaceOKLab:
+		L1, a1, b1 := from.oklab()
+		L2, a2, b2 := to.oklab()
+		return oklabToRGB(Eval(t, L1, L2), Eval(t, a1, a2), Eval(t, b1, b2))
+	}
+	lerp := func(a, b uint8) uint8 {
+		return uint8(math.Round(Eval(t, float64(a), float64(b))))
+	}
+	return RGB{lerp(from.R, to.R), lerp(from.G, to.G), lerp(from.B, to.B)}
+}
+
+// channel converts a component in [0, 1] to 8 bits, clamping it.
+func channel(v float64) uint8 {
+	return uint8(math.Round(Limit(v, 0, 1) * 255))
+}
+
+// hsl returns the hue (0-360), saturation and lightness (0-1) of the color.
+func (c RGB) hsl() (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := max(r, g, b), min(r, g, b)
+	l = (hi + lo) / 2
+	d := hi - lo
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d+6, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h * 60, s, l
+}
+
+// hslToRGB returns the color of a hue (0-360), saturation and lightness (0-1).
+func hslToRGB(h, s, l float64) RGB {
+	k := func(n float64) float64 {
+		k := math.Mod(n+h/30, 12)
+		a := s * min(l, 1-l)
+		return l - a*max(-1, min(k-3, 9-k, 1))
+	}
+	return RGB{channel(k(0)), channel(k(8)), channel(k(4))}
+}
+
+// toLinear decodes an sRGB component to linear light.
+func toLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// fromLinear encodes a linear light component to sRGB, in [0, 1].
+func fromLinear(c float64) float64 {
+	if c <= 0.0031308 {
+		return 12.92 * c
+	}
+	return 1.055*math.Pow(c, 1/2.4) - 0.055
+}
+
+// oklab returns the OKLab coordinates of the color.
+func (c RGB) oklab() (L, a, b float64) {
+	r, g, bl := toLinear(c.R), toLinear(c.G), toLinear(c.B)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+	return 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+}
+
+// oklabToRGB returns the color of OKLab coordinates, clamped to the sRGB gamut.
+func oklabToRGB(L, a, b float64) RGB {
+	l := math.Pow(L+0.3963377774*a+0.2158037573*b, 3)
+	m := math.Pow(L-0.1055613458*a-0.0638541728*b, 3)
+	s := math.Pow(L-0.0894841775*a-1.2914855480*b, 3)
+	return RGB{
+		channel(fromLinear(+4.0767416621*l - 3.3077115913*m + 0.2309699292*s)),
+		channel(fromLinear(-1.2684380046*l + 2.6097574011*m - 0.3413193965*s)),
+		channel(fromLinear(-0.0041960863*l - 0.7034186147*m + 1.7076147010*s)),
+	}
+}
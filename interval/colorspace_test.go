@@ -0,0 +1,47 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLerpColor(t *testing.T) {
+	red, blue := RGB{255, 0, 0}, RGB{0, 0, 255}
+	black, white := RGB{0, 0, 0}, RGB{255, 255, 255}
+	gray := RGB{128, 128, 128}
+	tests := []struct {
+		name     string
+		from, to RGB
+		t        float64
+		space    ColorSpace
+		want     RGB
+	}{
+		{"srgb midpoint", red, blue, 0.5, SpaceSRGB, RGB{128, 0, 128}},
+		{"srgb clamped", red, blue, 2, SpaceSRGB, blue},
+		{"hsl shorter hue", red, blue, 0.5, SpaceHSL, RGB{255, 0, 255}},
+		{"hsl from a gray", gray, red, 0.5, SpaceHSL, RGB{191, 64, 64}},
+		{"hsl end", red, blue, 1, SpaceHSL, blue},
+		{"oklab start", red, blue, 0, SpaceOKLab, red},
+		{"oklab end", red, blue, 1, SpaceOKLab, blue},
+		{"oklab gray", black, white, 0.5, SpaceOKLab, RGB{99, 99, 99}},
+		{"NaN", red, blue, math.NaN(), SpaceOKLab, red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LerpColor(tt.from, tt.to, tt.t, tt.space); got != tt.want {
+				t.Errorf("LerpColor(%v, %v, %v, %s) = %v, want %v", tt.from, tt.to, tt.t, tt.space, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseColorSpace(t *testing.T) {
+	for input, want := range map[string]ColorSpace{"": SpaceSRGB, "sRGB": SpaceSRGB, "hsl": SpaceHSL, "OKLab": SpaceOKLab} {
+		if got, err := ParseColorSpace(input); err != nil || got != want {
+			t.Errorf("ParseColorSpace(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseColorSpace("lab"); err == nil {
+		t.Error("ParseColorSpace(\"lab\") expected an error, but got nil")
+	}
+}
@@ -0,0 +1,120 @@
+package interval
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dashboardHistory is the number of recent values a dashboard keeps per column,
+// enough to fill the width of any terminal.
+const dashboardHistory = 1024
+
+// Dashboard accumulates the columns of a stream and renders each one as a
+// labeled sparkline of its recent values followed by its statistics, for a
+// terminal monitor. Lines are read as GenerateSeries reads them: a first line
+// without numbers names the columns.
+type Dashboard struct {
+	config  SparkConfig
+	format  func(float64) string
+	names   []string
+	columns []*dashboardColumn
+	started bool
+}
+
+type dashboardColumn struct {
+	recent *circularBuffer
+	seen   RunningRange
+	sum    kahanSum
+	last   float64
+}
+
+// NewDashboard returns an empty dashboard whose sparklines are drawn with the
+// characters and colors of config, and whose statistics are formatted with
+// format. The sliding window, labels and layout options of config are ignored.
+func NewDashboard(config SparkConfig, format func(float64) string) *Dashboard {
+	config.Width, config.Height, config.HasBaseline = 0, 0, false
+	config.Labels, config.Label, config.Fit = nil, "", 0
+	config.Style = StyleBlock
+	return &Dashboard{config: config, format: format}
+}
+
+// Add records the values of a line of input.
+func (d *Dashboard) Add(line string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+	if !d.started {
+		d.started = true
+		if isHeader(fields) {
+			d.names = fields
+			return
+		}
+	}
+	for i, field := range fields {
+		val, err := ParseHuman(field)
+		if err != nil {
+			continue
+		}
+		for len(d.columns) <= i {
+			d.columns = append(d.columns, &dashboardColumn{recent: newCircularBuffer(dashboardHistory)})
+		}
+		c := d.columns[i]
+		c.recent.Add(val)
+		c.seen.Add(val)
+		c.sum.add(val)
+		c.last = val
+	}
+}
+
+// Render draws one line per column, at most width characters wide: its name,
+// a sparkline of its latest values, and the last, min, max and mean of every
+// value seen, with their count. Sparklines and statistics are aligned across
+// the lines.
+func (d *Dashboard) Render(width int) string {
+	nameWidth := 0
+	for _, name := range d.names {
+		if n := len([]rune(name)); n > nameWidth {
+			nameWidth = n
+		}
+	}
+	stats := make([]string, len(d.columns))
+	statsWidth := 0
+	for i, c := range d.columns {
+		stats[i] = fmt.Sprintf("last %s  min %s  max %s  mean %s  n %d", d.format(c.last),
+			d.format(c.seen.Min), d.format(c.seen.Max), d.format(c.sum.value()/float64(c.seen.Count)), c.seen.Count)
+		if n := len([]rune(stats[i])); n > statsWidth {
+			statsWidth = n
+		}
+	}
+
+	cells := width - statsWidth - 2
+	if nameWidth > 0 {
+		cells -= nameWidth + 1
+	}
+	if cells < 1 {
+		cells = 1
+	}
+
+	lines := make([]string, len(d.columns))
+	for i, c := range d.columns {
+		values := c.recent.GetAll()
+		if len(values) > cells {
+			values = values[len(values)-cells:]
+		}
+		min, max := c.seen.Min, c.seen.Max
+		if d.config.HasMin {
+			min, max = d.config.Min, d.config.Max
+		}
+		line := renderFrame(values, min, max, d.config) + strings.Repeat(" ", cells-len(values)) + "  " + stats[i]
+		if nameWidth > 0 {
+			name := ""
+			if i < len(d.names) {
+				name = d.names[i]
+			}
+			line = name + strings.Repeat(" ", nameWidth-len([]rune(name))) + " " + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,34 @@
+package interval
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDashboard(t *testing.T) {
+	format := func(val float64) string { return fmt.Sprintf("%g", val) }
+
+	d := NewDashboard(SparkConfig{}, format)
+	for _, line := range []string{"cpu mem", "1 10", "", "3 x", "5 30"} {
+		d.Add(line)
+	}
+	want := "cpu  ▄█  last 5  min 1  max 5  mean 3  n 3\n" +
+		"mem  █   last 30  min 10  max 30  mean 20  n 2"
+	if got := d.Render(46); got != want {
+		t.Errorf("Render()\n  got: %q\n want: %q", got, want)
+	}
+}
+
+func TestDashboardNarrow(t *testing.T) {
+	format := func(val float64) string { return fmt.Sprintf("%g", val) }
+
+	d := NewDashboard(SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true}, format)
+	for _, line := range []string{"0", "8", "4"} {
+		d.Add(line)
+	}
+	// Only the latest values that fit are drawn, on the fixed interval.
+	want := "█▄  last 4  min 0  max 8  mean 4  n 3"
+	if got := d.Render(37); got != want {
+		t.Errorf("Render()\n  got: %q\n want: %q", got, want)
+	}
+}
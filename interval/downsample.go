@@ -0,0 +1,87 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+)
+
+// LTTB downsamples a series to n values using the Largest-Triangle-Three-Buckets
+// algorithm, treating each value's index as its x coordinate. The first and last
+// values are always kept, and the values in between are chosen to preserve the
+// visual shape of the series, including its peaks.
+func LTTB(values []float64, n int) ([]float64, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("target count cannot be negative")
+	}
+	if n >= len(values) {
+		return append([]float64(nil), values...), nil
+	}
+	switch n {
+	case 0:
+		return []float64{}, nil
+	case 1:
+		return []float64{values[0]}, nil
+	case 2:
+		return []float64{values[0], values[len(values)-1]}, nil
+	}
+
+	results := make([]float64, 0, n)
+	results = append(results, values[0])
+
+	// The values between the first and last are split into n-2 buckets.
+	bucketSize := float64(len(values)-2) / float64(n-2)
+	selected := 0
+	for i := 0; i < n-2; i++ {
+		start := int(math.Floor(float64(i)*bucketSize)) + 1
+		end := int(math.Floor(float64(i+1)*bucketSize)) + 1
+
+		// The average point of the next bucket is the third triangle vertex.
+		nextStart, nextEnd := end, int(math.Floor(float64(i+2)*bucketSize))+1
+		if nextEnd > len(values) || i == n-3 {
+			nextStart, nextEnd = len(values)-1, len(values)
+		}
+		avgX, avgY := 0.0, 0.0
+		for j := nextStart; j < nextEnd; j++ {
+			avgX += float64(j)
+			avgY += values[j]
+		}
+		avgX /= float64(nextEnd - nextStart)
+		avgY /= float64(nextEnd - nextStart)
+
+		// Keep the point forming the largest triangle with the previously
+		// selected point and the next bucket's average.
+		ax, ay := float64(selected), values[selected]
+		maxArea, best := -1.0, start
+		for j := start; j < end; j++ {
+			area := math.Abs((ax-avgX)*(values[j]-ay) - (ax-float64(j))*(avgY-ay))
+			if area > maxArea {
+				maxArea, best = area, j
+			}
+		}
+		results = append(results, values[best])
+		selected = best
+	}
+
+	return append(results, values[len(values)-1]), nil
+}
+
+// DownsampleUniform downsamples a series to n values picked at evenly spaced
+// indices, always including the first and last values.
+func DownsampleUniform(values []float64, n int) ([]float64, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("target count cannot be negative")
+	}
+	if n >= len(values) {
+		return append([]float64(nil), values...), nil
+	}
+	if n == 1 {
+		return []float64{values[0]}, nil
+	}
+
+	results := make([]float64, n)
+	for i := range results {
+		idx := int(math.Round(float64(i) * float64(len(values)-1) / float64(n-1)))
+		results[i] = values[idx]
+	}
+	return results, nil
+}
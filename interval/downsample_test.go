@@ -0,0 +1,82 @@
+package interval
+
+import (
+	"testing"
+)
+
+func TestLTTB(t *testing.T) {
+	// A flat series with a single spike that uniform sampling would miss.
+	spiky := make([]float64, 100)
+	spiky[37] = 50
+
+	tests := []struct {
+		name    string
+		values  []float64
+		n       int
+		want    []float64
+		wantErr bool
+	}{
+		{"fewer values than target", []float64{1, 2, 3}, 5, []float64{1, 2, 3}, false},
+		{"zero target", []float64{1, 2, 3}, 0, []float64{}, false},
+		{"one value", []float64{1, 2, 3}, 1, []float64{1}, false},
+		{"end points", []float64{1, 2, 3}, 2, []float64{1, 3}, false},
+		{"keeps peaks", []float64{0, 1, 9, 1, 0, -1, -9, -1, 0}, 4, []float64{0, 9, -9, 0}, false},
+		{"negative target", []float64{1, 2, 3}, -1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LTTB(tt.values, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LTTB() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("LTTB() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("keeps a lone spike", func(t *testing.T) {
+		got, _ := LTTB(spiky, 10)
+		if len(got) != 10 {
+			t.Fatalf("LTTB() len = %v, want 10", len(got))
+		}
+		found := false
+		for _, v := range got {
+			found = found || v == 50
+		}
+		if !found {
+			t.Errorf("LTTB() = %v, want the spike to be kept", got)
+		}
+	})
+}
+
+func TestDownsampleUniform(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		n       int
+		want    []float64
+		wantErr bool
+	}{
+		{"evenly spaced", []float64{0, 1, 2, 3, 4, 5, 6, 7, 8}, 3, []float64{0, 4, 8}, false},
+		{"fewer values than target", []float64{1, 2}, 5, []float64{1, 2}, false},
+		{"one value", []float64{1, 2, 3}, 1, []float64{1}, false},
+		{"zero target", []float64{1, 2, 3}, 0, []float64{}, false},
+		{"negative target", []float64{1, 2, 3}, -1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DownsampleUniform(tt.values, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DownsampleUniform() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("DownsampleUniform() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
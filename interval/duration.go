@@ -0,0 +1,48 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a duration such as "150ms", "2.5s" or "1h30m" and returns
+// it as a number of units (e.g. seconds when unit is time.Second). Plain numbers
+// are taken to be in that unit already.
+func ParseDuration(s string, unit time.Duration) (float64, error) {
+	if unit <= 0 {
+		return 0, fmt.Errorf("unit must be a positive duration")
+	}
+	s = strings.TrimSpace(s)
+	if val, err := strconv.ParseFloat(s, 64); err == nil {
+		return val, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %q", s)
+	}
+	return float64(d) / float64(unit), nil
+}
+
+// FormatDuration renders a number of units as a duration string (e.g. "1m30s").
+// The result is rounded to the nearest nanosecond.
+func FormatDuration(val float64, unit time.Duration) (string, error) {
+	ns := val * float64(unit)
+	if math.IsNaN(ns) || math.IsInf(ns, 0) || math.Abs(ns) > math.MaxInt64 {
+		return "", fmt.Errorf("cannot render %g as a duration", val)
+	}
+	return time.Duration(math.Round(ns)).String(), nil
+}
+
+// ParseUnit parses a duration unit name: "ns", "us" (or "µs"), "ms", "s", "m" or "h".
+func ParseUnit(s string) (time.Duration, error) {
+	switch s {
+	case "ns", "us", "µs", "ms", "s", "m", "h":
+		d, _ := time.ParseDuration("1" + s)
+		return d, nil
+	default:
+		return 0, fmt.Errorf("unknown unit: %s (expected ns, us, ms, s, m, or h)", s)
+	}
+}
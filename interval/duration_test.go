@@ -0,0 +1,75 @@
+package interval
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		unit    time.Duration
+		want    float64
+		wantErr bool
+	}{
+		{"150ms", time.Second, 0.15, false},
+		{"2.5s", time.Second, 2.5, false},
+		{"3m", time.Second, 180, false},
+		{"1h30m", time.Minute, 90, false},
+		{"2.5s", time.Millisecond, 2500, false},
+		{"-1s", time.Second, -1, false},
+		{"42", time.Second, 42, false},
+		{"3 days", time.Second, 0, true},
+		{"1s", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input, tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDuration(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !almostEqual(got, tt.want) {
+				t.Errorf("ParseDuration(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		val     float64
+		unit    time.Duration
+		want    string
+		wantErr bool
+	}{
+		{90, time.Second, "1m30s", false},
+		{0.15, time.Second, "150ms", false},
+		{2500, time.Millisecond, "2.5s", false},
+		{0, time.Second, "0s", false},
+		{math.NaN(), time.Second, "", true},
+		{1e300, time.Second, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := FormatDuration(tt.val, tt.unit)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FormatDuration(%v) error = %v, wantErr %v", tt.val, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	if d, err := ParseUnit("ms"); err != nil || d != time.Millisecond {
+		t.Errorf("ParseUnit(\"ms\") = %v, %v, want 1ms", d, err)
+	}
+	if _, err := ParseUnit("d"); err == nil {
+		t.Error("ParseUnit(\"d\") expected an error, but got nil")
+	}
+}
@@ -0,0 +1,104 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// EaseFunc maps a parameter 't' in [0, 1] to an eased parameter, also in [0, 1]
+// at the end points (some curves may overshoot in between).
+type EaseFunc func(t float64) float64
+
+// easings holds the named easing curves.
+var easings = map[string]EaseFunc{
+	"linear":       func(t float64) float64 { return t },
+	"inQuad":       func(t float64) float64 { return t * t },
+	"outQuad":      func(t float64) float64 { return 1 - (1-t)*(1-t) },
+	"inOutQuad":    inOut(func(t float64) float64 { return t * t }),
+	"inCubic":      func(t float64) float64 { return t * t * t },
+	"outCubic":     func(t float64) float64 { return 1 - math.Pow(1-t, 3) },
+	"inOutCubic":   inOut(func(t float64) float64 { return t * t * t }),
+	"inQuart":      func(t float64) float64 { return math.Pow(t, 4) },
+	"outQuart":     func(t float64) float64 { return 1 - math.Pow(1-t, 4) },
+	"inOutQuart":   inOut(func(t float64) float64 { return math.Pow(t, 4) }),
+	"inQuint":      func(t float64) float64 { return math.Pow(t, 5) },
+	"outQuint":     func(t float64) float64 { return 1 - math.Pow(1-t, 5) },
+	"inOutQuint":   inOut(func(t float64) float64 { return math.Pow(t, 5) }),
+	"inSine":       func(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) },
+	"outSine":      func(t float64) float64 { return math.Sin(t * math.Pi / 2) },
+	"inOutSine":    func(t float64) float64 { return -(math.Cos(math.Pi*t) - 1) / 2 },
+	"inExpo":       easeInExpo,
+	"outExpo":      func(t float64) float64 { return 1 - easeInExpo(1-t) },
+	"inOutExpo":    inOut(easeInExpo),
+	"inCirc":       func(t float64) float64 { return 1 - math.Sqrt(1-t*t) },
+	"outCirc":      func(t float64) float64 { return math.Sqrt(1 - (t-1)*(t-1)) },
+	"inOutCirc":    inOut(func(t float64) float64 { return 1 - math.Sqrt(1-t*t) }),
+	"smoothstep":   func(t float64) float64 { return t * t * (3 - 2*t) },
+	"smootherstep": func(t float64) float64 { return t * t * t * (t*(t*6-15) + 10) },
+}
+
+func easeInExpo(t float64) float64 {
+	if t == 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+// inOut builds a symmetric in-out curve from an "in" curve.
+func inOut(in EaseFunc) EaseFunc {
+	return func(t float64) float64 {
+		if t < 0.5 {
+			return in(2*t) / 2
+		}
+		return 1 - in(2-2*t)/2
+	}
+}
+
+// ParseEase translates an easing name (e.g. "inQuad", "outCubic") into an EaseFunc.
+// Names are case-insensitive.
+func ParseEase(name string) (EaseFunc, error) {
+	for key, ease := range easings {
+		if strings.EqualFold(key, name) {
+			return ease, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown easing: %s", name)
+}
+
+// EaseNames returns the names of all available easing curves, sorted.
+func EaseNames() []string {
+	names := make([]string, 0, len(easings))
+	for name := range easings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DivideEase generates a sequence by dividing an interval into a number of steps,
+// with the spacing between points following the given easing curve.
+// Like Divide, it does not include the end point (b) in the sequence.
+func DivideEase(steps int, a, b float64, ease EaseFunc) ([]float64, error) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return nil, fmt.Errorf("cannot divide: NaN values are not supported")
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return nil, fmt.Errorf("cannot divide: infinite values are not supported")
+	}
+	if ease == nil {
+		return nil, fmt.Errorf("cannot divide: no easing function given")
+	}
+	if steps < 0 {
+		return nil, fmt.Errorf("steps cannot be negative")
+	}
+
+	results := make([]float64, steps)
+	for i := 0; i < steps; i++ {
+		t := float64(i) / float64(steps)
+		results[i] = Eval(ease(t), a, b)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,67 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseEase(t *testing.T) {
+	for _, name := range EaseNames() {
+		t.Run(name, func(t *testing.T) {
+			ease, err := ParseEase(name)
+			if err != nil {
+				t.Fatalf("ParseEase(%q) returned an unexpected error: %v", name, err)
+			}
+			// Every curve must start at 0 and end at 1.
+			if !almostEqual(ease(0), 0) || !almostEqual(ease(1), 1) {
+				t.Errorf("%s: ease(0) = %v, ease(1) = %v, want 0 and 1", name, ease(0), ease(1))
+			}
+		})
+	}
+
+	if _, err := ParseEase("OutCubic"); err != nil {
+		t.Errorf("ParseEase() should be case-insensitive, got error: %v", err)
+	}
+	if _, err := ParseEase("bouncy"); err == nil {
+		t.Error("ParseEase() expected an error for an unknown easing, but got nil")
+	}
+}
+
+func TestDivideEase(t *testing.T) {
+	linear, _ := ParseEase("linear")
+	inQuad, _ := ParseEase("inQuad")
+	outQuad, _ := ParseEase("outQuad")
+
+	tests := []struct {
+		name    string
+		steps   int
+		a       float64
+		b       float64
+		ease    EaseFunc
+		want    []float64
+		wantErr bool
+	}{
+		{"linear matches divide", 4, 0, 1, linear, []float64{0, 0.25, 0.5, 0.75}, false},
+		{"inQuad dense at start", 4, 0, 16, inQuad, []float64{0, 1, 4, 9}, false},
+		{"outQuad dense at end", 4, 0, 16, outQuad, []float64{0, 7, 12, 15}, false},
+		{"inverted interval", 2, 10, 0, inQuad, []float64{10, 7.5}, false},
+		{"zero steps", 0, 0, 10, inQuad, []float64{}, false},
+		{"negative steps", -1, 0, 10, inQuad, nil, true},
+		{"nil ease", 4, 0, 10, nil, nil, true},
+		{"a is NaN", 4, math.NaN(), 10, inQuad, nil, true},
+		{"b is Inf", 4, 0, math.Inf(1), inQuad, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DivideEase(tt.steps, tt.a, tt.b, tt.ease)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DivideEase() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("DivideEase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
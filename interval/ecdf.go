@@ -0,0 +1,54 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ECDF is the empirical cumulative distribution function of a set of values:
+// the proportion of the values at or below a given one. It is the statistical
+// counterpart of Deval, spreading values over [0, 1] by rank rather than by
+// distance.
+type ECDF struct {
+	sorted []float64
+}
+
+// NewECDF returns the empirical distribution of values. NaN values are ignored.
+func NewECDF(values []float64) (*ECDF, error) {
+	sorted := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	if len(sorted) == 0 {
+		return nil, ErrNoValues
+	}
+	sort.Float64s(sorted)
+	return &ECDF{sorted: sorted}, nil
+}
+
+// CDF returns the proportion of the values that are less than or equal to x.
+func (e *ECDF) CDF(x float64) (float64, error) {
+	if math.IsNaN(x) {
+		return 0, fmt.Errorf("cannot compute the CDF: NaN values are not supported")
+	}
+	n := sort.Search(len(e.sorted), func(i int) bool { return e.sorted[i] > x })
+	return float64(n) / float64(len(e.sorted)), nil
+}
+
+// Quantile returns the smallest of the values whose CDF is at least p, in
+// [0, 1], which inverts CDF: Quantile(CDF(x)) is x for any of the values.
+func (e *ECDF) Quantile(p float64) (float64, error) {
+	if math.IsNaN(p) || p < 0 || p > 1 {
+		return 0, fmt.Errorf("probability must be in the range [0, 1]")
+	}
+	// The rank, snapped to an integer within rounding error so that 0.3 of 10
+	// values is the third one.
+	rank := p * float64(len(e.sorted))
+	if k := math.Round(rank); math.Abs(rank-k) <= 1e-9*max(1, rank) {
+		rank = k
+	}
+	return e.sorted[max(0, int(math.Ceil(rank))-1)], nil
+}
@@ -0,0 +1,67 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestECDF(t *testing.T) {
+	e, err := NewECDF([]float64{3, 1, math.NaN(), 4, 1, 5, 9, 2, 6, 5, 3})
+	if err != nil {
+		t.Fatalf("NewECDF() returned an unexpected error: %v", err)
+	}
+
+	cdf := []struct {
+		x, want float64
+	}{
+		{0, 0},
+		{1, 0.2},
+		{2.5, 0.3},
+		{5, 0.8},
+		{9, 1},
+		{math.Inf(1), 1},
+	}
+	for _, tt := range cdf {
+		if got, err := e.CDF(tt.x); err != nil || !almostEqual(got, tt.want) {
+			t.Errorf("CDF(%v) = %v, %v, want %v", tt.x, got, err, tt.want)
+		}
+	}
+	if _, err := e.CDF(math.NaN()); err == nil {
+		t.Error("CDF(NaN) expected an error, but got nil")
+	}
+
+	quantiles := []struct {
+		p, want float64
+	}{
+		{0, 1},
+		{0.2, 1},
+		{0.21, 2},
+		{0.3, 2},
+		{0.5, 3},
+		{0.7, 5},
+		{1, 9},
+	}
+	for _, tt := range quantiles {
+		if got, err := e.Quantile(tt.p); err != nil || got != tt.want {
+			t.Errorf("Quantile(%v) = %v, %v, want %v", tt.p, got, err, tt.want)
+		}
+	}
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := e.Quantile(p); err == nil {
+			t.Errorf("Quantile(%v) expected an error, but got nil", p)
+		}
+	}
+
+	t.Run("quantile inverts the CDF", func(t *testing.T) {
+		for _, x := range []float64{1, 2, 3, 4, 5, 6, 9} {
+			p, _ := e.CDF(x)
+			if got, _ := e.Quantile(p); got != x {
+				t.Errorf("Quantile(CDF(%v)) = %v, want %v", x, got, x)
+			}
+		}
+	})
+
+	if _, err := NewECDF([]float64{math.NaN()}); err != ErrNoValues {
+		t.Errorf("NewECDF() of no values error = %v, want %v", err, ErrNoValues)
+	}
+}
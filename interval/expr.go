@@ -0,0 +1,142 @@
+package interval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr is a per-value transform written as a pipeline of interval primitives,
+// such as "clamp(0, 100) | remap(0, 100, 0, 1) | ease(outCubic)". Each stage is
+// applied to the result of the previous one.
+type Expr struct {
+	source string
+	stages []func(val float64) (float64, error)
+}
+
+// ParseExpr parses an expression: stages separated by '|', each one a function
+// call with its arguments in parentheses. The functions are:
+//
+//	clamp(min, max)                   restricts the value to [min, max], as Limit
+//	remap(src_a, src_b, dst_a, dst_b) remaps the value, as Remap
+//	eval(a, b)                        evaluates the value as a parameter t in [a, b]
+//	deval(a, b)                       de-evaluates the value to a parameter t of [a, b]
+//	snap(steps, a, b)                 snaps the value to a grid over [a, b], as Snap
+//	ease(name)                        applies an easing curve (see EaseNames) to t
+//
+// limit is an alias of clamp.
+func ParseExpr(s string) (*Expr, error) {
+	expr := &Expr{source: s}
+	for _, stage := range strings.Split(s, "|") {
+		fn, err := parseStage(strings.TrimSpace(stage))
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %v", s, err)
+		}
+		expr.stages = append(expr.stages, fn)
+	}
+	return expr, nil
+}
+
+// parseStage parses one stage of an expression into the function it applies.
+func parseStage(stage string) (func(float64) (float64, error), error) {
+	name, rest, ok := strings.Cut(stage, "(")
+	name = strings.TrimSpace(name)
+	if !ok || !strings.HasSuffix(rest, ")") {
+		if name == "" {
+			return nil, fmt.Errorf("empty stage")
+		}
+		return nil, fmt.Errorf("%q is not a function call, as in clamp(0, 1)", stage)
+	}
+	var args []string
+	if list := strings.TrimSpace(strings.TrimSuffix(rest, ")")); list != "" {
+		for _, arg := range strings.Split(list, ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+	}
+
+	switch strings.ToLower(name) {
+	case "clamp", "limit":
+		v, err := exprNumbers(name, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return func(val float64) (float64, error) { return Limit(val, v[0], v[1]), nil }, nil
+	case "remap":
+		v, err := exprNumbers(name, args, 4)
+		if err != nil {
+			return nil, err
+		}
+		if v[0] == v[1] {
+			return nil, fmt.Errorf("cannot remap from a source interval with zero delta")
+		}
+		return func(val float64) (float64, error) { return Remap(val, v[0], v[1], v[2], v[3]) }, nil
+	case "eval":
+		v, err := exprNumbers(name, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return func(val float64) (float64, error) { return Eval(val, v[0], v[1]), nil }, nil
+	case "deval":
+		v, err := exprNumbers(name, args, 2)
+		if err != nil {
+			return nil, err
+		}
+		if v[0] == v[1] {
+			return nil, fmt.Errorf("cannot de-evaluate in an interval with zero delta")
+		}
+		return func(val float64) (float64, error) { return Deval(val, v[0], v[1]) }, nil
+	case "snap":
+		v, err := exprNumbers(name, args, 3)
+		if err != nil {
+			return nil, err
+		}
+		steps := int(v[0])
+		if float64(steps) != v[0] || steps <= 0 {
+			return nil, fmt.Errorf("snap: steps must be a positive integer")
+		}
+		return func(val float64) (float64, error) { return Snap(val, steps, v[1], v[2]) }, nil
+	case "ease":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("ease takes 1 argument: the name of a curve")
+		}
+		ease, err := ParseEase(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return func(val float64) (float64, error) { return ease(val), nil }, nil
+	default:
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
+}
+
+// exprNumbers parses the n numeric arguments of the function name.
+func exprNumbers(name string, args []string, n int) ([]float64, error) {
+	if len(args) != n {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", name, n, len(args))
+	}
+	values := make([]float64, n)
+	for i, arg := range args {
+		val, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid number: %q", name, arg)
+		}
+		values[i] = val
+	}
+	return values, nil
+}
+
+// Apply runs val through every stage of the expression.
+func (e *Expr) Apply(val float64) (float64, error) {
+	for _, stage := range e.stages {
+		var err error
+		if val, err = stage(val); err != nil {
+			return 0, err
+		}
+	}
+	return val, nil
+}
+
+// String returns the expression as it was parsed.
+func (e *Expr) String() string {
+	return e.source
+}
@@ -0,0 +1,53 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	testCases := []struct {
+		expr  string
+		input float64
+		want  float64
+	}{
+		{"clamp(0, 100)", 150, 100},
+		{"limit(0,100)", -5, 0},
+		{"remap(0, 10, 100, 200)", 5, 150},
+		{"eval(10, 20)", 0.25, 12.5},
+		{"deval(10, 20)", 15, 0.5},
+		{"snap(10, 0, 10)", 4.78, 5},
+		{"ease(inQuad)", 0.5, 0.25},
+		{"clamp(0,100) | remap(0,100,0,1) | ease(outCubic)", 150, 1},
+		{" clamp(0, 100) | remap(0, 100, 0, 1) | ease(inQuad) ", 50, 0.25},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			expr, err := ParseExpr(tc.expr)
+			if err != nil {
+				t.Fatalf("ParseExpr(%q) returned an unexpected error: %v", tc.expr, err)
+			}
+			got, err := expr.Apply(tc.input)
+			if err != nil {
+				t.Fatalf("Apply(%v) returned an unexpected error: %v", tc.input, err)
+			}
+			if math.Abs(got-tc.want) > 1e-12 {
+				t.Errorf("%s applied to %v = %v, want %v", tc.expr, tc.input, got, tc.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "clamp", "clamp(0)", "clamp(0, a)", "remap(1, 1, 0, 1)", "snap(1.5, 0, 1)", "ease(bouncy)", "sqrt(2)", "clamp(0, 1) |"} {
+		if _, err := ParseExpr(input); err == nil {
+			t.Errorf("ParseExpr(%q) expected an error", input)
+		}
+	}
+
+	expr, err := ParseExpr("remap(0, 10, 0, 1)")
+	if err != nil {
+		t.Fatalf("ParseExpr() returned an unexpected error: %v", err)
+	}
+	if _, err := expr.Apply(math.NaN()); err == nil {
+		t.Error("Apply(NaN) expected an error from remap")
+	}
+}
@@ -0,0 +1,84 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GapFiller fills the gaps (NaN values) of a stream. Depending on the method, it
+// may need to see the next known value before it can fill a gap, so values come
+// out of Add with some delay and Flush must be called at the end of the stream.
+type GapFiller struct {
+	method  string
+	value   float64 // The constant used by the "value" method.
+	prev    float64 // The last known value.
+	hasPrev bool
+	pending int // Gaps waiting for the next known value.
+}
+
+// NewGapFiller returns a filler for a method spec: "linear" interpolates between
+// the known values around a gap, "previous" repeats the last known value, and
+// "value:<x>" uses the constant x. Gaps before the first known value are filled
+// with it, and gaps after the last known value with the last one.
+func NewGapFiller(spec string) (*GapFiller, error) {
+	name, param, hasParam := strings.Cut(spec, ":")
+	switch name {
+	case "linear", "previous":
+		if hasParam {
+			return nil, fmt.Errorf("%s takes no parameter", name)
+		}
+		return &GapFiller{method: name}, nil
+	case "value":
+		val, err := strconv.ParseFloat(param, 64)
+		if !hasParam || err != nil {
+			return nil, fmt.Errorf("value requires a numeric parameter, as in value:0")
+		}
+		return &GapFiller{method: name, value: val}, nil
+	default:
+		return nil, fmt.Errorf("unknown fill method: %s", spec)
+	}
+}
+
+// Add records a value, NaN marking a gap, and returns the values that are ready
+// to be output.
+func (g *GapFiller) Add(val float64) []float64 {
+	if math.IsNaN(val) {
+		switch {
+		case g.method == "value":
+			return []float64{g.value}
+		case g.method == "previous" && g.hasPrev:
+			return []float64{g.prev}
+		}
+		g.pending++
+		return nil
+	}
+
+	out := make([]float64, 0, g.pending+1)
+	for i := 1; i <= g.pending; i++ {
+		if g.method == "linear" && g.hasPrev {
+			out = append(out, Eval(float64(i)/float64(g.pending+1), g.prev, val))
+		} else {
+			out = append(out, val)
+		}
+	}
+	g.pending = 0
+	g.prev, g.hasPrev = val, true
+	return append(out, val)
+}
+
+// Flush returns the values for the gaps still waiting at the end of the stream.
+// If the stream had no known value at all, they cannot be filled and are dropped.
+func (g *GapFiller) Flush() []float64 {
+	if !g.hasPrev {
+		g.pending = 0
+		return nil
+	}
+	out := make([]float64, g.pending)
+	for i := range out {
+		out[i] = g.prev
+	}
+	g.pending = 0
+	return out
+}
@@ -0,0 +1,48 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGapFiller(t *testing.T) {
+	nan := math.NaN()
+
+	tests := []struct {
+		name  string
+		spec  string
+		input []float64
+		want  []float64
+	}{
+		{"linear", "linear", []float64{0, nan, nan, 3, nan, 5}, []float64{0, 1, 2, 3, 4, 5}},
+		{"linear leading and trailing", "linear", []float64{nan, 2, 4, nan}, []float64{2, 2, 4, 4}},
+		{"previous", "previous", []float64{1, nan, nan, 3, nan}, []float64{1, 1, 1, 3, 3}},
+		{"previous leading", "previous", []float64{nan, 5, nan}, []float64{5, 5, 5}},
+		{"value", "value:-1", []float64{nan, 2, nan}, []float64{-1, 2, -1}},
+		{"no gaps", "linear", []float64{1, 2, 3}, []float64{1, 2, 3}},
+		{"only gaps", "linear", []float64{nan, nan}, []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGapFiller(tt.spec)
+			if err != nil {
+				t.Fatalf("NewGapFiller() returned an unexpected error: %v", err)
+			}
+			got := []float64{}
+			for _, v := range tt.input {
+				got = append(got, g.Add(v)...)
+			}
+			got = append(got, g.Flush()...)
+			if !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("GapFiller = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, spec := range []string{"spline", "value", "value:x", "linear:1"} {
+		if _, err := NewGapFiller(spec); err == nil {
+			t.Errorf("NewGapFiller(%q) expected an error, but got nil", spec)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package interval
+
+import (
+	"fmt"
+	"strings"
+)
+
+// floatVerbs are the printf verbs that format a float64 meaningfully.
+const floatVerbs = "beEfFgGxXv"
+
+// CheckFormat checks that a printf format formats exactly one float64, so that
+// a wrong verb (e.g. "%d") is reported once instead of garbling every value.
+// Literal text and "%%" are allowed around the verb.
+func CheckFormat(format string) error {
+	verbs := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if i < len(format) && format[i] == '%' {
+			continue
+		}
+		// Flags, width and precision.
+		for i < len(format) && strings.IndexByte("+-# 0123456789.", format[i]) >= 0 {
+			i++
+		}
+		if i == len(format) {
+			return fmt.Errorf("invalid format %q: missing verb at the end", format)
+		}
+		if format[i] == '*' || format[i] == '[' {
+			return fmt.Errorf("invalid format %q: '%c' is not supported", format, format[i])
+		}
+		if strings.IndexByte(floatVerbs, format[i]) < 0 {
+			return fmt.Errorf("invalid format %q: %%%c does not format a floating-point number (use one of %%e, %%f, %%g)", format, format[i])
+		}
+		verbs++
+	}
+	if verbs != 1 {
+		return fmt.Errorf("invalid format %q: expected exactly one verb, found %d", format, verbs)
+	}
+	return nil
+}
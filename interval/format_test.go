@@ -0,0 +1,33 @@
+package interval
+
+import "testing"
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{"%g", false},
+		{"%.3f", false},
+		{"%+08.2f", false},
+		{"%e", false},
+		{"value: %.1f ms", false},
+		{"%.0f%%", false},
+		{"%d", true},
+		{"%s", true},
+		{"%.2", true},
+		{"no verb", true},
+		{"%f %f", true},
+		{"%*f", true},
+		{"%[1]f", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			err := CheckFormat(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckFormat(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			}
+		})
+	}
+}
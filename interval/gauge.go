@@ -0,0 +1,100 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultGaugeWidth is the width of the bar of a gauge when none is given.
+const DefaultGaugeWidth = 40
+
+// GaugeConfig holds the configuration for rendering a gauge.
+type GaugeConfig struct {
+	Min, Max float64 // The interval the bar spans.
+	Width    int     // Width of the bar, in characters. 0 means DefaultGaugeWidth.
+	// Warn and Crit, when HasWarn and HasCrit are set, are the thresholds past
+	// which a value needs attention. When Crit is below Warn, lower values are
+	// worse, as for a battery level or free disk space.
+	Warn, Crit       float64
+	HasWarn, HasCrit bool
+	// Color draws the bar in green, in yellow past Warn and in red past Crit.
+	Color bool
+	// ASCII draws the bar with plain ASCII characters.
+	ASCII bool
+	// Label is printed before the gauge.
+	Label string
+	// Format formats the value printed after the percentage. Nil means %g.
+	Format func(float64) string
+}
+
+// gaugeLevels are the levels of a gauge, from the best to the worst.
+const (
+	gaugeOK = iota
+	gaugeWarn
+	gaugeCrit
+)
+
+// level returns how far past the thresholds val is.
+func (c GaugeConfig) level(val float64) int {
+	reached := func(threshold float64) bool {
+		if c.HasWarn && c.HasCrit && c.Crit < c.Warn {
+			return val <= threshold
+		}
+		return val >= threshold
+	}
+	switch {
+	case c.HasCrit && reached(c.Crit):
+		return gaugeCrit
+	case c.HasWarn && reached(c.Warn):
+		return gaugeWarn
+	default:
+		return gaugeOK
+	}
+}
+
+// gaugeColors are the colors of the bar at each level.
+var gaugeColors = []SparkColor{ColorGreen, ColorYellow, ColorRed}
+
+// RenderGauge draws val as a horizontal bar filling the share of [Min, Max]
+// it reaches, followed by that share as a percentage and by the value, as in
+// "cpu [██████████▌         ]  53% 5.3". The bar is clamped to the interval;
+// the percentage is not.
+func RenderGauge(val float64, config GaugeConfig) (string, error) {
+	t, err := Deval(val, config.Min, config.Max)
+	if err != nil {
+		return "", err
+	}
+	width := config.Width
+	if width <= 0 {
+		width = DefaultGaugeWidth
+	}
+	format := config.Format
+	if format == nil {
+		format = func(v float64) string { return fmt.Sprintf("%g", v) }
+	}
+
+	var bar string
+	if config.ASCII {
+		filled := int(math.Round(Limit(t, 0, 1) * float64(width)))
+		bar = strings.Repeat("#", filled) + strings.Repeat(" ", width-filled)
+	} else {
+		eighths := int(math.Round(Limit(t, 0, 1) * float64(width*8)))
+		bar = strings.Repeat(string(barCharacters[8]), eighths/8)
+		cells := eighths / 8
+		if eighths%8 > 0 {
+			bar += string(barCharacters[eighths%8])
+			cells++
+		}
+		bar += strings.Repeat(" ", width-cells)
+	}
+	if config.Color {
+		bar = applyColor(bar, gaugeColors[config.level(val)])
+	}
+
+	output := fmt.Sprintf("[%s] %3.0f%% %s", bar, t*100, format(val))
+	if config.Label != "" {
+		output = config.Label + " " + output
+	}
+	return output, nil
+}
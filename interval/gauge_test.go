@@ -0,0 +1,92 @@
+package interval
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRenderGauge(t *testing.T) {
+	testCases := []struct {
+		name   string
+		value  float64
+		config GaugeConfig
+		want   string
+	}{
+		{
+			name:   "Half full",
+			value:  5,
+			config: GaugeConfig{Min: 0, Max: 10, Width: 4},
+			want:   "[██  ]  50% 5",
+		},
+		{
+			name:   "Partial cell",
+			value:  1.5,
+			config: GaugeConfig{Min: 0, Max: 4, Width: 2},
+			want:   "[▊ ]  38% 1.5",
+		},
+		{
+			name:   "Clamped bar, unclamped percentage",
+			value:  15,
+			config: GaugeConfig{Min: 0, Max: 10, Width: 4},
+			want:   "[████] 150% 15",
+		},
+		{
+			name:   "Empty",
+			value:  -1,
+			config: GaugeConfig{Min: 0, Max: 10, Width: 2},
+			want:   "[  ] -10% -1",
+		},
+		{
+			name:   "ASCII",
+			value:  3,
+			config: GaugeConfig{Min: 0, Max: 4, Width: 4, ASCII: true},
+			want:   "[### ]  75% 3",
+		},
+		{
+			name:   "Label and format",
+			value:  0.5,
+			config: GaugeConfig{Min: 0, Max: 1, Width: 2, Label: "cpu", Format: func(v float64) string { return fmt.Sprintf("%.2f", v) }},
+			want:   "cpu [█ ]  50% 0.50",
+		},
+		{
+			name:   "Below the thresholds",
+			value:  5,
+			config: GaugeConfig{Min: 0, Max: 10, Width: 2, Warn: 7, Crit: 9, HasWarn: true, HasCrit: true, Color: true},
+			want:   "[\033[32m█ \033[0m]  50% 5",
+		},
+		{
+			name:   "Past the warning",
+			value:  8,
+			config: GaugeConfig{Min: 0, Max: 10, Width: 2, Warn: 7, Crit: 9, HasWarn: true, HasCrit: true, Color: true},
+			want:   "[\033[33m█▋\033[0m]  80% 8",
+		},
+		{
+			name:   "Past the critical threshold",
+			value:  9,
+			config: GaugeConfig{Min: 0, Max: 10, Width: 2, Warn: 7, Crit: 9, HasWarn: true, HasCrit: true, Color: true},
+			want:   "[\033[31m█▊\033[0m]  90% 9",
+		},
+		{
+			name:   "Lower is worse",
+			value:  1,
+			config: GaugeConfig{Min: 0, Max: 10, Width: 2, Warn: 3, Crit: 1, HasWarn: true, HasCrit: true, Color: true},
+			want:   "[\033[31m▎ \033[0m]  10% 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := RenderGauge(tc.value, tc.config)
+			if err != nil {
+				t.Fatalf("RenderGauge() returned an unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("RenderGauge()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := RenderGauge(1, GaugeConfig{Min: 2, Max: 2}); err == nil {
+		t.Error("RenderGauge() with an empty interval expected an error")
+	}
+}
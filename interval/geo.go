@@ -0,0 +1,54 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxLatitude is the latitude at which the Web Mercator projection is cut, so
+// that the world is a square. Latitudes beyond it are clamped to it.
+const MaxLatitude = 85.05112877980659
+
+// TileSize is the size of a Web Mercator tile, in pixels.
+const TileSize = 256
+
+// MaxZoom is the deepest zoom level accepted, where a tile's coordinates still
+// fit in a 32-bit integer.
+const MaxZoom = 30
+
+// LatLonToTile projects a latitude and longitude, in degrees, to Web Mercator
+// tile coordinates at zoom: the integer parts are the tile, counted from the
+// north-west corner of the world, and the fractional parts the position within
+// it. Latitudes are clamped to ±MaxLatitude. It returns an error for a zoom
+// outside [0, MaxZoom], a latitude outside [-90, 90] or a longitude outside
+// [-180, 180].
+func LatLonToTile(lat, lon float64, zoom int) (x, y float64, err error) {
+	if zoom < 0 || zoom > MaxZoom {
+		return 0, 0, fmt.Errorf("zoom must be between 0 and %d", MaxZoom)
+	}
+	if !(lat >= -90 && lat <= 90) || !(lon >= -180 && lon <= 180) {
+		return 0, 0, fmt.Errorf("invalid coordinates %v, %v (expected a latitude in [-90, 90] and a longitude in [-180, 180])", lat, lon)
+	}
+	n := math.Exp2(float64(zoom))
+	lat = Limit(lat, -MaxLatitude, MaxLatitude) * math.Pi / 180
+	x = Eval((lon+180)/360, 0, n)
+	y = (1 - math.Asinh(math.Tan(lat))/math.Pi) / 2 * n
+	return x, y, nil
+}
+
+// TileToLatLon returns the latitude and longitude, in degrees, of Web Mercator
+// tile coordinates at zoom, the reverse of LatLonToTile. The corner of a tile is
+// at its integer coordinates. It returns an error for a zoom outside
+// [0, MaxZoom], or for coordinates outside the world.
+func TileToLatLon(x, y float64, zoom int) (lat, lon float64, err error) {
+	if zoom < 0 || zoom > MaxZoom {
+		return 0, 0, fmt.Errorf("zoom must be between 0 and %d", MaxZoom)
+	}
+	n := math.Exp2(float64(zoom))
+	if !(x >= 0 && x <= n) || !(y >= 0 && y <= n) {
+		return 0, 0, fmt.Errorf("invalid tile coordinates %v, %v (expected values in [0, %v])", x, y, n)
+	}
+	lat = math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+	lon = Eval(x/n, -180, 180)
+	return lat, lon, nil
+}
@@ -0,0 +1,49 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLatLonToTile(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		zoom     int
+		x, y     float64
+	}{
+		{"origin at zoom 0", 0, 0, 0, 0.5, 0.5},
+		{"north-west corner", MaxLatitude, -180, 3, 0, 0},
+		{"south-east corner", -MaxLatitude, 180, 3, 8, 8},
+		{"clamped to the cut", 89, 0, 1, 1, 0},
+		{"London at zoom 10", 51.5074, -0.1278, 10, 511.636, 340.506},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, err := LatLonToTile(tt.lat, tt.lon, tt.zoom)
+			if err != nil {
+				t.Fatalf("LatLonToTile() returned an unexpected error: %v", err)
+			}
+			if math.Abs(x-tt.x) > 1e-3 || math.Abs(y-tt.y) > 1e-3 {
+				t.Errorf("LatLonToTile(%v, %v, %d) = %v, %v, want %v, %v", tt.lat, tt.lon, tt.zoom, x, y, tt.x, tt.y)
+			}
+
+			lat, lon, err := TileToLatLon(x, y, tt.zoom)
+			if err != nil {
+				t.Fatalf("TileToLatLon() returned an unexpected error: %v", err)
+			}
+			if wantLat := Limit(tt.lat, -MaxLatitude, MaxLatitude); math.Abs(lat-wantLat) > 1e-9 || math.Abs(lon-tt.lon) > 1e-9 {
+				t.Errorf("TileToLatLon(%v, %v, %d) = %v, %v, want %v, %v", x, y, tt.zoom, lat, lon, wantLat, tt.lon)
+			}
+		})
+	}
+
+	for _, in := range [][3]float64{{91, 0, 1}, {0, 181, 1}, {math.NaN(), 0, 1}, {0, 0, -1}, {0, 0, 31}} {
+		if _, _, err := LatLonToTile(in[0], in[1], int(in[2])); err == nil {
+			t.Errorf("LatLonToTile(%v, %v, %v) expected an error, but got nil", in[0], in[1], in[2])
+		}
+	}
+	if _, _, err := TileToLatLon(3, 0, 1); err == nil {
+		t.Error("TileToLatLon() expected an error for a tile outside the world, but got nil")
+	}
+}
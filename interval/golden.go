@@ -0,0 +1,75 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+)
+
+// Phi is the golden ratio.
+var Phi = (1 + math.Sqrt(5)) / 2
+
+// Golden splits the interval [a, b] into n segments by recursively cutting the
+// remaining interval at its golden section. As each cut leaves 1/Phi² of the
+// remainder, each segment is Phi² (about 2.618) times longer than the next one,
+// except for the last one, which takes whatever remains: the one before it is
+// Phi times longer.
+func Golden(n int, a, b float64) ([][2]float64, error) {
+	if err := checkSegmentArgs("golden", n, a, b); err != nil {
+		return nil, err
+	}
+
+	results := make([][2]float64, n)
+	start := a
+	for i := 0; i < n; i++ {
+		end := b
+		if i < n-1 {
+			end = start + (b-start)/Phi
+		}
+		results[i] = [2]float64{start, end}
+		start = end
+	}
+
+	return results, nil
+}
+
+// Fibonacci splits the interval [a, b] into n segments whose lengths are
+// proportional to the Fibonacci sequence 1, 1, 2, 3, 5, ...
+func Fibonacci(n int, a, b float64) ([][2]float64, error) {
+	if err := checkSegmentArgs("fibonacci", n, a, b); err != nil {
+		return nil, err
+	}
+
+	// Cumulative sums of the Fibonacci sequence give the cut points.
+	cumulative := make([]float64, n+1)
+	prev, curr := 0.0, 1.0
+	for i := 1; i <= n; i++ {
+		cumulative[i] = cumulative[i-1] + curr
+		prev, curr = curr, prev+curr
+	}
+
+	results := make([][2]float64, n)
+	total := cumulative[n]
+	for i := 0; i < n; i++ {
+		end := b
+		if i < n-1 {
+			end = Eval(cumulative[i+1]/total, a, b)
+		}
+		results[i] = [2]float64{Eval(cumulative[i]/total, a, b), end}
+	}
+
+	return results, nil
+}
+
+// checkSegmentArgs validates the arguments shared by the segment generators.
+func checkSegmentArgs(op string, n int, a, b float64) error {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return fmt.Errorf("cannot create %s segments: NaN bounds", op)
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return fmt.Errorf("cannot create %s segments: infinite bounds", op)
+	}
+	if n < 0 {
+		return fmt.Errorf("segment count cannot be negative")
+	}
+	return nil
+}
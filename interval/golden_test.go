@@ -0,0 +1,101 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func pairsAlmostEqual(a, b [][2]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !almostEqual(a[i][0], b[i][0]) || !almostEqual(a[i][1], b[i][1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGolden(t *testing.T) {
+	major := 100 / Phi
+
+	tests := []struct {
+		name    string
+		n       int
+		a       float64
+		b       float64
+		want    [][2]float64
+		wantErr bool
+	}{
+		{"one segment", 1, 0, 100, [][2]float64{{0, 100}}, false},
+		{"two segments", 2, 0, 100, [][2]float64{{0, major}, {major, 100}}, false},
+		{"three segments", 3, 0, 100, [][2]float64{{0, major}, {major, major + (100-major)/Phi}, {major + (100-major)/Phi, 100}}, false},
+		{"inverted interval", 2, 100, 0, [][2]float64{{100, 100 - major}, {100 - major, 0}}, false},
+		{"zero segments", 0, 0, 100, [][2]float64{}, false},
+		{"negative segments", -1, 0, 100, nil, true},
+		{"a is NaN", 2, math.NaN(), 100, nil, true},
+		{"b is Inf", 2, 0, math.Inf(1), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Golden(tt.n, tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Golden() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !pairsAlmostEqual(got, tt.want) {
+				t.Errorf("Golden() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoldenRatios(t *testing.T) {
+	got, err := Golden(6, 0, 100)
+	if err != nil {
+		t.Fatalf("Golden() returned an unexpected error: %v", err)
+	}
+	length := func(i int) float64 { return got[i][1] - got[i][0] }
+	for i := 0; i < len(got)-2; i++ {
+		if ratio := length(i) / length(i+1); !almostEqual(ratio, Phi*Phi) {
+			t.Errorf("segment %d is %v times longer than the next, want Phi² (%v)", i, ratio, Phi*Phi)
+		}
+	}
+	if ratio := length(4) / length(5); !almostEqual(ratio, Phi) {
+		t.Errorf("the next-to-last segment is %v times longer than the last, want Phi (%v)", ratio, Phi)
+	}
+}
+
+func TestFibonacci(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		a       float64
+		b       float64
+		want    [][2]float64
+		wantErr bool
+	}{
+		{"one segment", 1, 0, 10, [][2]float64{{0, 10}}, false},
+		// Proportions 1, 1, 2, 3, 5 over a total of 12.
+		{"five segments", 5, 0, 12, [][2]float64{{0, 1}, {1, 2}, {2, 4}, {4, 7}, {7, 12}}, false},
+		{"inverted interval", 3, 4, 0, [][2]float64{{4, 3}, {3, 2}, {2, 0}}, false},
+		{"zero segments", 0, 0, 10, [][2]float64{}, false},
+		{"negative segments", -1, 0, 10, nil, true},
+		{"b is NaN", 2, 0, math.NaN(), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Fibonacci(tt.n, tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Fibonacci() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !pairsAlmostEqual(got, tt.want) {
+				t.Errorf("Fibonacci() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RGB is a 24-bit color.
+type RGB struct {
+	R, G, B uint8
+}
+
+// Hex returns the color as "#RRGGBB".
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// namedColors are the color names accepted by ParseRGB.
+var namedColors = map[string]RGB{
+	"black":   {0, 0, 0},
+	"white":   {255, 255, 255},
+	"red":     {255, 0, 0},
+	"green":   {0, 255, 0},
+	"blue":    {0, 0, 255},
+	"yellow":  {255, 255, 0},
+	"magenta": {255, 0, 255},
+	"cyan":    {0, 255, 255},
+}
+
+// ParseRGB parses a color given as "#RRGGBB" (the '#' is optional) or as one of
+// the basic color names (red, green, blue, yellow, magenta, cyan, black, white).
+func ParseRGB(s string) (RGB, error) {
+	if c, ok := namedColors[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 {
+		return RGB{}, fmt.Errorf("invalid color: %s (expected #RRGGBB or a color name)", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("invalid color: %s (expected #RRGGBB or a color name)", s)
+	}
+	return RGB{uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// Gradient interpolates linearly between evenly spaced color stops.
+type Gradient struct {
+	stops []RGB
+}
+
+// palettes are the named gradients accepted by ParsePalette.
+var palettes = map[string][]RGB{
+	"viridis": {
+		{0x44, 0x01, 0x54}, {0x48, 0x28, 0x78}, {0x3e, 0x49, 0x89}, {0x31, 0x68, 0x8e}, {0x26, 0x82, 0x8e},
+		{0x1f, 0x9e, 0x89}, {0x35, 0xb7, 0x79}, {0x6e, 0xce, 0x58}, {0xb5, 0xde, 0x2b}, {0xfd, 0xe7, 0x25},
+	},
+	"heat": {{0, 0, 0}, {255, 0, 0}, {255, 255, 0}, {255, 255, 255}},
+}
+
+// NewGradient returns a gradient through the given color stops. It needs at
+// least one stop; a single stop gives a constant color.
+func NewGradient(stops ...RGB) (*Gradient, error) {
+	if len(stops) == 0 {
+		return nil, fmt.Errorf("a gradient needs at least one color")
+	}
+	return &Gradient{stops: stops}, nil
+}
+
+// ParsePalette translates a palette spec into a Gradient: a named palette
+// (viridis, heat), or "rgb:" followed by comma-separated colors as accepted by
+// ParseRGB (e.g. "rgb:#000000,#ff8800,white").
+func ParsePalette(spec string) (*Gradient, error) {
+	if stops, ok := palettes[strings.ToLower(spec)]; ok {
+		return NewGradient(stops...)
+	}
+	list, ok := strings.CutPrefix(spec, "rgb:")
+	if !ok {
+		return nil, fmt.Errorf("unknown palette: %s (expected viridis, heat or rgb:<color>,<color>...)", spec)
+	}
+	var stops []RGB
+	for _, s := range strings.Split(list, ",") {
+		c, err := ParseRGB(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		stops = append(stops, c)
+	}
+	return NewGradient(stops...)
+}
+
+// ParseColorRange parses a two-color gradient given as "<from>..<to>", with
+// each color as accepted by ParseRGB (e.g. "green..red").
+func ParseColorRange(spec string) (*Gradient, error) {
+	from, to, ok := strings.Cut(spec, "..")
+	if !ok {
+		return nil, fmt.Errorf("invalid color range: %s (expected <from>..<to>)", spec)
+	}
+	fromColor, err := ParseRGB(strings.TrimSpace(from))
+	if err != nil {
+		return nil, err
+	}
+	toColor, err := ParseRGB(strings.TrimSpace(to))
+	if err != nil {
+		return nil, err
+	}
+	return NewGradient(fromColor, toColor)
+}
+
+// At returns the color at parameter t, clamped to [0, 1]. NaN gives the first stop.
+func (g *Gradient) At(t float64) RGB {
+	if math.IsNaN(t) || len(g.stops) == 1 {
+		return g.stops[0]
+	}
+	t = Limit(t, 0, 1)
+	pos := t * float64(len(g.stops)-1)
+	i := min(int(pos), len(g.stops)-2)
+	frac := pos - float64(i)
+	from, to := g.stops[i], g.stops[i+1]
+	lerp := func(a, b uint8) uint8 {
+		return uint8(math.Round(Eval(frac, float64(a), float64(b))))
+	}
+	return RGB{lerp(from.R, to.R), lerp(from.G, to.G), lerp(from.B, to.B)}
+}
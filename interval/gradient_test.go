@@ -0,0 +1,102 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseRGB(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    RGB
+		wantErr bool
+	}{
+		{"#ff8800", RGB{255, 136, 0}, false},
+		{"00FF7f", RGB{0, 255, 127}, false},
+		{"Red", RGB{255, 0, 0}, false},
+		{"#fff", RGB{}, true},
+		{"#gg0000", RGB{}, true},
+		{"purple-ish", RGB{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRGB(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRGB(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseRGB(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if hex := (RGB{255, 136, 0}).Hex(); hex != "#ff8800" {
+		t.Errorf("Hex() = %q, want %q", hex, "#ff8800")
+	}
+}
+
+func TestGradientAt(t *testing.T) {
+	g, err := ParsePalette("rgb:#000000,#ff0000,#ffffff")
+	if err != nil {
+		t.Fatalf("ParsePalette() returned an unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		t    float64
+		want RGB
+	}{
+		{0, RGB{0, 0, 0}},
+		{0.25, RGB{128, 0, 0}},
+		{0.5, RGB{255, 0, 0}},
+		{0.75, RGB{255, 128, 128}},
+		{1, RGB{255, 255, 255}},
+		{-1, RGB{0, 0, 0}},
+		{2, RGB{255, 255, 255}},
+		{math.NaN(), RGB{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		if got := g.At(tt.t); got != tt.want {
+			t.Errorf("At(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+
+	single, _ := NewGradient(RGB{1, 2, 3})
+	if got := single.At(0.7); got != (RGB{1, 2, 3}) {
+		t.Errorf("single-stop At() = %v, want {1 2 3}", got)
+	}
+}
+
+func TestParsePalette(t *testing.T) {
+	for _, spec := range []string{"viridis", "heat", "Heat", "rgb:red,blue"} {
+		if _, err := ParsePalette(spec); err != nil {
+			t.Errorf("ParsePalette(%q) returned an unexpected error: %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"rainbow", "rgb:", "rgb:red,nope"} {
+		if _, err := ParsePalette(spec); err == nil {
+			t.Errorf("ParsePalette(%q) expected an error, but got nil", spec)
+		}
+	}
+	if _, err := NewGradient(); err == nil {
+		t.Error("NewGradient() expected an error without stops, but got nil")
+	}
+}
+
+func TestParseColorRange(t *testing.T) {
+	g, err := ParseColorRange("green..#ff0000")
+	if err != nil {
+		t.Fatalf("ParseColorRange() returned an unexpected error: %v", err)
+	}
+	if got := g.At(0); got != (RGB{0, 255, 0}) {
+		t.Errorf("At(0) = %v, want green", got)
+	}
+	if got := g.At(1); got != (RGB{255, 0, 0}) {
+		t.Errorf("At(1) = %v, want red", got)
+	}
+	for _, spec := range []string{"green", "green..", "..red", "green..nope"} {
+		if _, err := ParseColorRange(spec); err == nil {
+			t.Errorf("ParseColorRange(%q) expected an error, but got nil", spec)
+		}
+	}
+}
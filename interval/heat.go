@@ -0,0 +1,79 @@
+package interval
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// HeatConfig holds the configuration for rendering a heat strip.
+type HeatConfig struct {
+	Min, Max float64
+	HasRange bool // Use Min and Max instead of the range of the input.
+	Gradient *Gradient
+	// Plain draws the cells as shade characters from light to dark instead of
+	// colored backgrounds, for output where colors are turned off.
+	Plain bool
+}
+
+// heatShades are the cells of a plain heat strip, from the start of the range
+// to its end.
+var heatShades = []rune{' ', '░', '▒', '▓', '█'}
+
+// GenerateHeatStrip renders the numbers read from scanner as a row of cells
+// whose truecolor background follows the gradient, a 1-D heatmap. With a fixed
+// range, cells are written as the numbers arrive; otherwise the whole input is
+// read first to find its range.
+func GenerateHeatStrip(scanner *bufio.Scanner, writer io.Writer, config HeatConfig) error {
+	if config.Gradient == nil {
+		return fmt.Errorf("no gradient given")
+	}
+	if config.HasRange {
+		for scanner.Scan() {
+			for _, field := range strings.Fields(scanner.Text()) {
+				val, err := ParseHuman(field)
+				if err != nil {
+					continue // Skip non-numeric fields
+				}
+				fmt.Fprint(writer, heatCell(val, config.Min, config.Max, config))
+			}
+		}
+		return scanner.Err()
+	}
+
+	numbers, err := readAllNumbers(scanner)
+	if err != nil {
+		return err
+	}
+	var r RunningRange
+	for _, n := range numbers {
+		r.Add(n)
+	}
+	var output strings.Builder
+	for _, n := range numbers {
+		output.WriteString(heatCell(n, r.Min, r.Max, config))
+	}
+	fmt.Fprint(writer, output.String())
+	return nil
+}
+
+// heatCell renders one value as a space on a colored background, or as a shade
+// in plain mode. NaN values are left blank, and a zero-width range gives the
+// middle color.
+func heatCell(val, min, max float64, config HeatConfig) string {
+	if math.IsNaN(val) {
+		return " "
+	}
+	t := 0.5
+	if max != min {
+		t, _ = Deval(val, min, max)
+	}
+	if config.Plain {
+		last := float64(len(heatShades) - 1)
+		return string(heatShades[int(math.Round(Limit(t, 0, 1)*last))])
+	}
+	c := config.Gradient.At(t)
+	return fmt.Sprintf("\033[48;2;%d;%d;%dm %s", c.R, c.G, c.B, ColorReset)
+}
@@ -0,0 +1,47 @@
+package interval
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerateHeatStrip(t *testing.T) {
+	gradient, _ := ParsePalette("rgb:#000000,#ffffff")
+	black := "\033[48;2;0;0;0m \033[0m"
+	gray := "\033[48;2;128;128;128m \033[0m"
+	white := "\033[48;2;255;255;255m \033[0m"
+
+	tests := []struct {
+		name   string
+		input  string
+		config HeatConfig
+		want   string
+	}{
+		{"auto range", "0 5\n10", HeatConfig{Gradient: gradient}, black + gray + white},
+		{"fixed range clamps", "-5 50 100 200", HeatConfig{Min: 0, Max: 100, HasRange: true, Gradient: gradient}, black + gray + white + white},
+		{"constant input", "3 3", HeatConfig{Gradient: gradient}, gray + gray},
+		{"skips non-numeric fields", "0 abc 10", HeatConfig{Gradient: gradient}, black + white},
+		{"empty input", "", HeatConfig{Gradient: gradient}, ""},
+		{"plain shades", "0 2 5 7 10", HeatConfig{Gradient: gradient, Plain: true}, " ░▒▓█"},
+		{"plain clamps", "-5 50 200", HeatConfig{Min: 0, Max: 100, HasRange: true, Gradient: gradient, Plain: true}, " ▒█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			scanner := bufio.NewScanner(strings.NewReader(tt.input))
+			if err := GenerateHeatStrip(scanner, &out, tt.config); err != nil {
+				t.Fatalf("GenerateHeatStrip() returned an unexpected error: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("GenerateHeatStrip() = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+
+	if err := GenerateHeatStrip(bufio.NewScanner(strings.NewReader("1")), &bytes.Buffer{}, HeatConfig{}); err == nil {
+		t.Error("GenerateHeatStrip() expected an error without a gradient, but got nil")
+	}
+}
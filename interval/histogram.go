@@ -0,0 +1,84 @@
+package interval
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Bin is a bin of a histogram: the number of values in [Start, End). The last
+// bin of a histogram also counts the values equal to its End.
+type Bin struct {
+	Start, End float64
+	Count      int
+}
+
+// Histogram counts values into n equal bins spanning [a, b]. NaN values and
+// values outside of the interval are ignored. If a == b, every value equal to a
+// is counted in the first bin.
+func Histogram(values []float64, n int, a, b float64) ([]Bin, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("the number of bins must be a positive integer")
+	}
+	if a > b {
+		a, b = b, a
+	}
+	edges, err := Subintervals(n, a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	bins := make([]Bin, n)
+	for i, edge := range edges {
+		bins[i] = Bin{Start: edge[0], End: edge[1]}
+	}
+	for _, v := range values {
+		if math.IsNaN(v) || v < a || v > b {
+			continue
+		}
+		i := 0
+		if b > a {
+			i = int(math.Min((v-a)/(b-a)*float64(n), float64(n-1)))
+		}
+		bins[i].Count++
+	}
+	return bins, nil
+}
+
+// barCharacters are the eighths used to draw the end of a horizontal bar.
+var barCharacters = []rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+
+// RenderHistogram draws bins as a horizontal bar chart, one line per bin: its
+// label, a bar scaled so the largest count spans width characters, and the
+// count. Labels are padded to the same width; labels[i] names bins[i].
+func RenderHistogram(writer io.Writer, bins []Bin, labels []string, width int) error {
+	if len(labels) != len(bins) {
+		return fmt.Errorf("got %d labels for %d bins", len(labels), len(bins))
+	}
+	labelWidth, maxCount := 0, 0
+	for i, bin := range bins {
+		if n := len([]rune(labels[i])); n > labelWidth {
+			labelWidth = n
+		}
+		if bin.Count > maxCount {
+			maxCount = bin.Count
+		}
+	}
+
+	for i, bin := range bins {
+		eighths := 0
+		if maxCount > 0 {
+			eighths = int(math.Round(float64(bin.Count) / float64(maxCount) * float64(width*8)))
+		}
+		bar := strings.Repeat(string(barCharacters[8]), eighths/8)
+		if eighths%8 > 0 {
+			bar += string(barCharacters[eighths%8])
+		}
+		padding := strings.Repeat(" ", labelWidth-len([]rune(labels[i])))
+		if _, err := fmt.Fprintf(writer, "%s%s │%s %d\n", labels[i], padding, bar, bin.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package interval
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	testCases := []struct {
+		name    string
+		values  []float64
+		n       int
+		a, b    float64
+		want    []Bin
+		wantErr bool
+	}{
+		{
+			name:   "Basic",
+			values: []float64{0, 1, 4, 5, 9, 10},
+			n:      2, a: 0, b: 10,
+			want: []Bin{{0, 5, 3}, {5, 10, 3}},
+		},
+		{
+			name:   "Outside values and NaN are ignored",
+			values: []float64{-1, 2, 11, math.NaN()},
+			n:      2, a: 0, b: 10,
+			want: []Bin{{0, 5, 1}, {5, 10, 0}},
+		},
+		{
+			name:   "Reversed interval",
+			values: []float64{1, 9},
+			n:      2, a: 10, b: 0,
+			want: []Bin{{0, 5, 1}, {5, 10, 1}},
+		},
+		{
+			name:   "Zero-width interval",
+			values: []float64{3, 3, 4},
+			n:      2, a: 3, b: 3,
+			want: []Bin{{3, 3, 2}, {3, 3, 0}},
+		},
+		{"Zero bins", []float64{1}, 0, 0, 1, nil, true},
+		{"Infinite bounds", []float64{1}, 2, 0, math.Inf(1), nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Histogram(tc.values, tc.n, tc.a, tc.b)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Histogram() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Histogram() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderHistogram(t *testing.T) {
+	bins := []Bin{{0, 5, 4}, {5, 10, 1}, {10, 15, 0}}
+	labels := []string{"[0, 5)", "[5, 10)", "[10, 15]"}
+
+	var writer bytes.Buffer
+	if err := RenderHistogram(&writer, bins, labels, 2); err != nil {
+		t.Fatalf("RenderHistogram() returned an unexpected error: %v", err)
+	}
+	want := "[0, 5)   │██ 4\n" +
+		"[5, 10)  │▌ 1\n" +
+		"[10, 15] │ 0\n"
+	if got := writer.String(); got != want {
+		t.Errorf("RenderHistogram()\n  got: %q\n want: %q", got, want)
+	}
+
+	if err := RenderHistogram(&writer, bins, labels[:1], 2); err == nil {
+		t.Error("RenderHistogram() expected an error for missing labels")
+	}
+}
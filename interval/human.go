@@ -0,0 +1,88 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// decimalSuffixes are the SI prefixes accepted by ParseHuman. "K" is accepted
+// as an alias of "k", and both "u" and "µ" mean micro.
+var decimalSuffixes = map[string]float64{
+	"y": 1e-24, "z": 1e-21, "a": 1e-18, "f": 1e-15, "p": 1e-12, "n": 1e-9,
+	"u": 1e-6, "µ": 1e-6, "m": 1e-3,
+	"k": 1e3, "K": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18, "Z": 1e21, "Y": 1e24,
+}
+
+// binarySuffixes are the IEC prefixes accepted by ParseHuman.
+var binarySuffixes = map[string]float64{
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+// ParseHuman parses a number that may carry an SI suffix (e.g. "1.5k", "3M",
+// "200m" for 0.2) or a binary suffix (e.g. "1Gi", "512Ki"). Suffixes are case
+// sensitive, since "m" (milli) and "M" (mega) differ. Plain numbers are parsed
+// as by strconv.ParseFloat.
+func ParseHuman(s string) (float64, error) {
+	if val, err := strconv.ParseFloat(s, 64); err == nil {
+		return val, nil
+	}
+
+	trimmed := strings.TrimSpace(s)
+	for _, suffixes := range []map[string]float64{binarySuffixes, decimalSuffixes} {
+		for suffix, mult := range suffixes {
+			num, ok := strings.CutSuffix(trimmed, suffix)
+			if !ok || num == "" {
+				continue
+			}
+			val, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+			if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+				continue
+			}
+			return val * mult, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid number: %q", s)
+}
+
+// siPrefixes are the decimal prefixes used by FormatHuman, from 1e-24 to 1e24
+// in steps of 1000. Micro is written "u" so the output can be parsed back.
+var siPrefixes = []string{"y", "z", "a", "f", "p", "n", "u", "m", "", "k", "M", "G", "T", "P", "E", "Z", "Y"}
+
+// iecPrefixes are the binary prefixes used by FormatHuman, in steps of 1024.
+var iecPrefixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+
+// FormatHuman formats a number with an SI suffix (e.g. "12.3k", "4.5M", "200m"),
+// or with a binary suffix (e.g. "1.5Gi") if binary is true, the reverse of
+// ParseHuman. The mantissa is formatted with the printf format. Binary suffixes
+// are only used for magnitudes of 1024 and more.
+func FormatHuman(val float64, format string, binary bool) string {
+	if val == 0 || math.IsNaN(val) || math.IsInf(val, 0) {
+		return fmt.Sprintf(format, val)
+	}
+
+	base, prefixes, unit := 1000.0, siPrefixes, 8 // Index of the empty prefix.
+	if binary {
+		base, prefixes, unit = 1024, iecPrefixes, 0
+	}
+	i := unit + int(math.Floor(math.Log(math.Abs(val))/math.Log(base)))
+	i = max(0, min(i, len(prefixes)-1))
+
+	mantissa := fmt.Sprintf(format, scaleDown(val, base, i-unit))
+	// Rounding may carry the mantissa to the next prefix (999.96 -> "1000.0").
+	if m, err := strconv.ParseFloat(strings.TrimSpace(mantissa), 64); err == nil && math.Abs(m) >= base && i+1 < len(prefixes) {
+		i++
+		mantissa = fmt.Sprintf(format, scaleDown(val, base, i-unit))
+	}
+	return mantissa + prefixes[i]
+}
+
+// scaleDown returns val / base^exp. Negative powers are applied as a product,
+// since the positive powers of 1000 are exact while the negative ones are not.
+func scaleDown(val, base float64, exp int) float64 {
+	if exp < 0 {
+		return val * math.Pow(base, float64(-exp))
+	}
+	return val / math.Pow(base, float64(exp))
+}
@@ -0,0 +1,77 @@
+package interval
+
+import (
+	"testing"
+)
+
+func TestParseHuman(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"42", 42, false},
+		{"-1.5e3", -1500, false},
+		{"1.5k", 1500, false},
+		{"1.5K", 1500, false},
+		{"3M", 3e6, false},
+		{"200m", 0.2, false},
+		{"250u", 250e-6, false},
+		{"250µ", 250e-6, false},
+		{"2G", 2e9, false},
+		{"1Gi", 1 << 30, false},
+		{"512Ki", 512 * 1024, false},
+		{"-2Mi", -2 * 1024 * 1024, false},
+		{"1.5 k", 1500, false},
+		{"k", 0, true},
+		{"1.5x", 0, true},
+		{"infk", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseHuman(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHuman(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !almostEqual(got, tt.want) {
+				t.Errorf("ParseHuman(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatHuman(t *testing.T) {
+	tests := []struct {
+		val    float64
+		format string
+		binary bool
+		want   string
+	}{
+		{12345, "%.1f", false, "12.3k"},
+		{4.5e6, "%g", false, "4.5M"},
+		{-2500, "%g", false, "-2.5k"},
+		{0.2, "%g", false, "200m"},
+		{250e-6, "%g", false, "250u"},
+		{42, "%g", false, "42"},
+		{0, "%g", false, "0"},
+		{999.96, "%.1f", false, "1.0k"},
+		{1e30, "%g", false, "1e+06Y"},
+		{1.5 * (1 << 30), "%g", true, "1.5Gi"},
+		{512 * 1024, "%g", true, "512Ki"},
+		{1000, "%g", true, "1000"},
+		{0.5, "%g", true, "0.5"},
+	}
+
+	for _, tt := range tests {
+		got := FormatHuman(tt.val, tt.format, tt.binary)
+		if got != tt.want {
+			t.Errorf("FormatHuman(%v, %q, %v) = %q, want %q", tt.val, tt.format, tt.binary, got, tt.want)
+		}
+		if back, err := ParseHuman(got); err != nil || !almostEqual(back, tt.val) && tt.format == "%g" {
+			t.Errorf("ParseHuman(%q) = %v, %v, want %v", got, back, err, tt.val)
+		}
+	}
+}
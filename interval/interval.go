@@ -6,11 +6,42 @@ import (
 	"math"
 	"math/rand"
 	"os"
-	"strconv"
 )
 
+// Tolerance decides when a difference is too small to tell from zero.
+type Tolerance struct {
+	// Epsilon is the smallest difference that is not zero. With Epsilon 0,
+	// only an exact zero is.
+	Epsilon float64
+	// Relative scales Epsilon by the magnitude of the interval's bounds, so
+	// that tiny intervals near zero and large values far from it are judged
+	// alike.
+	Relative bool
+}
+
+// ZeroTolerance is the tolerance below which Deval, and every operation built
+// on it, considers an interval to have a delta of zero.
+var ZeroTolerance = Tolerance{Epsilon: 1e-15}
+
+// negligible reports whether the difference d, between values of the interval
+// [a, b], is zero within the tolerance.
+func (tol Tolerance) negligible(d, a, b float64) bool {
+	epsilon := tol.Epsilon
+	if tol.Relative {
+		epsilon *= max(math.Abs(a), math.Abs(b))
+	}
+	return d == 0 || math.Abs(d) < epsilon
+}
+
+// Empty reports whether the interval [a, b] has a delta of zero within the
+// tolerance, the intervals Deval rejects.
+func (tol Tolerance) Empty(a, b float64) bool {
+	return tol.negligible(b-a, a, b)
+}
+
 // Deval returns the parameter 't' of a value within an interval [a, b].
-// It returns an error if the interval has a delta of zero (a == b).
+// It returns an error if the interval has a delta of zero (a == b), within
+// ZeroTolerance.
 func Deval(val, a, b float64) (float64, error) {
 	// Handle NaN and Inf inputs
 	if math.IsNaN(val) || math.IsNaN(a) || math.IsNaN(b) {
@@ -21,10 +52,8 @@ func Deval(val, a, b float64) (float64, error) {
 	}
 
 	delta := b - a
-	const epsilon = 1e-15
-
-	if math.Abs(delta) < epsilon {
-		if math.Abs(val-a) < epsilon {
+	if ZeroTolerance.negligible(delta, a, b) {
+		if ZeroTolerance.negligible(val-a, a, b) {
 			return 0, nil
 		}
 		return 0, fmt.Errorf("cannot de-evaluate in an interval with near-zero delta")
@@ -34,15 +63,36 @@ func Deval(val, a, b float64) (float64, error) {
 	return t, nil
 }
 
-// Eval evaluates a parameter 't' within the interval [a, b].
+// Eval evaluates a parameter 't' within the interval [a, b]. It is exact at the
+// ends, t = 0 giving a and t = 1 giving b, however far apart they are: each half
+// of the interval is computed from its nearest end, with a fused multiply-add
+// rounding once.
 func Eval(t, a, b float64) float64 {
 	if math.IsNaN(t) || math.IsNaN(a) || math.IsNaN(b) {
 		return math.NaN()
 	}
-	return a + (b-a)*t
+	if t < 0.5 {
+		return math.FMA(t, b-a, a)
+	}
+	return math.FMA(t-1, b-a, b)
+}
+
+// EvalVector evaluates a parameter 't' between the vectors a and b, component by
+// component: Eval generalized to rows of numbers. It returns an error if a and b
+// have different lengths.
+func EvalVector(t float64, a, b []float64) ([]float64, error) {
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("cannot interpolate between vectors of %d and %d components", len(a), len(b))
+	}
+	result := make([]float64, len(a))
+	for i := range a {
+		result[i] = Eval(t, a[i], b[i])
+	}
+	return result, nil
 }
 
 // Remap translates a value from a source interval [srcA, srcB] to a target interval [dstA, dstB].
+// srcA maps exactly to dstA and srcB to dstB, as with Eval.
 // It returns an error if the source interval has a delta of zero.
 func Remap(val, srcA, srcB, dstA, dstB float64) (float64, error) {
 	if math.IsNaN(val) || math.IsNaN(srcA) || math.IsNaN(srcB) ||
@@ -153,9 +203,10 @@ func Divide(steps int, a, b float64) ([]float64, error) {
 		return results, nil
 	}
 
-	stepSize := (b - a) / float64(steps)
+	// Each point is evaluated from its own index rather than by adding steps of a
+	// rounded size, so the error does not grow along the sequence.
 	for i := 0; i < steps; i++ {
-		results[i] = a + (float64(i) * stepSize)
+		results[i] = Eval(float64(i)/float64(steps), a, b)
 	}
 
 	return results, nil
@@ -193,12 +244,22 @@ func Random(r *rand.Rand, count int, a, b float64) ([]float64, error) {
 
 // Subintervals generates a sequence of interval pairs.
 func Subintervals(steps int, a, b float64) ([][2]float64, error) {
+	return SubintervalsOverlap(steps, a, b, 0)
+}
+
+// SubintervalsOverlap generates a sequence of interval pairs where consecutive
+// pairs overlap by the given fraction of their length (0 <= overlap < 1).
+// The windows are sized so that together they still span exactly [a, b].
+func SubintervalsOverlap(steps int, a, b, overlap float64) ([][2]float64, error) {
 	if math.IsNaN(a) || math.IsNaN(b) {
 		return nil, fmt.Errorf("cannot create subintervals: NaN bounds")
 	}
 	if math.IsInf(a, 0) || math.IsInf(b, 0) {
 		return nil, fmt.Errorf("cannot create subintervals: infinite bounds")
 	}
+	if math.IsNaN(overlap) || overlap < 0 || overlap >= 1 {
+		return nil, fmt.Errorf("overlap must be in the range [0, 1)")
+	}
 	if steps < 0 {
 		return nil, fmt.Errorf("steps cannot be negative")
 	}
@@ -214,50 +275,70 @@ func Subintervals(steps int, a, b float64) ([][2]float64, error) {
 		return results, nil
 	}
 
-	stepSize := (b - a) / float64(steps)
+	// The windows cover the whole interval: size + (steps-1)*stride == b-a, in
+	// units of the window size. Each bound is evaluated from its own index, so
+	// the last windows do not drift, and without overlap each window ends
+	// exactly where the next one starts.
+	span := 1 + float64(steps-1)*(1-overlap)
 	for i := 0; i < steps; i++ {
-		start := a + (float64(i) * stepSize)
-		end := a + (float64(i+1) * stepSize)
-		results[i] = [2]float64{start, end}
+		offset := float64(i) * (1 - overlap)
+		end := b
+		if i < steps-1 {
+			end = Eval((offset+1)/span, a, b)
+		}
+		results[i] = [2]float64{Eval(offset/span, a, b), end}
 	}
 
 	return results, nil
 }
 
 // Encompass reads a stream of numbers and returns the minimum and maximum values.
-// It returns an error if no valid numbers are found in the input.
+// NaN values are ignored, so an input of only NaN has no range: like an input
+// without valid numbers, it returns an error rather than +Inf and -Inf.
 func Encompass(scanner *bufio.Scanner) (float64, float64, error) {
-	minVal := math.Inf(1)  // Positive infinity
-	maxVal := math.Inf(-1) // Negative infinity
-	foundNumber := false
+	var r RunningRange
 
 	for scanner.Scan() {
 		line := scanner.Text()
 		if line == "" {
 			continue
 		}
-		val, err := strconv.ParseFloat(line, 64)
+		val, err := ParseHuman(line)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not parse input value '%s', skipping: %v\n", line, err)
 			continue
 		}
-
-		if val < minVal {
-			minVal = val
-		}
-		if val > maxVal {
-			maxVal = val
-		}
-		foundNumber = true
+		r.Add(val)
 	}
 
 	if err := scanner.Err(); err != nil {
 		return 0, 0, fmt.Errorf("error reading from input: %v", err)
 	}
 
-	if !foundNumber {
+	if r.Count == 0 {
 		return 0, 0, fmt.Errorf("no numbers found in input")
 	}
 
-	return minVal, maxVal, nil
+	return r.Min, r.Max, nil
+}
+
+// RunningRange tracks the minimum and maximum of the values seen so far.
+// The zero value is ready to use.
+type RunningRange struct {
+	Min, Max float64
+	Count    int
+}
+
+// Add records a value. NaN values are ignored.
+func (r *RunningRange) Add(val float64) {
+	if math.IsNaN(val) {
+		return
+	}
+	if r.Count == 0 || val < r.Min {
+		r.Min = val
+	}
+	if r.Count == 0 || val > r.Max {
+		r.Max = val
+	}
+	r.Count++
 }
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"math"
+	"math/big"
 	"math/rand"
 	"testing"
 )
@@ -70,6 +71,40 @@ func TestLimit(t *testing.T) {
 	}
 }
 
+func TestZeroTolerance(t *testing.T) {
+	defer func(saved Tolerance) { ZeroTolerance = saved }(ZeroTolerance)
+	tests := []struct {
+		name    string
+		tol     Tolerance
+		val     float64
+		a       float64
+		b       float64
+		want    float64
+		wantErr bool
+	}{
+		{"default collapses a tiny interval", Tolerance{Epsilon: 1e-15}, 1.5e-16, 1e-16, 2e-16, 0, false},
+		{"zero epsilon accepts a tiny interval", Tolerance{}, 1.5e-16, 1e-16, 2e-16, 0.5, false},
+		{"zero epsilon rejects an empty interval", Tolerance{}, 1, 0, 0, 0, true},
+		{"zero epsilon accepts the value of an empty interval", Tolerance{}, 3, 3, 3, 0, false},
+		{"larger epsilon", Tolerance{Epsilon: 1e-3}, 1, 1, 1.0001, 0, false},
+		{"relative accepts a tiny interval", Tolerance{Epsilon: 1e-9, Relative: true}, 1.5e-16, 1e-16, 2e-16, 0.5, false},
+		{"relative collapses a narrow interval of large values", Tolerance{Epsilon: 1e-9, Relative: true}, 1e12, 1e12, 1e12 + 1e-3, 0, false},
+		{"relative collapsed interval, value off it", Tolerance{Epsilon: 1e-9, Relative: true}, 2e12, 1e12, 1e12 + 1e-3, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ZeroTolerance = tt.tol
+			got, err := Deval(tt.val, tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Deval() with %+v error = %v, wantErr %v", tt.tol, err, tt.wantErr)
+			}
+			if !tt.wantErr && !almostEqual(got, tt.want) {
+				t.Errorf("Deval() with %+v = %v, want %v", tt.tol, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestEval(t *testing.T) {
 	tests := []struct {
 		name string
@@ -105,6 +140,49 @@ func TestEval(t *testing.T) {
 			}
 		})
 	}
+
+	// The ends are exact, even when a + (b-a) rounds away from b.
+	for _, ends := range [][2]float64{{1e16, 1}, {0.7, 0.9}, {-1e300, 1e300}, {3, -1e-300}} {
+		a, b := ends[0], ends[1]
+		if got := Eval(0, a, b); got != a {
+			t.Errorf("Eval(0, %v, %v) = %v, want exactly %v", a, b, got, a)
+		}
+		if got := Eval(1, a, b); got != b {
+			t.Errorf("Eval(1, %v, %v) = %v, want exactly %v", a, b, got, b)
+		}
+	}
+}
+
+func TestEvalVector(t *testing.T) {
+	tests := []struct {
+		name string
+		t    float64
+		a, b []float64
+		want []float64
+	}{
+		{"midpoint", 0.5, []float64{0, 10, -4}, []float64{100, 20, 4}, []float64{50, 15, 0}},
+		{"start", 0, []float64{1, 2}, []float64{3, 4}, []float64{1, 2}},
+		{"outside", 2, []float64{0}, []float64{1}, []float64{2}},
+		{"empty", 0.5, []float64{}, []float64{}, []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvalVector(tt.t, tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("EvalVector() returned an unexpected error: %v", err)
+			}
+			if !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("EvalVector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("different lengths", func(t *testing.T) {
+		if _, err := EvalVector(0.5, []float64{1, 2}, []float64{1}); err == nil {
+			t.Error("EvalVector() expected an error for vectors of different lengths, but got nil")
+		}
+	})
 }
 
 func TestDeval(t *testing.T) {
@@ -195,6 +273,16 @@ func TestRemap(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("exact ends", func(t *testing.T) {
+		srcA, srcB, dstA, dstB := 0.1, 0.3, 1e16, 1.0
+		if got, _ := Remap(srcA, srcA, srcB, dstA, dstB); got != dstA {
+			t.Errorf("Remap(srcA) = %v, want exactly %v", got, dstA)
+		}
+		if got, _ := Remap(srcB, srcA, srcB, dstA, dstB); got != dstB {
+			t.Errorf("Remap(srcB) = %v, want exactly %v", got, dstB)
+		}
+	})
 }
 
 func TestSnap(t *testing.T) {
@@ -273,6 +361,24 @@ func TestDivide(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("no drift over long sequences", func(t *testing.T) {
+		const steps = 1000000
+		a, b := 0.1, 0.7
+		got, err := Divide(steps, a, b)
+		if err != nil {
+			t.Fatalf("Divide() returned an unexpected error: %v", err)
+		}
+		// Each point is within a few ulps of a + (b-a)*i/steps, computed exactly.
+		exactA := new(big.Float).SetFloat64(a)
+		exactDelta := new(big.Float).Sub(new(big.Float).SetFloat64(b), exactA)
+		for _, i := range []int{1, steps / 3, steps / 2, steps - 1} {
+			want, _ := new(big.Float).Add(exactA, new(big.Float).Quo(new(big.Float).Mul(exactDelta, big.NewFloat(float64(i))), big.NewFloat(steps))).Float64()
+			if ulps := math.Abs(got[i]-want) / (math.Nextafter(want, math.Inf(1)) - want); ulps > 2 {
+				t.Errorf("Divide()[%d] = %v, want %v (%.0f ulps away)", i, got[i], want, ulps)
+			}
+		}
+	})
 }
 
 func TestRandom(t *testing.T) {
@@ -378,6 +484,72 @@ func TestSubintervals(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("no drift over long sequences", func(t *testing.T) {
+		const steps = 1000000
+		a, b := 0.1, 0.7
+		got, err := Subintervals(steps, a, b)
+		if err != nil {
+			t.Fatalf("Subintervals() returned an unexpected error: %v", err)
+		}
+		for i := 0; i < steps-1; i++ {
+			if got[i][1] != got[i+1][0] {
+				t.Fatalf("Subintervals()[%d] ends at %v, but [%d] starts at %v", i, got[i][1], i+1, got[i+1][0])
+			}
+		}
+		// The last start is within a few ulps of a + (b-a)*(steps-1)/steps, computed exactly.
+		exactA := new(big.Float).SetFloat64(a)
+		exactDelta := new(big.Float).Sub(new(big.Float).SetFloat64(b), exactA)
+		want, _ := new(big.Float).Add(exactA, new(big.Float).Quo(new(big.Float).Mul(exactDelta, big.NewFloat(steps-1)), big.NewFloat(steps))).Float64()
+		if last := got[steps-1][0]; math.Abs(last-want)/(math.Nextafter(want, math.Inf(1))-want) > 2 {
+			t.Errorf("Subintervals()[%d] starts at %v, want %v", steps-1, last, want)
+		}
+	})
+}
+
+func TestSubintervalsOverlap(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   int
+		a       float64
+		b       float64
+		overlap float64
+		want    [][2]float64
+		wantErr bool
+	}{
+		{"no overlap matches subintervals", 2, 0, 1, 0, [][2]float64{{0, 0.5}, {0.5, 1}}, false},
+		{"half overlap", 3, 0, 100, 0.5, [][2]float64{{0, 50}, {25, 75}, {50, 100}}, false},
+		{"single window spans interval", 1, 0, 100, 0.5, [][2]float64{{0, 100}}, false},
+		{"inverted interval", 3, 100, 0, 0.5, [][2]float64{{100, 50}, {75, 25}, {50, 0}}, false},
+		{"zero steps", 0, 0, 10, 0.5, [][2]float64{}, false},
+		{"zero delta", 2, 10, 10, 0.5, [][2]float64{{10, 10}, {10, 10}}, false},
+		{"overlap of one", 3, 0, 10, 1, nil, true},
+		{"negative overlap", 3, 0, 10, -0.1, nil, true},
+		{"overlap is NaN", 3, 0, 10, math.NaN(), nil, true},
+		{"negative steps", -1, 0, 10, 0.5, nil, true},
+		{"a is NaN", 3, math.NaN(), 10, 0.5, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SubintervalsOverlap(tt.steps, tt.a, tt.b, tt.overlap)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SubintervalsOverlap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("SubintervalsOverlap() len = %v, want %v", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !almostEqual(got[i][0], tt.want[i][0]) || !almostEqual(got[i][1], tt.want[i][1]) {
+					t.Errorf("SubintervalsOverlap() got[%d] = %v, want[%d] = %v", i, got[i], i, tt.want[i])
+				}
+			}
+		})
+	}
 }
 
 func TestEncompass(t *testing.T) {
@@ -397,6 +569,8 @@ func TestEncompass(t *testing.T) {
 		{"mixed valid and invalid", "1\nfoo\n2\nbar\n3", 1, 3, false},
 		{"decreasing order", "10\n5\n1", 1, 10, false},
 		{"zero delta", "5\n5\n5", 5, 5, false},
+		{"NaN is ignored", "nan\n4\nNaN\n2", 2, 4, false},
+		{"only NaN", "nan\nnan", 0, 0, true},
 	}
 
 	for _, tt := range tests {
@@ -420,3 +594,16 @@ func TestEncompass(t *testing.T) {
 		})
 	}
 }
+
+func TestRunningRange(t *testing.T) {
+	var r RunningRange
+	if r.Count != 0 {
+		t.Fatalf("zero RunningRange Count = %v, want 0", r.Count)
+	}
+	for _, v := range []float64{5, -2, math.NaN(), 9, 3} {
+		r.Add(v)
+	}
+	if r.Count != 4 || r.Min != -2 || r.Max != 9 {
+		t.Errorf("RunningRange = %+v, want {Min:-2 Max:9 Count:4}", r)
+	}
+}
@@ -0,0 +1,95 @@
+package interval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// NaNPolicy controls how NaN and infinite input values are treated.
+type NaNPolicy int
+
+const (
+	// NaNDefault leaves non-finite values to each operation: most reject them
+	// with an error, Limit clamps infinities, and the statistics ignore NaN.
+	NaNDefault NaNPolicy = iota
+	// NaNSkip drops non-finite values.
+	NaNSkip
+	// NaNZero replaces non-finite values with 0.
+	NaNZero
+	// NaNClamp replaces infinities with the bounds of the interval, and drops NaN.
+	NaNClamp
+	// NaNPropagate passes non-finite values through unchanged.
+	NaNPropagate
+	// NaNError treats a non-finite value as a fatal error.
+	NaNError
+)
+
+// ErrSkipped is returned for a value that the policy drops.
+var ErrSkipped = errors.New("value skipped")
+
+// ErrNonFinite is returned for a value that the NaNError policy rejects.
+var ErrNonFinite = errors.New("non-finite value")
+
+var nanPolicies = map[string]NaNPolicy{
+	"skip":      NaNSkip,
+	"zero":      NaNZero,
+	"clamp":     NaNClamp,
+	"propagate": NaNPropagate,
+	"error":     NaNError,
+}
+
+// ParseNaNPolicy translates a policy name (skip, zero, clamp, propagate, error)
+// into a NaNPolicy.
+func ParseNaNPolicy(name string) (NaNPolicy, error) {
+	policy, ok := nanPolicies[name]
+	if !ok {
+		return NaNDefault, fmt.Errorf("unknown NaN policy: %s (expected skip, zero, clamp, propagate or error)", name)
+	}
+	return policy, nil
+}
+
+// Resolve returns the value to use in place of val, which is returned as is when
+// it is finite. lo and hi are the bounds used by NaNClamp. It returns ErrSkipped
+// if the value should be dropped, and an error wrapping ErrNonFinite under NaNError.
+func (p NaNPolicy) Resolve(val, lo, hi float64) (float64, error) {
+	if !math.IsNaN(val) && !math.IsInf(val, 0) {
+		return val, nil
+	}
+	switch p {
+	case NaNSkip:
+		return 0, ErrSkipped
+	case NaNZero:
+		return 0, nil
+	case NaNClamp:
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		switch {
+		case math.IsInf(val, 1):
+			return hi, nil
+		case math.IsInf(val, -1):
+			return lo, nil
+		}
+		return 0, ErrSkipped
+	case NaNError:
+		return 0, fmt.Errorf("%w: %v", ErrNonFinite, val)
+	}
+	return val, nil
+}
+
+// Apply wraps a per-value operation so that its non-finite inputs are handled
+// according to the policy. Under NaNPropagate, they bypass the operation and are
+// returned unchanged.
+func (p NaNPolicy) Apply(fn func(float64) (float64, error), lo, hi float64) func(float64) (float64, error) {
+	return func(val float64) (float64, error) {
+		if p == NaNPropagate && (math.IsNaN(val) || math.IsInf(val, 0)) {
+			return val, nil
+		}
+		resolved, err := p.Resolve(val, lo, hi)
+		if err != nil {
+			return 0, err
+		}
+		return fn(resolved)
+	}
+}
@@ -0,0 +1,73 @@
+package interval
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseNaNPolicy(t *testing.T) {
+	for name, want := range nanPolicies {
+		got, err := ParseNaNPolicy(name)
+		if err != nil || got != want {
+			t.Errorf("ParseNaNPolicy(%q) = %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseNaNPolicy("ignore"); err == nil {
+		t.Error("ParseNaNPolicy() expected an error for an unknown policy, but got nil")
+	}
+}
+
+func TestNaNPolicyResolve(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+
+	tests := []struct {
+		name    string
+		policy  NaNPolicy
+		val     float64
+		want    float64
+		wantErr error
+	}{
+		{"finite value untouched", NaNError, 5, 5, nil},
+		{"default passes NaN", NaNDefault, nan, nan, nil},
+		{"skip NaN", NaNSkip, nan, 0, ErrSkipped},
+		{"skip Inf", NaNSkip, inf, 0, ErrSkipped},
+		{"zero NaN", NaNZero, nan, 0, nil},
+		{"zero -Inf", NaNZero, -inf, 0, nil},
+		{"clamp +Inf", NaNClamp, inf, 10, nil},
+		{"clamp -Inf", NaNClamp, -inf, 0, nil},
+		{"clamp NaN is skipped", NaNClamp, nan, 0, ErrSkipped},
+		{"propagate Inf", NaNPropagate, inf, inf, nil},
+		{"error NaN", NaNError, nan, 0, ErrNonFinite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Bounds given in reverse order to check they are sorted.
+			got, err := tt.policy.Resolve(tt.val, 10, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !(got == tt.want || math.IsNaN(got) && math.IsNaN(tt.want)) {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNaNPolicyApply(t *testing.T) {
+	remap := func(val float64) (float64, error) { return Remap(val, 0, 10, 0, 100) }
+
+	if got, err := NaNClamp.Apply(remap, 0, 10)(math.Inf(1)); err != nil || got != 100 {
+		t.Errorf("clamp: Apply()(+Inf) = %v, %v, want 100", got, err)
+	}
+	if got, err := NaNPropagate.Apply(remap, 0, 10)(math.NaN()); err != nil || !math.IsNaN(got) {
+		t.Errorf("propagate: Apply()(NaN) = %v, %v, want NaN", got, err)
+	}
+	if _, err := NaNDefault.Apply(remap, 0, 10)(math.NaN()); err == nil {
+		t.Error("default: Apply()(NaN) expected the error from Remap, but got nil")
+	}
+	if got, err := NaNZero.Apply(remap, 0, 10)(5); err != nil || got != 50 {
+		t.Errorf("zero: Apply()(5) = %v, %v, want 50", got, err)
+	}
+}
@@ -0,0 +1,117 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Interval is an interval of the real line whose bounds may each be open or
+// closed, as written in bracket notation: "[0, 10)" holds 0 but not 10.
+type Interval struct {
+	Lo, Hi         float64
+	LoOpen, HiOpen bool
+}
+
+// Parse parses an interval in bracket notation: a '[' or '(' (closed or open
+// lower bound), the bounds separated by a comma, and a ']' or ')'. Bounds may
+// be infinite, written as inf, -inf, +inf or ∞; an infinite bound is always
+// open. The lower bound must not be greater than the upper one, and the
+// interval must not be empty.
+func Parse(s string) (Interval, error) {
+	trimmed := strings.TrimSpace(s)
+	if len(trimmed) < 2 {
+		return Interval{}, fmt.Errorf("invalid interval: %q (expected e.g. [0, 10))", s)
+	}
+	var iv Interval
+	switch trimmed[0] {
+	case '[':
+	case '(':
+		iv.LoOpen = true
+	default:
+		return Interval{}, fmt.Errorf("invalid interval: %q (expected [ or ( first)", s)
+	}
+	switch trimmed[len(trimmed)-1] {
+	case ']':
+	case ')':
+		iv.HiOpen = true
+	default:
+		return Interval{}, fmt.Errorf("invalid interval: %q (expected ] or ) last)", s)
+	}
+	lo, hi, ok := strings.Cut(trimmed[1:len(trimmed)-1], ",")
+	if !ok {
+		return Interval{}, fmt.Errorf("invalid interval: %q (expected two bounds separated by a comma)", s)
+	}
+	var err error
+	if iv.Lo, err = parseBound(lo); err != nil {
+		return Interval{}, fmt.Errorf("invalid interval: %q: %v", s, err)
+	}
+	if iv.Hi, err = parseBound(hi); err != nil {
+		return Interval{}, fmt.Errorf("invalid interval: %q: %v", s, err)
+	}
+	iv.LoOpen = iv.LoOpen || math.IsInf(iv.Lo, 0)
+	iv.HiOpen = iv.HiOpen || math.IsInf(iv.Hi, 0)
+
+	if iv.Lo > iv.Hi {
+		return Interval{}, fmt.Errorf("invalid interval: %q (the lower bound is greater than the upper one)", s)
+	}
+	if iv.Lo == iv.Hi && (iv.LoOpen || iv.HiOpen) {
+		return Interval{}, fmt.Errorf("invalid interval: %q is empty", s)
+	}
+	return iv, nil
+}
+
+// parseBound parses a bound of an interval, which may be infinite.
+func parseBound(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "∞", "+∞":
+		return math.Inf(1), nil
+	case "-∞":
+		return math.Inf(-1), nil
+	}
+	val, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(val) {
+		return 0, fmt.Errorf("invalid bound: %q", s)
+	}
+	return val, nil
+}
+
+// String returns the interval in bracket notation.
+func (iv Interval) String() string {
+	open, close := "[", "]"
+	if iv.LoOpen {
+		open = "("
+	}
+	if iv.HiOpen {
+		close = ")"
+	}
+	return open + strconv.FormatFloat(iv.Lo, 'g', -1, 64) + ", " + strconv.FormatFloat(iv.Hi, 'g', -1, 64) + close
+}
+
+// Contains reports whether val lies within the interval, honoring open bounds.
+func (iv Interval) Contains(val float64) bool {
+	aboveLo := val > iv.Lo || (!iv.LoOpen && val == iv.Lo)
+	belowHi := val < iv.Hi || (!iv.HiOpen && val == iv.Hi)
+	return aboveLo && belowHi
+}
+
+// Limit restricts val to the interval, as the package-level Limit does. A value
+// beyond an open bound is moved to the closest number inside it, the next
+// floating-point number past the bound. NaN is returned unchanged.
+func (iv Interval) Limit(val float64) float64 {
+	if math.IsNaN(val) || iv.Contains(val) {
+		return val
+	}
+	if val <= iv.Lo {
+		if iv.LoOpen {
+			return math.Nextafter(iv.Lo, math.Inf(1))
+		}
+		return iv.Lo
+	}
+	if iv.HiOpen {
+		return math.Nextafter(iv.Hi, math.Inf(-1))
+	}
+	return iv.Hi
+}
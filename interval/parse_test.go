@@ -0,0 +1,99 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  Interval
+	}{
+		{"[0, 10]", Interval{Lo: 0, Hi: 10}},
+		{"[0, 10)", Interval{Lo: 0, Hi: 10, HiOpen: true}},
+		{"(0,10]", Interval{Lo: 0, Hi: 10, LoOpen: true}},
+		{" ( -2.5 , 1e3 ) ", Interval{Lo: -2.5, Hi: 1000, LoOpen: true, HiOpen: true}},
+		{"[-inf, 5]", Interval{Lo: math.Inf(-1), Hi: 5, LoOpen: true}},
+		{"[0, +inf]", Interval{Lo: 0, Hi: math.Inf(1), HiOpen: true}},
+		{"(-∞, ∞)", Interval{Lo: math.Inf(-1), Hi: math.Inf(1), LoOpen: true, HiOpen: true}},
+		{"[3, 3]", Interval{Lo: 3, Hi: 3}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := Parse(tc.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned an unexpected error: %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tc.input, got, tc.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "0, 10", "[0 10]", "{0, 10}", "[0, 10", "[a, 10]", "[nan, 1]", "[10, 0]", "[3, 3)", "(3, 3]"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected an error", input)
+		}
+	}
+}
+
+func TestIntervalString(t *testing.T) {
+	for _, input := range []string{"[0, 10)", "(0, 10]", "(-Inf, 5]", "[1.5, 2]"} {
+		iv, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned an unexpected error: %v", input, err)
+		}
+		if got := iv.String(); got != input {
+			t.Errorf("Parse(%q).String() = %q", input, got)
+		}
+	}
+}
+
+func TestIntervalContains(t *testing.T) {
+	iv := Interval{Lo: 0, Hi: 10, HiOpen: true}
+	testCases := []struct {
+		val  float64
+		want bool
+	}{
+		{0, true},
+		{5, true},
+		{10, false},
+		{-1, false},
+		{math.NaN(), false},
+	}
+	for _, tc := range testCases {
+		if got := iv.Contains(tc.val); got != tc.want {
+			t.Errorf("%v.Contains(%v) = %v, want %v", iv, tc.val, got, tc.want)
+		}
+	}
+	if (Interval{Lo: 0, Hi: 10, LoOpen: true}).Contains(0) {
+		t.Error("(0, 10].Contains(0) = true, want false")
+	}
+}
+
+func TestIntervalLimit(t *testing.T) {
+	testCases := []struct {
+		name string
+		iv   Interval
+		val  float64
+		want float64
+	}{
+		{"Inside", Interval{Lo: 0, Hi: 10}, 5, 5},
+		{"Closed lower bound", Interval{Lo: 0, Hi: 10}, -5, 0},
+		{"Closed upper bound", Interval{Lo: 0, Hi: 10}, 15, 10},
+		{"Open upper bound", Interval{Lo: 0, Hi: 10, HiOpen: true}, 10, math.Nextafter(10, 0)},
+		{"Open lower bound", Interval{Lo: 0, Hi: 10, LoOpen: true}, -5, math.Nextafter(0, 1)},
+		{"Infinite bound", Interval{Lo: math.Inf(-1), Hi: 10, LoOpen: true}, -1e300, -1e300},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.iv.Limit(tc.val); got != tc.want {
+				t.Errorf("%v.Limit(%v) = %v, want %v", tc.iv, tc.val, got, tc.want)
+			}
+		})
+	}
+	if got := (Interval{Lo: 0, Hi: 1}).Limit(math.NaN()); !math.IsNaN(got) {
+		t.Errorf("Limit(NaN) = %v, want NaN", got)
+	}
+}
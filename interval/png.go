@@ -0,0 +1,69 @@
+package interval
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// GeneratePNG renders the numbers read from scanner as a PNG image with a
+// transparent background, laid out as GenerateSVG does: a line through the
+// values over a lighter area down to the bottom edge. A single value draws a
+// flat line. Without numbers, the image is blank.
+func GeneratePNG(scanner *bufio.Scanner, writer io.Writer, config ImageConfig) error {
+	points, err := readImagePoints(scanner, config)
+	if err != nil {
+		return err
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, config.Width, config.Height))
+	c := config.Color
+	line := color.NRGBA{c.R, c.G, c.B, 255}
+	area := color.NRGBA{c.R, c.G, c.B, 51} // The 0.2 opacity of the SVG area.
+
+	if len(points) > 0 {
+		prev := -1
+		for px := 0; px < config.Width; px++ {
+			y := int(math.Round(lineAt(points, float64(px)+0.5)))
+			y = int(Limit(float64(y), 0, float64(config.Height-1)))
+			for py := y + 1; py < config.Height; py++ {
+				img.SetNRGBA(px, py, area)
+			}
+			// Join the previous column, so steep slopes stay connected.
+			from, to := y, y
+			if prev >= 0 {
+				from, to = minMax(prev, y)
+			}
+			for py := from; py <= to; py++ {
+				img.SetNRGBA(px, py, line)
+			}
+			prev = y
+		}
+	}
+	return png.Encode(writer, img)
+}
+
+// lineAt returns the height of the polyline through points at x, holding the
+// first and last heights beyond the ends.
+func lineAt(points [][2]float64, x float64) float64 {
+	if x <= points[0][0] {
+		return points[0][1]
+	}
+	for i := 1; i < len(points); i++ {
+		if x <= points[i][0] {
+			a, b := points[i-1], points[i]
+			return Eval((x-a[0])/(b[0]-a[0]), a[1], b[1])
+		}
+	}
+	return points[len(points)-1][1]
+}
+
+func minMax(a, b int) (int, int) {
+	if a > b {
+		return b, a
+	}
+	return a, b
+}
@@ -0,0 +1,63 @@
+package interval
+
+import (
+	"bufio"
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePNG(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("0 10"))
+	var writer bytes.Buffer
+	config := ImageConfig{Width: 10, Height: 12, Color: RGB{255, 0, 0}}
+	if err := GeneratePNG(scanner, &writer, config); err != nil {
+		t.Fatalf("GeneratePNG() returned an unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(&writer)
+	if err != nil {
+		t.Fatalf("png.Decode() returned an unexpected error: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 10 || size.Y != 12 {
+		t.Fatalf("image size = %v, want 10x12", size)
+	}
+
+	line := color.NRGBA{255, 0, 0, 255}
+	area := color.NRGBA{255, 0, 0, 51}
+	blank := color.NRGBA{}
+	testCases := []struct {
+		name string
+		x, y int
+		want color.NRGBA
+	}{
+		{"Line at the low end", 0, 11, line},
+		{"Line at the high end", 9, 2, line},
+		{"Area under the line", 9, 8, area},
+		{"Blank above the line", 0, 2, blank},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := color.NRGBAModel.Convert(img.At(tc.x, tc.y)).(color.NRGBA); got != tc.want {
+				t.Errorf("At(%d, %d) = %v, want %v", tc.x, tc.y, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGeneratePNGEmpty(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader(""))
+	var writer bytes.Buffer
+	if err := GeneratePNG(scanner, &writer, ImageConfig{Width: 4, Height: 4}); err != nil {
+		t.Fatalf("GeneratePNG() returned an unexpected error: %v", err)
+	}
+	img, err := png.Decode(&writer)
+	if err != nil {
+		t.Fatalf("png.Decode() returned an unexpected error: %v", err)
+	}
+	if got := color.NRGBAModel.Convert(img.At(2, 2)).(color.NRGBA); got != (color.NRGBA{}) {
+		t.Errorf("At(2, 2) = %v, want a blank pixel", got)
+	}
+}
@@ -0,0 +1,123 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ThresholdScale maps values to buckets by explicit break points, to pick one of
+// a discrete set of outputs such as severity levels: the values below the first
+// threshold fall into bucket 0, those from threshold i-1 up to threshold i into
+// bucket i, and those from the last threshold on into the last bucket.
+type ThresholdScale struct {
+	thresholds []float64
+}
+
+// NewThresholdScale returns a threshold scale over thresholds, which must be
+// finite and ascending. It has one more bucket than there are thresholds.
+func NewThresholdScale(thresholds []float64) (*ThresholdScale, error) {
+	if len(thresholds) == 0 {
+		return nil, fmt.Errorf("a threshold scale needs at least one threshold")
+	}
+	for i, t := range thresholds {
+		if math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, fmt.Errorf("invalid threshold %v (expected a finite number)", t)
+		}
+		if i > 0 && t <= thresholds[i-1] {
+			return nil, fmt.Errorf("thresholds must be in ascending order (%v does not follow %v)", t, thresholds[i-1])
+		}
+	}
+	return &ThresholdScale{thresholds: append([]float64(nil), thresholds...)}, nil
+}
+
+// Buckets returns the number of buckets.
+func (s *ThresholdScale) Buckets() int {
+	return len(s.thresholds) + 1
+}
+
+// Bucket returns the index of the bucket holding val. NaN values are an error.
+func (s *ThresholdScale) Bucket(val float64) (int, error) {
+	if math.IsNaN(val) {
+		return 0, fmt.Errorf("cannot bucket: NaN values are not supported")
+	}
+	// The number of thresholds at or below the value.
+	return sort.Search(len(s.thresholds), func(i int) bool { return s.thresholds[i] > val }), nil
+}
+
+// Extent returns the bounds of bucket i. The first bucket starts at -Inf and
+// the last one ends at +Inf.
+func (s *ThresholdScale) Extent(i int) ([2]float64, error) {
+	if i < 0 || i >= s.Buckets() {
+		return [2]float64{}, fmt.Errorf("bucket %d out of range (expected 0 to %d)", i, s.Buckets()-1)
+	}
+	extent := [2]float64{math.Inf(-1), math.Inf(1)}
+	if i > 0 {
+		extent[0] = s.thresholds[i-1]
+	}
+	if i < len(s.thresholds) {
+		extent[1] = s.thresholds[i]
+	}
+	return extent, nil
+}
+
+// QuantizeScale cuts the interval [a, b] into equal buckets, and maps a value to
+// the index of the one holding it, to pick one of a discrete set of outputs such
+// as letter grades. Values outside the interval fall into the bucket at its
+// nearest end, and a value on the boundary of two buckets into the one farther
+// from a.
+type QuantizeScale struct {
+	a, b      float64
+	threshold *ThresholdScale // The inner boundaries, in ascending order.
+}
+
+// NewQuantizeScale returns a quantize scale cutting [a, b] into buckets equal
+// buckets.
+func NewQuantizeScale(a, b float64, buckets int) (*QuantizeScale, error) {
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return nil, fmt.Errorf("cannot quantize: the bounds must be finite")
+	}
+	if a == b {
+		return nil, fmt.Errorf("cannot quantize an interval with zero delta")
+	}
+	if buckets <= 0 {
+		return nil, fmt.Errorf("buckets must be a positive integer")
+	}
+	s := &QuantizeScale{a: a, b: b, threshold: &ThresholdScale{}}
+	for i := 1; i < buckets; i++ {
+		s.threshold.thresholds = append(s.threshold.thresholds, Eval(float64(i)/float64(buckets), min(a, b), max(a, b)))
+	}
+	return s, nil
+}
+
+// Buckets returns the number of buckets.
+func (s *QuantizeScale) Buckets() int {
+	return s.threshold.Buckets()
+}
+
+// Bucket returns the index of the bucket holding val, counted from a. NaN
+// values are an error.
+func (s *QuantizeScale) Bucket(val float64) (int, error) {
+	if math.IsNaN(val) {
+		return 0, fmt.Errorf("cannot quantize: NaN values are not supported")
+	}
+	i, _ := s.threshold.Bucket(val)
+	if s.a > s.b {
+		// A boundary belongs to the bucket farther from a.
+		i = sort.Search(len(s.threshold.thresholds), func(j int) bool { return s.threshold.thresholds[j] >= val })
+		i = s.Buckets() - 1 - i
+	}
+	return i, nil
+}
+
+// Extent returns the bounds of bucket i, in the direction of the interval.
+func (s *QuantizeScale) Extent(i int) ([2]float64, error) {
+	if i < 0 || i >= s.Buckets() {
+		return [2]float64{}, fmt.Errorf("bucket %d out of range (expected 0 to %d)", i, s.Buckets()-1)
+	}
+	bounds := append(append([]float64{min(s.a, s.b)}, s.threshold.thresholds...), max(s.a, s.b))
+	if s.a > s.b {
+		return [2]float64{bounds[len(bounds)-1-i], bounds[len(bounds)-2-i]}, nil
+	}
+	return [2]float64{bounds[i], bounds[i+1]}, nil
+}
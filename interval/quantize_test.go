@@ -0,0 +1,83 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestThresholdScale(t *testing.T) {
+	s, err := NewThresholdScale([]float64{50, 70, 90})
+	if err != nil {
+		t.Fatalf("NewThresholdScale() returned an unexpected error: %v", err)
+	}
+	for val, want := range map[float64]int{math.Inf(-1): 0, 12: 0, 50: 1, 69.9: 1, 70: 2, 90: 3, 1e9: 3} {
+		if got, err := s.Bucket(val); err != nil || got != want {
+			t.Errorf("Bucket(%v) = %v, %v, want %v", val, got, err, want)
+		}
+	}
+	if _, err := s.Bucket(math.NaN()); err == nil {
+		t.Error("Bucket(NaN) expected an error, but got nil")
+	}
+
+	if got, _ := s.Extent(0); got != [2]float64{math.Inf(-1), 50} {
+		t.Errorf("Extent(0) = %v, want [-Inf 50]", got)
+	}
+	if got, _ := s.Extent(2); got != [2]float64{70, 90} {
+		t.Errorf("Extent(2) = %v, want [70 90]", got)
+	}
+	if _, err := s.Extent(4); err == nil {
+		t.Error("Extent(4) expected an error, but got nil")
+	}
+
+	for _, thresholds := range [][]float64{nil, {1, 1}, {2, 1}, {0, math.NaN()}} {
+		if _, err := NewThresholdScale(thresholds); err == nil {
+			t.Errorf("NewThresholdScale(%v) expected an error, but got nil", thresholds)
+		}
+	}
+}
+
+func TestQuantizeScale(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    float64
+		buckets int
+		values  map[float64]int
+	}{
+		{"equal buckets", 0, 100, 4, map[float64]int{0: 0, 24.9: 0, 25: 1, 50: 2, 99: 3, 100: 3}},
+		{"outside the interval", 0, 100, 4, map[float64]int{-5: 0, 250: 3, math.Inf(1): 3}},
+		{"boundaries of tenths", 0, 1, 10, map[float64]int{0.1: 1, 0.3: 3, 0.7: 7, 0.9: 9}},
+		{"inverted interval", 100, 0, 4, map[float64]int{100: 0, 75: 1, 60: 1, 50: 2, 0: 3, -1: 3}},
+		{"one bucket", 0, 1, 1, map[float64]int{-1: 0, 0.5: 0, 2: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewQuantizeScale(tt.a, tt.b, tt.buckets)
+			if err != nil {
+				t.Fatalf("NewQuantizeScale() returned an unexpected error: %v", err)
+			}
+			for val, want := range tt.values {
+				if got, err := s.Bucket(val); err != nil || got != want {
+					t.Errorf("Bucket(%v) = %v, %v, want %v", val, got, err, want)
+				}
+			}
+		})
+	}
+
+	t.Run("extents", func(t *testing.T) {
+		s, _ := NewQuantizeScale(10, 0, 4)
+		if got, _ := s.Extent(0); got != [2]float64{10, 7.5} {
+			t.Errorf("Extent(0) = %v, want [10 7.5]", got)
+		}
+		if got, _ := s.Extent(3); got != [2]float64{2.5, 0} {
+			t.Errorf("Extent(3) = %v, want [2.5 0]", got)
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for _, args := range [][3]float64{{0, 0, 2}, {0, 1, 0}, {math.NaN(), 1, 2}, {0, math.Inf(1), 2}} {
+			if _, err := NewQuantizeScale(args[0], args[1], int(args[2])); err == nil {
+				t.Errorf("NewQuantizeScale(%v, %v, %v) expected an error, but got nil", args[0], args[1], args[2])
+			}
+		}
+	})
+}
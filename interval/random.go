@@ -0,0 +1,430 @@
+package interval
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RandomNormal generates a sequence of normally distributed random numbers with the
+// given mean and standard deviation.
+func RandomNormal(r *rand.Rand, count int, mean, stddev float64) ([]float64, error) {
+	if err := checkNormalArgs(count, mean, stddev); err != nil {
+		return nil, err
+	}
+
+	results := make([]float64, count)
+	for i := range results {
+		results[i] = mean + stddev*r.NormFloat64()
+	}
+
+	return results, nil
+}
+
+// RandomNormalTruncated generates a sequence of normally distributed random numbers,
+// truncated to the interval [a, b]. Samples are drawn by inverting the normal CDF
+// over the interval, so values are never clamped and no draws are rejected.
+func RandomNormalTruncated(r *rand.Rand, count int, mean, stddev, a, b float64) ([]float64, error) {
+	if err := checkNormalArgs(count, mean, stddev); err != nil {
+		return nil, err
+	}
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return nil, fmt.Errorf("cannot generate random values: NaN bounds")
+	}
+
+	lo, hi := a, b
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	results := make([]float64, count)
+	if stddev == 0 {
+		for i := range results {
+			results[i] = Limit(mean, lo, hi)
+		}
+		return results, nil
+	}
+
+	cdfLo := normalCDF((lo - mean) / stddev)
+	cdfHi := normalCDF((hi - mean) / stddev)
+	for i := range results {
+		if cdfHi <= cdfLo {
+			// The interval carries no measurable probability mass (far in a tail):
+			// the best we can do is the bound nearest to the mean.
+			results[i] = Limit(mean, lo, hi)
+			continue
+		}
+		u := cdfLo + r.Float64()*(cdfHi-cdfLo)
+		results[i] = Limit(mean+stddev*normalQuantile(u), lo, hi)
+	}
+
+	return results, nil
+}
+
+func checkNormalArgs(count int, mean, stddev float64) error {
+	if math.IsNaN(mean) || math.IsNaN(stddev) {
+		return fmt.Errorf("cannot generate random values: NaN parameters")
+	}
+	if math.IsInf(mean, 0) || math.IsInf(stddev, 0) {
+		return fmt.Errorf("cannot generate random values: infinite parameters")
+	}
+	if stddev < 0 {
+		return fmt.Errorf("standard deviation cannot be negative")
+	}
+	if count < 0 {
+		return fmt.Errorf("count cannot be negative")
+	}
+	return nil
+}
+
+// normalCDF is the cumulative distribution function of the standard normal distribution.
+func normalCDF(z float64) float64 {
+	return 0.5 * (1 + math.Erf(z/math.Sqrt2))
+}
+
+// normalQuantile is the inverse of normalCDF.
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// RandomInt generates a sequence of uniformly distributed random integers within
+// the interval [a, b], both bounds included.
+func RandomInt(r *rand.Rand, count int, a, b int64) ([]int64, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count cannot be negative")
+	}
+
+	lo, hi := a, b
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	span := uint64(hi-lo) + 1
+	if span == 0 || span > math.MaxInt64 {
+		return nil, fmt.Errorf("cannot generate random integers: interval is too large")
+	}
+
+	results := make([]int64, count)
+	for i := range results {
+		results[i] = lo + r.Int63n(int64(span))
+	}
+
+	return results, nil
+}
+
+// Jitter divides the interval [a, b] into steps equal subintervals and returns one
+// uniformly distributed random point within each of them (stratified sampling).
+func Jitter(r *rand.Rand, steps int, a, b float64) ([]float64, error) {
+	cells, err := Subintervals(steps, a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]float64, len(cells))
+	for i, cell := range cells {
+		point, err := Random(r, 1, cell[0], cell[1])
+		if err != nil {
+			return nil, err
+		}
+		results[i] = point[0]
+	}
+
+	return results, nil
+}
+
+// WeightedBin is a range of values [Lo, Hi] drawn with a relative Weight.
+// A single value has Lo == Hi.
+type WeightedBin struct {
+	Lo, Hi float64
+	Weight float64
+}
+
+// ReadWeights reads weighted bins from a stream. Each line holds either
+// "value weight" or "lo hi count" (bin edges and a count, as produced by a
+// histogram). Blank lines and lines starting with '#' are ignored.
+func ReadWeights(scanner *bufio.Scanner) ([]WeightedBin, error) {
+	var bins []WeightedBin
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 'value weight' or 'lo hi count', got %q", lineNum, line)
+		}
+		nums := make([]float64, len(fields))
+		for i, field := range fields {
+			val, err := strconv.ParseFloat(field, 64)
+			if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+				return nil, fmt.Errorf("line %d: invalid number %q", lineNum, field)
+			}
+			nums[i] = val
+		}
+
+		bin := WeightedBin{Lo: nums[0], Hi: nums[0], Weight: nums[1]}
+		if len(nums) == 3 {
+			bin = WeightedBin{Lo: nums[0], Hi: nums[1], Weight: nums[2]}
+		}
+		if bin.Weight < 0 {
+			return nil, fmt.Errorf("line %d: weight cannot be negative", lineNum)
+		}
+		bins = append(bins, bin)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading from input: %v", err)
+	}
+
+	return bins, nil
+}
+
+// RandomWeighted generates a sequence of random numbers drawn from weighted bins:
+// a bin is picked proportionally to its weight, then a value is drawn uniformly
+// within it.
+func RandomWeighted(r *rand.Rand, count int, bins []WeightedBin) ([]float64, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count cannot be negative")
+	}
+
+	cumulative := make([]float64, len(bins))
+	var sum kahanSum
+	for i, bin := range bins {
+		sum.add(bin.Weight)
+		cumulative[i] = sum.value()
+	}
+	total := sum.value()
+	if total <= 0 {
+		return nil, fmt.Errorf("cannot generate weighted random values: total weight is zero")
+	}
+
+	results := make([]float64, count)
+	for i := range results {
+		target := r.Float64() * total
+		// The first bin whose cumulative weight exceeds the target; zero-weight
+		// bins never qualify.
+		idx := sort.Search(len(cumulative), func(j int) bool { return cumulative[j] > target })
+		if idx == len(cumulative) {
+			idx = len(cumulative) - 1
+		}
+		bin := bins[idx]
+		results[i] = Eval(r.Float64(), bin.Lo, bin.Hi)
+	}
+
+	return results, nil
+}
+
+// Halton generates the first count points of the Halton (van der Corput) low-discrepancy
+// sequence in the given base, evaluated within the interval [a, b]. The points cover
+// the interval more evenly than uniform random numbers. In one dimension, base 2 gives
+// the same points as the Sobol sequence.
+func Halton(count, base int, a, b float64) ([]float64, error) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return nil, fmt.Errorf("cannot generate quasi-random values: NaN bounds")
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return nil, fmt.Errorf("cannot generate quasi-random values: infinite bounds")
+	}
+	if base < 2 {
+		return nil, fmt.Errorf("base must be at least 2")
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("count cannot be negative")
+	}
+
+	results := make([]float64, count)
+	for i := range results {
+		results[i] = Eval(radicalInverse(i+1, base), a, b)
+	}
+
+	return results, nil
+}
+
+// radicalInverse mirrors the digits of n in the given base around the radix point.
+func radicalInverse(n, base int) float64 {
+	inv, f := 0.0, 1.0
+	for n > 0 {
+		f /= float64(base)
+		inv += f * float64(n%base)
+		n /= base
+	}
+	return inv
+}
+
+// DistFunc draws a random parameter 't' in [0, 1] from a distribution. It is
+// evaluated within an interval to produce values scaled into that interval.
+type DistFunc func(r *rand.Rand) float64
+
+// ParseDist translates a distribution spec of the form "name[:p1,p2]" into a DistFunc.
+// Supported distributions and their optional parameters are:
+//
+//	uniform
+//	exponential[:rate]     truncated to [0, 1], rate defaults to 5
+//	lognormal[:sigma]      truncated at 3 sigma in log space, sigma defaults to 0.5
+//	triangular[:mode]      mode as a parameter 't' in [0, 1], defaults to 0.5
+//	beta[:alpha,beta]      shape parameters default to 2, 2
+func ParseDist(spec string) (DistFunc, error) {
+	name, params, err := splitSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(name) {
+	case "uniform":
+		if err := wantParams(name, params, 0); err != nil {
+			return nil, err
+		}
+		return func(r *rand.Rand) float64 { return r.Float64() }, nil
+	case "exponential", "exp":
+		rate, err := paramOr(name, params, 1, 0, 5)
+		if err != nil {
+			return nil, err
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("exponential rate must be positive")
+		}
+		// Exact inverse CDF of the exponential distribution truncated to [0, 1].
+		mass := -math.Expm1(-rate)
+		return func(r *rand.Rand) float64 {
+			return -math.Log1p(-r.Float64()*mass) / rate
+		}, nil
+	case "lognormal":
+		sigma, err := paramOr(name, params, 1, 0, 0.5)
+		if err != nil {
+			return nil, err
+		}
+		if sigma <= 0 {
+			return nil, fmt.Errorf("lognormal sigma must be positive")
+		}
+		// ln(t) = sigma*(z-3) with z a standard normal truncated to z <= 3.
+		top := normalCDF(3)
+		return func(r *rand.Rand) float64 {
+			z := normalQuantile(r.Float64() * top)
+			return math.Exp(sigma * (z - 3))
+		}, nil
+	case "triangular":
+		mode, err := paramOr(name, params, 1, 0, 0.5)
+		if err != nil {
+			return nil, err
+		}
+		if mode < 0 || mode > 1 {
+			return nil, fmt.Errorf("triangular mode must be in the range [0, 1]")
+		}
+		return func(r *rand.Rand) float64 {
+			u := r.Float64()
+			if u < mode {
+				return math.Sqrt(u * mode)
+			}
+			return 1 - math.Sqrt((1-u)*(1-mode))
+		}, nil
+	case "beta":
+		alpha, err := paramOr(name, params, 2, 0, 2)
+		if err != nil {
+			return nil, err
+		}
+		beta, err := paramOr(name, params, 2, 1, 2)
+		if err != nil {
+			return nil, err
+		}
+		if alpha <= 0 || beta <= 0 {
+			return nil, fmt.Errorf("beta shape parameters must be positive")
+		}
+		return func(r *rand.Rand) float64 {
+			x := gammaSample(r, alpha)
+			y := gammaSample(r, beta)
+			return x / (x + y)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown distribution: %s", name)
+	}
+}
+
+// RandomDist generates a sequence of random numbers drawn from a distribution and
+// scaled into the interval [a, b]. The distribution's parameter 't' is measured
+// from a, so skewed distributions are dense near a.
+func RandomDist(r *rand.Rand, count int, a, b float64, dist DistFunc) ([]float64, error) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return nil, fmt.Errorf("cannot generate random values: NaN bounds")
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return nil, fmt.Errorf("cannot generate random values: infinite bounds")
+	}
+	if dist == nil {
+		return nil, fmt.Errorf("cannot generate random values: no distribution given")
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("count cannot be negative")
+	}
+
+	results := make([]float64, count)
+	for i := range results {
+		results[i] = Eval(Limit(dist(r), 0, 1), a, b)
+	}
+
+	return results, nil
+}
+
+// gammaSample draws from a gamma distribution with unit scale using the method
+// of Marsaglia and Tsang.
+func gammaSample(r *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// Boost the shape above 1, then correct with a uniform power.
+		return gammaSample(r, shape+1) * math.Pow(r.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := r.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := r.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// splitSpec splits a "name[:p1,p2,...]" spec into its name and numeric parameters.
+func splitSpec(spec string) (string, []float64, error) {
+	name, rest, hasParams := strings.Cut(spec, ":")
+	if !hasParams {
+		return name, nil, nil
+	}
+	var params []float64
+	for _, field := range strings.Split(rest, ",") {
+		p, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid parameter '%s' for %s", field, name)
+		}
+		params = append(params, p)
+	}
+	return name, params, nil
+}
+
+// paramOr returns the i-th parameter of a spec, or def when it was not given.
+// max is the number of parameters the spec accepts.
+func paramOr(name string, params []float64, max, i int, def float64) (float64, error) {
+	if err := wantParams(name, params, max); err != nil {
+		return 0, err
+	}
+	if i < len(params) {
+		return params[i], nil
+	}
+	return def, nil
+}
+
+func wantParams(name string, params []float64, max int) error {
+	if len(params) > max {
+		return fmt.Errorf("%s takes at most %d parameter(s), got %d", name, max, len(params))
+	}
+	return nil
+}
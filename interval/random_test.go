@@ -0,0 +1,381 @@
+package interval
+
+import (
+	"bufio"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func sampleMean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func TestRandomNormal(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	t.Run("correct count and moments", func(t *testing.T) {
+		results, err := RandomNormal(r, 10000, 50, 5)
+		if err != nil {
+			t.Fatalf("RandomNormal() returned an unexpected error: %v", err)
+		}
+		if len(results) != 10000 {
+			t.Fatalf("RandomNormal() len = %v, want 10000", len(results))
+		}
+		if m := sampleMean(results); math.Abs(m-50) > 0.5 {
+			t.Errorf("RandomNormal() mean = %v, want close to 50", m)
+		}
+	})
+
+	t.Run("zero stddev", func(t *testing.T) {
+		results, _ := RandomNormal(r, 3, 7, 0)
+		if !slicesAlmostEqual(results, []float64{7, 7, 7}) {
+			t.Errorf("RandomNormal() = %v, want all 7", results)
+		}
+	})
+
+	t.Run("negative stddev", func(t *testing.T) {
+		if _, err := RandomNormal(r, 3, 0, -1); err == nil {
+			t.Error("RandomNormal() expected an error for negative stddev, but got nil")
+		}
+	})
+
+	t.Run("negative count", func(t *testing.T) {
+		if _, err := RandomNormal(r, -1, 0, 1); err == nil {
+			t.Error("RandomNormal() expected an error for negative count, but got nil")
+		}
+	})
+
+	t.Run("mean is NaN", func(t *testing.T) {
+		if _, err := RandomNormal(r, 3, math.NaN(), 1); err == nil {
+			t.Error("RandomNormal() expected an error for NaN mean, but got nil")
+		}
+	})
+}
+
+func TestRandomNormalTruncated(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	tests := []struct {
+		name   string
+		mean   float64
+		stddev float64
+		a      float64
+		b      float64
+	}{
+		{"centered", 0, 1, -1, 1},
+		{"inverted interval", 0, 1, 1, -1},
+		{"one-sided", 0, 1, 0, 10},
+		{"far in the tail", 0, 1, 50, 60},
+		{"zero stddev outside interval", 100, 0, 0, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := RandomNormalTruncated(r, 1000, tt.mean, tt.stddev, tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("RandomNormalTruncated() returned an unexpected error: %v", err)
+			}
+			lo, hi := math.Min(tt.a, tt.b), math.Max(tt.a, tt.b)
+			for _, v := range results {
+				if v < lo || v > hi || math.IsNaN(v) {
+					t.Fatalf("RandomNormalTruncated() value %v is outside [%v, %v]", v, lo, hi)
+				}
+			}
+		})
+	}
+
+	if _, err := RandomNormalTruncated(r, 3, 0, 1, math.NaN(), 1); err == nil {
+		t.Error("RandomNormalTruncated() expected an error for NaN bound, but got nil")
+	}
+}
+
+func TestParseDist(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"uniform", false},
+		{"exponential", false},
+		{"exp:2", false},
+		{"lognormal:0.25", false},
+		{"triangular:0.2", false},
+		{"BETA:2,5", false},
+		{"uniform:1", true},
+		{"exponential:0", true},
+		{"lognormal:-1", true},
+		{"triangular:2", true},
+		{"beta:2,0", true},
+		{"beta:1,2,3", true},
+		{"beta:x", true},
+		{"cauchy", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseDist(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDist(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRandomDist(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	tests := []struct {
+		spec     string
+		wantMean float64 // Expected mean of 't', before scaling.
+	}{
+		{"uniform", 0.5},
+		{"triangular:0", 1.0 / 3},
+		{"beta:2,6", 0.25},
+		{"exponential:5", 1/5.0 - math.Exp(-5)/(1-math.Exp(-5))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			dist, err := ParseDist(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseDist() returned an unexpected error: %v", err)
+			}
+			results, err := RandomDist(r, 20000, 10, 20, dist)
+			if err != nil {
+				t.Fatalf("RandomDist() returned an unexpected error: %v", err)
+			}
+			for _, v := range results {
+				if v < 10 || v > 20 {
+					t.Fatalf("RandomDist() value %v is outside [10, 20]", v)
+				}
+			}
+			if m := sampleMean(results); math.Abs(m-(10+10*tt.wantMean)) > 0.1 {
+				t.Errorf("RandomDist() mean = %v, want close to %v", m, 10+10*tt.wantMean)
+			}
+		})
+	}
+
+	t.Run("lognormal stays in range", func(t *testing.T) {
+		dist, _ := ParseDist("lognormal:1")
+		results, _ := RandomDist(r, 1000, 0, 1, dist)
+		for _, v := range results {
+			if v < 0 || v > 1 {
+				t.Fatalf("RandomDist() value %v is outside [0, 1]", v)
+			}
+		}
+	})
+
+	t.Run("negative count", func(t *testing.T) {
+		dist, _ := ParseDist("uniform")
+		if _, err := RandomDist(r, -1, 0, 1, dist); err == nil {
+			t.Error("RandomDist() expected an error for negative count, but got nil")
+		}
+	})
+
+	t.Run("nil distribution", func(t *testing.T) {
+		if _, err := RandomDist(r, 1, 0, 1, nil); err == nil {
+			t.Error("RandomDist() expected an error for a nil distribution, but got nil")
+		}
+	})
+}
+
+func TestRandomInt(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	tests := []struct {
+		name    string
+		a       int64
+		b       int64
+		wantErr bool
+	}{
+		{"small positive range", 1, 6, false},
+		{"negative range", -3, -1, false},
+		{"crossing zero", -2, 2, false},
+		{"inverted interval", 6, 1, false},
+		{"single value", 5, 5, false},
+		{"too large", math.MinInt64, math.MaxInt64, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := RandomInt(r, 2000, tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RandomInt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			lo, hi := min(tt.a, tt.b), max(tt.a, tt.b)
+			seen := map[int64]bool{}
+			for _, v := range results {
+				if v < lo || v > hi {
+					t.Fatalf("RandomInt() value %v is outside [%v, %v]", v, lo, hi)
+				}
+				seen[v] = true
+			}
+			// Both bounds are inclusive, so every value must eventually show up.
+			if int64(len(seen)) != hi-lo+1 {
+				t.Errorf("RandomInt() produced %d distinct values, want %d", len(seen), hi-lo+1)
+			}
+		})
+	}
+
+	if _, err := RandomInt(r, -1, 0, 1); err == nil {
+		t.Error("RandomInt() expected an error for negative count, but got nil")
+	}
+}
+
+func TestHalton(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		base    int
+		a       float64
+		b       float64
+		want    []float64
+		wantErr bool
+	}{
+		{"base 2", 7, 2, 0, 1, []float64{0.5, 0.25, 0.75, 0.125, 0.625, 0.375, 0.875}, false},
+		{"base 3", 4, 3, 0, 9, []float64{3, 6, 1, 4}, false},
+		{"inverted interval", 3, 2, 10, 0, []float64{5, 7.5, 2.5}, false},
+		{"zero count", 0, 2, 0, 1, []float64{}, false},
+		{"base too small", 3, 1, 0, 1, nil, true},
+		{"negative count", -1, 2, 0, 1, nil, true},
+		{"a is NaN", 3, 2, math.NaN(), 1, nil, true},
+		{"b is Inf", 3, 2, 0, math.Inf(1), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Halton(tt.count, tt.base, tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Halton() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("Halton() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	t.Run("one point per subinterval", func(t *testing.T) {
+		results, err := Jitter(r, 10, 0, 100)
+		if err != nil {
+			t.Fatalf("Jitter() returned an unexpected error: %v", err)
+		}
+		if len(results) != 10 {
+			t.Fatalf("Jitter() len = %v, want 10", len(results))
+		}
+		for i, v := range results {
+			lo, hi := float64(i)*10, float64(i+1)*10
+			if v < lo || v > hi {
+				t.Errorf("Jitter() point %d = %v, want within [%v, %v]", i, v, lo, hi)
+			}
+		}
+	})
+
+	t.Run("inverted interval", func(t *testing.T) {
+		results, _ := Jitter(r, 2, 10, 0)
+		if results[0] < 5 || results[0] > 10 || results[1] < 0 || results[1] > 5 {
+			t.Errorf("Jitter() = %v, want points in [10, 5] then [5, 0]", results)
+		}
+	})
+
+	t.Run("negative steps", func(t *testing.T) {
+		if _, err := Jitter(r, -1, 0, 1); err == nil {
+			t.Error("Jitter() expected an error for negative steps, but got nil")
+		}
+	})
+
+	t.Run("a is NaN", func(t *testing.T) {
+		if _, err := Jitter(r, 2, math.NaN(), 1); err == nil {
+			t.Error("Jitter() expected an error for NaN bound, but got nil")
+		}
+	})
+}
+
+func TestReadWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []WeightedBin
+		wantErr bool
+	}{
+		{"values", "1 2\n5 1", []WeightedBin{{1, 1, 2}, {5, 5, 1}}, false},
+		{"bins", "0 10 3\n10 20 1", []WeightedBin{{0, 10, 3}, {10, 20, 1}}, false},
+		{"comments and blanks", "# value weight\n\n1 2\n", []WeightedBin{{1, 1, 2}}, false},
+		{"empty", "", nil, false},
+		{"wrong field count", "1 2 3 4", nil, true},
+		{"not a number", "1 x", nil, true},
+		{"negative weight", "1 -2", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadWeights(bufio.NewScanner(strings.NewReader(tt.input)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadWeights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ReadWeights() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ReadWeights()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRandomWeighted(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	t.Run("proportional to weights", func(t *testing.T) {
+		bins := []WeightedBin{{1, 1, 3}, {2, 2, 1}, {3, 3, 0}}
+		results, err := RandomWeighted(r, 10000, bins)
+		if err != nil {
+			t.Fatalf("RandomWeighted() returned an unexpected error: %v", err)
+		}
+		counts := map[float64]int{}
+		for _, v := range results {
+			counts[v]++
+		}
+		if counts[3] != 0 {
+			t.Errorf("RandomWeighted() drew a zero-weight value %d times", counts[3])
+		}
+		if ratio := float64(counts[1]) / float64(counts[2]); math.Abs(ratio-3) > 0.3 {
+			t.Errorf("RandomWeighted() ratio = %v, want close to 3", ratio)
+		}
+	})
+
+	t.Run("values within bins", func(t *testing.T) {
+		results, _ := RandomWeighted(r, 100, []WeightedBin{{10, 20, 1}})
+		for _, v := range results {
+			if v < 10 || v > 20 {
+				t.Fatalf("RandomWeighted() value %v is outside [10, 20]", v)
+			}
+		}
+	})
+
+	t.Run("zero total weight", func(t *testing.T) {
+		if _, err := RandomWeighted(r, 1, []WeightedBin{{1, 1, 0}}); err == nil {
+			t.Error("RandomWeighted() expected an error for zero total weight, but got nil")
+		}
+	})
+
+	t.Run("negative count", func(t *testing.T) {
+		if _, err := RandomWeighted(r, -1, []WeightedBin{{1, 1, 1}}); err == nil {
+			t.Error("RandomWeighted() expected an error for negative count, but got nil")
+		}
+	})
+}
@@ -0,0 +1,356 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Scale maps the values of a domain to a range, as the axis of a chart does.
+// Remap, Eval and Deval are the linear scale: Remap maps from a source domain to
+// a target range, Eval inverts a scale onto [0, 1], and Deval maps onto it.
+// The errors of scales leave the operation to the caller, which can name it,
+// as in "cannot remap: NaN values are not supported".
+type Scale interface {
+	// Map returns the value of the range that val, in the domain, maps to.
+	Map(val float64) (float64, error)
+	// Invert returns the value of the domain that maps to val, in the range.
+	Invert(val float64) (float64, error)
+	// Ticks returns about count nice values within the domain, to label it.
+	Ticks(count int) ([]float64, error)
+}
+
+// LinearScale maps its domain to its range in proportion.
+type LinearScale struct {
+	Domain, Range [2]float64
+	// Clamp restricts mapped values to the range, and inverted ones to the domain.
+	Clamp bool
+}
+
+// Map returns the value of the range that val maps to.
+func (s LinearScale) Map(val float64) (float64, error) {
+	return scaleMap(val, s.Domain, s.Range, s.Clamp, identity)
+}
+
+// Invert returns the value of the domain that maps to val.
+func (s LinearScale) Invert(val float64) (float64, error) {
+	return scaleInvert(val, s.Domain, s.Range, s.Clamp, identity, identity)
+}
+
+// Ticks returns the ticks of Ticks that lie within the domain.
+func (s LinearScale) Ticks(count int) ([]float64, error) {
+	return domainTicks(count, s.Domain)
+}
+
+// LogScale maps the logarithm of its domain to its range, so that each power of
+// Base takes the same room. The domain must be positive.
+type LogScale struct {
+	Domain, Range [2]float64
+	// Base is the base of the logarithm; 0 uses 10.
+	Base float64
+	// Clamp restricts mapped values to the range, and inverted ones to the domain.
+	Clamp bool
+}
+
+// base returns the base of the logarithm, checking it.
+func (s LogScale) base() (float64, error) {
+	switch {
+	case s.Base == 0:
+		return 10, nil
+	case !(s.Base > 0) || s.Base == 1 || math.IsInf(s.Base, 0):
+		return 0, fmt.Errorf("invalid log scale base %v (expected a positive number other than 1)", s.Base)
+	}
+	return s.Base, nil
+}
+
+// Map returns the value of the range that val maps to. Values that are not
+// positive are an error.
+func (s LogScale) Map(val float64) (float64, error) {
+	base, err := s.base()
+	if err != nil {
+		return 0, err
+	}
+	if err := checkScale(val, s.Domain, s.Range); err != nil {
+		return 0, err
+	}
+	if !(val > 0) || !(s.Domain[0] > 0) || !(s.Domain[1] > 0) {
+		return 0, fmt.Errorf("%v is not on the log scale over [%v, %v]: values must be positive", val, s.Domain[0], s.Domain[1])
+	}
+	return scaleMap(val, s.Domain, s.Range, s.Clamp, logFunc(base))
+}
+
+// Invert returns the value of the domain that maps to val.
+func (s LogScale) Invert(val float64) (float64, error) {
+	base, err := s.base()
+	if err != nil {
+		return 0, err
+	}
+	if !(s.Domain[0] > 0) || !(s.Domain[1] > 0) {
+		return 0, fmt.Errorf("invalid log scale over [%v, %v]: values must be positive", s.Domain[0], s.Domain[1])
+	}
+	return scaleInvert(val, s.Domain, s.Range, s.Clamp, logFunc(base), func(y float64) float64 {
+		return math.Pow(base, y)
+	})
+}
+
+// Ticks returns the powers of the base within the domain, or the ticks of a
+// linear scale when it holds less than two of them.
+func (s LogScale) Ticks(count int) ([]float64, error) {
+	base, err := s.base()
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be a positive integer")
+	}
+	if !(s.Domain[0] > 0) || !(s.Domain[1] > 0) || math.IsInf(s.Domain[0], 0) || math.IsInf(s.Domain[1], 0) {
+		return nil, fmt.Errorf("cannot create ticks on a log scale over [%v, %v]: values must be positive", s.Domain[0], s.Domain[1])
+	}
+	lo, hi := min(s.Domain[0], s.Domain[1]), max(s.Domain[0], s.Domain[1])
+	log := logFunc(base)
+	first, last := math.Floor(log(lo)), math.Ceil(log(hi))
+	var ticks []float64
+	for e := first; e <= last; e++ {
+		if tick := math.Pow(base, e); tick >= lo && tick <= hi {
+			ticks = append(ticks, tick)
+		}
+	}
+	if len(ticks) < 2 {
+		return domainTicks(count, s.Domain)
+	}
+	if s.Domain[0] > s.Domain[1] {
+		slices.Reverse(ticks)
+	}
+	return ticks, nil
+}
+
+// PowScale maps its domain raised to Exponent to its range: with an exponent of
+// 0.5, a square root scale, areas drawn from the range stay proportional to the
+// domain. Negative values are raised by their magnitude and keep their sign.
+type PowScale struct {
+	Domain, Range [2]float64
+	// Exponent is the power the domain is raised to; 0 uses 1.
+	Exponent float64
+	// Clamp restricts mapped values to the range, and inverted ones to the domain.
+	Clamp bool
+}
+
+// exponent returns the exponent, checking it.
+func (s PowScale) exponent() (float64, error) {
+	switch {
+	case s.Exponent == 0:
+		return 1, nil
+	case math.IsNaN(s.Exponent) || math.IsInf(s.Exponent, 0):
+		return 0, fmt.Errorf("invalid pow scale exponent %v", s.Exponent)
+	}
+	return s.Exponent, nil
+}
+
+// Map returns the value of the range that val maps to.
+func (s PowScale) Map(val float64) (float64, error) {
+	exponent, err := s.exponent()
+	if err != nil {
+		return 0, err
+	}
+	return scaleMap(val, s.Domain, s.Range, s.Clamp, signedPow(exponent))
+}
+
+// Invert returns the value of the domain that maps to val.
+func (s PowScale) Invert(val float64) (float64, error) {
+	exponent, err := s.exponent()
+	if err != nil {
+		return 0, err
+	}
+	return scaleInvert(val, s.Domain, s.Range, s.Clamp, signedPow(exponent), signedPow(1/exponent))
+}
+
+// Ticks returns the ticks of Ticks that lie within the domain.
+func (s PowScale) Ticks(count int) ([]float64, error) {
+	return domainTicks(count, s.Domain)
+}
+
+// SymlogScale maps its domain through a logarithm that is symmetric around zero
+// and linear near it, sign(x) * log(1 + |x|/Constant), so that it spreads values
+// of both signs over several orders of magnitude, zero included.
+type SymlogScale struct {
+	Domain, Range [2]float64
+	// Constant is the width of the linear region around zero; 0 uses 1.
+	Constant float64
+	// Clamp restricts mapped values to the range, and inverted ones to the domain.
+	Clamp bool
+}
+
+// constant returns the constant, checking it.
+func (s SymlogScale) constant() (float64, error) {
+	switch {
+	case s.Constant == 0:
+		return 1, nil
+	case !(s.Constant > 0) || math.IsInf(s.Constant, 0):
+		return 0, fmt.Errorf("invalid symlog scale constant %v (expected a positive number)", s.Constant)
+	}
+	return s.Constant, nil
+}
+
+// Map returns the value of the range that val maps to.
+func (s SymlogScale) Map(val float64) (float64, error) {
+	c, err := s.constant()
+	if err != nil {
+		return 0, err
+	}
+	return scaleMap(val, s.Domain, s.Range, s.Clamp, symlog(c))
+}
+
+// Invert returns the value of the domain that maps to val.
+func (s SymlogScale) Invert(val float64) (float64, error) {
+	c, err := s.constant()
+	if err != nil {
+		return 0, err
+	}
+	return scaleInvert(val, s.Domain, s.Range, s.Clamp, symlog(c), func(y float64) float64 {
+		return math.Copysign(math.Expm1(math.Abs(y))*c, y)
+	})
+}
+
+// Ticks returns the ticks of Ticks that lie within the domain.
+func (s SymlogScale) Ticks(count int) ([]float64, error) {
+	return domainTicks(count, s.Domain)
+}
+
+// ParseScale returns the scale named by spec from domain to rng: linear, log
+// (optionally log:<base>), pow:<exponent>, sqrt, or symlog (optionally
+// symlog:<constant>).
+func ParseScale(spec string, domain, rng [2]float64, clamp bool) (Scale, error) {
+	name, param, hasParam := strings.Cut(strings.ToLower(spec), ":")
+	var value float64
+	if hasParam {
+		var err error
+		if value, err = strconv.ParseFloat(param, 64); err != nil || value == 0 {
+			return nil, fmt.Errorf("invalid %s scale parameter: %q", name, param)
+		}
+	}
+	switch {
+	case (name == "linear" || name == "") && !hasParam:
+		return LinearScale{Domain: domain, Range: rng, Clamp: clamp}, nil
+	case name == "log":
+		s := LogScale{Domain: domain, Range: rng, Base: value, Clamp: clamp}
+		if _, err := s.base(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case name == "pow" && hasParam:
+		s := PowScale{Domain: domain, Range: rng, Exponent: value, Clamp: clamp}
+		if _, err := s.exponent(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case name == "sqrt" && !hasParam:
+		return PowScale{Domain: domain, Range: rng, Exponent: 0.5, Clamp: clamp}, nil
+	case name == "symlog":
+		s := SymlogScale{Domain: domain, Range: rng, Constant: value, Clamp: clamp}
+		if _, err := s.constant(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown scale: %s (expected linear, log[:<base>], pow:<exponent>, sqrt or symlog[:<constant>])", spec)
+}
+
+// scaleMap maps val from domain to rng through a scale that is linear in
+// forward(val).
+func scaleMap(val float64, domain, rng [2]float64, clamp bool, forward func(float64) float64) (float64, error) {
+	if err := checkScale(val, domain, rng); err != nil {
+		return 0, err
+	}
+	t, err := Deval(forward(val), forward(domain[0]), forward(domain[1]))
+	if err != nil {
+		return 0, fmt.Errorf("the domain has a zero delta")
+	}
+	if clamp {
+		t = Limit(t, 0, 1)
+	}
+	return Eval(t, rng[0], rng[1]), nil
+}
+
+// scaleInvert maps val from rng back to domain through a scale that is linear
+// in forward(val). The ends of the range give the ends of the domain exactly.
+func scaleInvert(val float64, domain, rng [2]float64, clamp bool, forward, inverse func(float64) float64) (float64, error) {
+	if err := checkScale(val, domain, rng); err != nil {
+		return 0, err
+	}
+	t, err := Deval(val, rng[0], rng[1])
+	if err != nil {
+		return 0, fmt.Errorf("the range has a zero delta")
+	}
+	if clamp {
+		t = Limit(t, 0, 1)
+	}
+	switch t {
+	case 0:
+		return domain[0], nil
+	case 1:
+		return domain[1], nil
+	}
+	return inverse(Eval(t, forward(domain[0]), forward(domain[1]))), nil
+}
+
+// checkScale rejects NaN and infinite values, and scales with NaN or infinite
+// bounds.
+func checkScale(val float64, domain, rng [2]float64) error {
+	for _, v := range []float64{val, domain[0], domain[1], rng[0], rng[1]} {
+		if math.IsNaN(v) {
+			return fmt.Errorf("NaN values are not supported")
+		}
+		if math.IsInf(v, 0) {
+			return fmt.Errorf("infinite values are not supported")
+		}
+	}
+	return nil
+}
+
+// domainTicks returns the ticks of Ticks over domain that lie within it.
+func domainTicks(count int, domain [2]float64) ([]float64, error) {
+	ticks, err := Ticks(count, domain[0], domain[1])
+	if err != nil {
+		return nil, err
+	}
+	lo, hi := min(domain[0], domain[1]), max(domain[0], domain[1])
+	within := ticks[:0]
+	for _, tick := range ticks {
+		if tick >= lo && tick <= hi {
+			within = append(within, tick)
+		}
+	}
+	return within, nil
+}
+
+func identity(x float64) float64 { return x }
+
+// logFunc returns the logarithm in base. Bases 10 and 2 use the dedicated
+// functions, which are exact on the powers of the base.
+func logFunc(base float64) func(float64) float64 {
+	switch base {
+	case 10:
+		return math.Log10
+	case 2:
+		return math.Log2
+	}
+	lnBase := math.Log(base)
+	return func(x float64) float64 { return math.Log(x) / lnBase }
+}
+
+// signedPow returns the function raising the magnitude of x to exponent,
+// keeping its sign.
+func signedPow(exponent float64) func(float64) float64 {
+	return func(x float64) float64 {
+		return math.Copysign(math.Pow(math.Abs(x), exponent), x)
+	}
+}
+
+// symlog returns the symmetric log function with constant c.
+func symlog(c float64) func(float64) float64 {
+	return func(x float64) float64 {
+		return math.Copysign(math.Log1p(math.Abs(x)/c), x)
+	}
+}
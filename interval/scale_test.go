@@ -0,0 +1,142 @@
+package interval
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestScales(t *testing.T) {
+	tests := []struct {
+		name    string
+		scale   Scale
+		val     float64
+		want    float64
+		wantErr bool
+	}{
+		{"linear", LinearScale{Domain: [2]float64{0, 10}, Range: [2]float64{100, 200}}, 2.5, 125, false},
+		{"linear extrapolates", LinearScale{Domain: [2]float64{0, 10}, Range: [2]float64{100, 200}}, 20, 300, false},
+		{"linear clamped", LinearScale{Domain: [2]float64{0, 10}, Range: [2]float64{100, 200}, Clamp: true}, 20, 200, false},
+		{"linear inverted range", LinearScale{Domain: [2]float64{0, 10}, Range: [2]float64{1, 0}}, 2, 0.8, false},
+		{"linear zero delta, value on it", LinearScale{Domain: [2]float64{5, 5}, Range: [2]float64{0, 1}}, 5, 0, false},
+		{"linear zero delta", LinearScale{Domain: [2]float64{5, 5}, Range: [2]float64{0, 1}}, 6, 0, true},
+		{"linear NaN", LinearScale{Domain: [2]float64{0, 1}, Range: [2]float64{0, 1}}, math.NaN(), 0, true},
+		{"log decades", LogScale{Domain: [2]float64{1, 1000}, Range: [2]float64{0, 3}}, 100, 2, false},
+		{"log base 2", LogScale{Domain: [2]float64{1, 256}, Range: [2]float64{0, 8}, Base: 2}, 16, 4, false},
+		{"log clamped", LogScale{Domain: [2]float64{1, 100}, Range: [2]float64{0, 1}, Clamp: true}, 1000, 1, false},
+		{"log of zero", LogScale{Domain: [2]float64{1, 100}, Range: [2]float64{0, 1}}, 0, 0, true},
+		{"log over zero", LogScale{Domain: [2]float64{0, 100}, Range: [2]float64{0, 1}}, 10, 0, true},
+		{"log base one", LogScale{Domain: [2]float64{1, 100}, Range: [2]float64{0, 1}, Base: 1}, 10, 0, true},
+		{"sqrt", PowScale{Domain: [2]float64{0, 100}, Range: [2]float64{0, 10}, Exponent: 0.5}, 25, 5, false},
+		{"pow keeps the sign", PowScale{Domain: [2]float64{-2, 2}, Range: [2]float64{-4, 4}, Exponent: 2}, -1, -1, false},
+		{"pow defaults to linear", PowScale{Domain: [2]float64{0, 10}, Range: [2]float64{0, 1}}, 5, 0.5, false},
+		{"symlog zero", SymlogScale{Domain: [2]float64{-100, 100}, Range: [2]float64{-1, 1}}, 0, 0, false},
+		{"symlog symmetric", SymlogScale{Domain: [2]float64{-99, 99}, Range: [2]float64{-1, 1}}, -9, -0.5, false},
+		{"symlog constant", SymlogScale{Domain: [2]float64{0, 30}, Range: [2]float64{0, 2}, Constant: 10}, 10, math.Log(2) / math.Log(4) * 2, false},
+		{"symlog negative constant", SymlogScale{Domain: [2]float64{0, 30}, Range: [2]float64{0, 2}, Constant: -1}, 10, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.scale.Map(tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Map(%v) error = %v, wantErr %v", tt.val, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !almostEqual(got, tt.want) {
+				t.Errorf("Map(%v) = %v, want %v", tt.val, got, tt.want)
+			}
+			// Inverting the result gives the value back, unless it was clamped.
+			back, err := tt.scale.Invert(got)
+			if err != nil {
+				t.Fatalf("Invert(%v) returned an unexpected error: %v", got, err)
+			}
+			if clamped, _ := tt.scale.Map(back); !almostEqual(clamped, got) {
+				t.Errorf("Map(Invert(%v)) = %v, want %v", got, clamped, got)
+			}
+		})
+	}
+}
+
+func TestScaleInvertExactEnds(t *testing.T) {
+	scales := []struct {
+		scale  Scale
+		lo, hi float64
+	}{
+		{LinearScale{Domain: [2]float64{0.1, 0.7}, Range: [2]float64{0, 1}}, 0.1, 0.7},
+		{LogScale{Domain: [2]float64{3, 7e5}, Range: [2]float64{0, 1}}, 3, 7e5},
+		{PowScale{Domain: [2]float64{0.3, 9.1}, Range: [2]float64{0, 1}, Exponent: 1.7}, 0.3, 9.1},
+		{SymlogScale{Domain: [2]float64{-3.3, 0.7}, Range: [2]float64{0, 1}}, -3.3, 0.7},
+	}
+	for _, tt := range scales {
+		lo, _ := tt.scale.Invert(0)
+		hi, _ := tt.scale.Invert(1)
+		if lo != tt.lo || hi != tt.hi {
+			t.Errorf("%T.Invert() of the ends of the range = %v, %v, want %v, %v", tt.scale, lo, hi, tt.lo, tt.hi)
+		}
+	}
+}
+
+func TestScaleTicks(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale Scale
+		count int
+		want  []float64
+	}{
+		{"linear", LinearScale{Domain: [2]float64{0.3, 1.7}}, 5, []float64{0.5, 1, 1.5}},
+		{"linear inverted", LinearScale{Domain: [2]float64{10, 0}}, 2, []float64{10, 5, 0}},
+		{"log decades", LogScale{Domain: [2]float64{2, 5000}}, 10, []float64{10, 100, 1000}},
+		{"log inverted", LogScale{Domain: [2]float64{1000, 1}}, 10, []float64{1000, 100, 10, 1}},
+		{"log within a decade", LogScale{Domain: [2]float64{2, 9}}, 4, []float64{2, 4, 6, 8}},
+		{"sqrt", PowScale{Domain: [2]float64{0, 100}, Exponent: 0.5}, 4, []float64{0, 50, 100}},
+		{"symlog", SymlogScale{Domain: [2]float64{-10, 10}}, 4, []float64{-10, -5, 0, 5, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.scale.Ticks(tt.count)
+			if err != nil {
+				t.Fatalf("Ticks() returned an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ticks(%d) = %v, want %v", tt.count, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScale(t *testing.T) {
+	domain, rng := [2]float64{1, 100}, [2]float64{0, 1}
+	tests := []struct {
+		spec    string
+		want    Scale
+		wantErr bool
+	}{
+		{"linear", LinearScale{Domain: domain, Range: rng}, false},
+		{"", LinearScale{Domain: domain, Range: rng}, false},
+		{"log", LogScale{Domain: domain, Range: rng}, false},
+		{"log:2", LogScale{Domain: domain, Range: rng, Base: 2}, false},
+		{"pow:3", PowScale{Domain: domain, Range: rng, Exponent: 3}, false},
+		{"SQRT", PowScale{Domain: domain, Range: rng, Exponent: 0.5}, false},
+		{"symlog:10", SymlogScale{Domain: domain, Range: rng, Constant: 10}, false},
+		{"pow", nil, true},
+		{"linear:2", nil, true},
+		{"log:x", nil, true},
+		{"log:1", nil, true},
+		{"symlog:-1", nil, true},
+		{"exp", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseScale(tt.spec, domain, rng, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScale(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseScale(%q) = %#v, want %#v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
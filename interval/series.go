@@ -0,0 +1,129 @@
+package interval
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// GenerateSeries renders one sparkline per column of the input, stacked one
+// under the other, so related metrics can be compared. Each line of input is
+// a row of whitespace-separated columns; a field that is not a number, or a
+// column missing from a row, is drawn as a gap in its own column only, with
+// config.Gap or a blank, so the columns stay aligned in time. If the first
+// line has no numbers, its fields name the columns and label their sparklines.
+// With shared scaling, all the sparklines are scaled to the range of every
+// column, otherwise each to its own. The min and max of the config, when
+// given, apply to all of them. In strict mode, a field that is not a number
+// is an error instead of a gap.
+func GenerateSeries(scanner *bufio.Scanner, writer io.Writer, config SparkConfig, shared bool) error {
+	if config.Log {
+		var err error
+		if config, err = config.logScale(); err != nil {
+			return err
+		}
+	}
+
+	var names []string
+	var columns [][]float64
+	rows := 0 // The rows of values read so far.
+	first := true
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if first && len(fields) > 0 {
+			first = false
+			if isHeader(fields) {
+				names = fields
+				continue
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		for i, field := range fields {
+			val, err := ParseHuman(field)
+			if err != nil && config.Strict {
+				return fmt.Errorf("%w: %v", ErrUnparsable, err)
+			} else if err != nil {
+				val = math.NaN()
+			} else if resolved, ok, err := config.resolve(val); err != nil {
+				return err
+			} else if ok {
+				val = resolved
+			} else {
+				val = math.NaN()
+			}
+			for len(columns) <= i {
+				columns = append(columns, gaps(rows))
+			}
+			columns[i] = append(columns[i], val)
+		}
+		for i := len(fields); i < len(columns); i++ {
+			columns[i] = append(columns[i], math.NaN())
+		}
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if shared {
+		var r RunningRange
+		for _, column := range columns {
+			for _, val := range column {
+				r.Add(val)
+			}
+		}
+		if !config.HasMin && r.Count > 0 {
+			config.Min, config.HasMin = r.Min, true
+		}
+		if !config.HasMax && r.Count > 0 {
+			config.Max, config.HasMax = r.Max, true
+		}
+	}
+
+	width := 0
+	for _, name := range names {
+		if n := len([]rune(name)); n > width {
+			width = n
+		}
+	}
+	for i, column := range columns {
+		if i > 0 {
+			fmt.Fprintln(writer)
+		}
+		series := config
+		if series.Gap == 0 {
+			series.Gap = ' '
+		}
+		if i < len(names) {
+			series.Label = names[i] + strings.Repeat(" ", width-len([]rune(names[i])))
+		}
+		if err := generateSparklineFromSlice(column, writer, series); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isHeader reports whether fields hold no number, as a line of column names does.
+func isHeader(fields []string) bool {
+	for _, field := range fields {
+		if _, err := ParseHuman(field); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// gaps returns n missing values, the placeholders of a column for the rows
+// before its first field.
+func gaps(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	return values
+}
@@ -0,0 +1,76 @@
+package interval
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSeries(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		shared bool
+		want   string
+	}{
+		{
+			name:  "Independent scaling",
+			input: "1 10\n2 20\n3 30",
+			want:  " ▄█\n ▄█",
+		},
+		{
+			name:   "Shared scaling",
+			input:  "0 4\n1 8",
+			shared: true,
+			want:   "  \n▄█",
+		},
+		{
+			name:  "Header names the series",
+			input: "cpu mem\n1 3\n2 1",
+			want:  "cpu  █\nmem █ ",
+		},
+		{
+			name:  "Non-numeric fields are gaps in their own column",
+			input: "1 x\n2 5\n3 6",
+			want:  " ▄█\n  █",
+		},
+		{
+			name:   "Missing columns are gaps",
+			input:  "1\n2 5 7\n3 6",
+			config: SparkConfig{Gap: '·'},
+			want:   " ▄█\n· █\n· ·",
+		},
+		{
+			name:   "Fixed interval applies to every series",
+			input:  "5 10",
+			config: SparkConfig{Min: 0, Max: 10, HasMin: true, HasMax: true},
+			want:   "▄\n█",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSeries(scanner, &writer, tc.config, tc.shared); err != nil {
+				t.Fatalf("GenerateSeries() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSeries()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSeriesStrict(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("1 2\n3 4\nabc 5\n"))
+	var writer bytes.Buffer
+	err := GenerateSeries(scanner, &writer, SparkConfig{Strict: true}, false)
+	if !errors.Is(err, ErrUnparsable) {
+		t.Errorf("GenerateSeries() error = %v, want ErrUnparsable", err)
+	}
+}
@@ -0,0 +1,205 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The functions below treat a pair as the closed interval [x[0], x[1]]. Pairs
+// with reversed bounds are taken in increasing order.
+
+// ordered returns x with its bounds in increasing order.
+func ordered(x [2]float64) [2]float64 {
+	if x[0] > x[1] {
+		return [2]float64{x[1], x[0]}
+	}
+	return x
+}
+
+// Overlaps reports whether x and y share at least one point, so intervals that
+// only touch at a bound overlap.
+func Overlaps(x, y [2]float64) bool {
+	x, y = ordered(x), ordered(y)
+	return x[0] <= y[1] && y[0] <= x[1]
+}
+
+// Intersect returns the interval common to x and y, and false if they do not
+// overlap.
+func Intersect(x, y [2]float64) ([2]float64, bool) {
+	if !Overlaps(x, y) {
+		return [2]float64{}, false
+	}
+	x, y = ordered(x), ordered(y)
+	return [2]float64{math.Max(x[0], y[0]), math.Min(x[1], y[1])}, true
+}
+
+// Union returns the hull of x and y: the smallest interval holding both. When
+// they do not overlap, it also holds the gap between them.
+func Union(x, y [2]float64) [2]float64 {
+	x, y = ordered(x), ordered(y)
+	return [2]float64{math.Min(x[0], y[0]), math.Max(x[1], y[1])}
+}
+
+// Merge coalesces intervals into the minimal set of non-overlapping ones that
+// cover the same points, sorted by start. Intervals that touch are merged, and
+// intervals with a NaN bound are ignored.
+func Merge(pairs [][2]float64) [][2]float64 {
+	sorted := make([][2]float64, 0, len(pairs))
+	for _, pair := range pairs {
+		if !math.IsNaN(pair[0]) && !math.IsNaN(pair[1]) {
+			sorted = append(sorted, ordered(pair))
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+
+	var merged [][2]float64
+	for _, pair := range sorted {
+		if last := len(merged) - 1; last >= 0 && pair[0] <= merged[last][1] {
+			merged[last][1] = math.Max(merged[last][1], pair[1])
+			continue
+		}
+		merged = append(merged, pair)
+	}
+	return merged
+}
+
+// Gaps returns the intervals between the merged intervals, the holes in their
+// coverage, sorted by start.
+func Gaps(pairs [][2]float64) [][2]float64 {
+	merged := Merge(pairs)
+	var gaps [][2]float64
+	for i := 1; i < len(merged); i++ {
+		gaps = append(gaps, [2]float64{merged[i-1][1], merged[i][0]})
+	}
+	return gaps
+}
+
+// Complement returns the parts of within that no interval covers, sorted by
+// start: the gaps between the intervals, and the parts before the first one and
+// after the last one.
+func Complement(pairs [][2]float64, within [2]float64) [][2]float64 {
+	within = ordered(within)
+	var gaps [][2]float64
+	start := within[0] // Start of the part not covered yet.
+	for _, pair := range Merge(pairs) {
+		if pair[0] > start {
+			gaps = append(gaps, [2]float64{start, math.Min(pair[0], within[1])})
+		}
+		start = math.Max(start, pair[1])
+		if start >= within[1] {
+			return gaps
+		}
+	}
+	return append(gaps, [2]float64{start, within[1]})
+}
+
+// Coverage returns the length of within covered by the intervals, with
+// overlaps counted once, and the fraction of within it makes up.
+func Coverage(pairs [][2]float64, within [2]float64) (length, fraction float64, err error) {
+	within = ordered(within)
+	if within[1]-within[0] <= 0 {
+		return 0, 0, fmt.Errorf("cannot compute the coverage of an interval of zero length")
+	}
+	var sum kahanSum
+	for _, pair := range Merge(pairs) {
+		if covered, ok := Intersect(pair, within); ok {
+			sum.add(covered[1] - covered[0])
+		}
+	}
+	length = sum.value()
+	return length, length / (within[1] - within[0]), nil
+}
+
+// AllenRelation is one of the 13 relations of Allen's interval algebra, which
+// tell exactly how two intervals are placed relative to each other.
+type AllenRelation string
+
+// The relations of x to y returned by Relation. Each one but RelationEquals
+// has an inverse, the relation of y to x.
+const (
+	RelationBefore       AllenRelation = "before"        // x ends before y starts.
+	RelationMeets        AllenRelation = "meets"         // x ends where y starts.
+	RelationOverlaps     AllenRelation = "overlaps"      // x starts first and ends inside y.
+	RelationStarts       AllenRelation = "starts"        // x starts with y and ends first.
+	RelationDuring       AllenRelation = "during"        // x lies strictly inside y.
+	RelationFinishes     AllenRelation = "finishes"      // x ends with y and starts last.
+	RelationEquals       AllenRelation = "equals"        // x and y have the same bounds.
+	RelationFinishedBy   AllenRelation = "finished-by"   // Inverse of RelationFinishes.
+	RelationContains     AllenRelation = "contains"      // Inverse of RelationDuring.
+	RelationStartedBy    AllenRelation = "started-by"    // Inverse of RelationStarts.
+	RelationOverlappedBy AllenRelation = "overlapped-by" // Inverse of RelationOverlaps.
+	RelationMetBy        AllenRelation = "met-by"        // Inverse of RelationMeets.
+	RelationAfter        AllenRelation = "after"         // Inverse of RelationBefore.
+)
+
+// Relation returns the Allen relation of x to y. With intervals of zero
+// length, several relations may hold at once: equals is preferred, then the
+// relations of intervals that share at most a bound (before, meets, met-by,
+// after).
+func Relation(x, y [2]float64) AllenRelation {
+	x, y = ordered(x), ordered(y)
+	switch {
+	case x == y:
+		return RelationEquals
+	case x[1] < y[0]:
+		return RelationBefore
+	case x[1] == y[0]:
+		return RelationMeets
+	case y[1] < x[0]:
+		return RelationAfter
+	case y[1] == x[0]:
+		return RelationMetBy
+	case x[0] == y[0] && x[1] < y[1]:
+		return RelationStarts
+	case x[0] == y[0]:
+		return RelationStartedBy
+	case x[1] == y[1] && x[0] > y[0]:
+		return RelationFinishes
+	case x[1] == y[1]:
+		return RelationFinishedBy
+	case x[0] > y[0] && x[1] < y[1]:
+		return RelationDuring
+	case x[0] < y[0] && x[1] > y[1]:
+		return RelationContains
+	case x[0] < y[0]:
+		return RelationOverlaps
+	default:
+		return RelationOverlappedBy
+	}
+}
+
+// Clip restricts x to within, as Limit does for a value. It returns false when
+// nothing of x is left: when x lies outside of within, or only touches one of
+// its bounds. An interval of zero length inside within is kept.
+func Clip(x, within [2]float64) ([2]float64, bool) {
+	clipped, ok := Intersect(x, within)
+	if !ok || (clipped[0] == clipped[1] && x[0] != x[1]) {
+		return [2]float64{}, false
+	}
+	return clipped, true
+}
+
+// Split cuts x at each of points lying strictly inside it and returns the
+// pieces in increasing order. Points outside x, on its bounds or NaN are
+// ignored, so x is returned whole when none of them cuts it.
+func Split(x [2]float64, points []float64) [][2]float64 {
+	x = ordered(x)
+	cuts := make([]float64, 0, len(points))
+	for _, point := range points {
+		if point > x[0] && point < x[1] {
+			cuts = append(cuts, point)
+		}
+	}
+	sort.Float64s(cuts)
+
+	pieces := make([][2]float64, 0, len(cuts)+1)
+	start := x[0]
+	for _, cut := range cuts {
+		if cut > start { // Skips repeated points.
+			pieces = append(pieces, [2]float64{start, cut})
+			start = cut
+		}
+	}
+	return append(pieces, [2]float64{start, x[1]})
+}
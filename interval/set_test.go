@@ -0,0 +1,328 @@
+package interval
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestIntervalSets(t *testing.T) {
+	testCases := []struct {
+		name         string
+		x, y         [2]float64
+		overlaps     bool
+		intersection [2]float64
+		union        [2]float64
+	}{
+		{
+			name:         "Overlapping",
+			x:            [2]float64{0, 10},
+			y:            [2]float64{5, 15},
+			overlaps:     true,
+			intersection: [2]float64{5, 10},
+			union:        [2]float64{0, 15},
+		},
+		{
+			name:         "Nested",
+			x:            [2]float64{0, 10},
+			y:            [2]float64{2, 3},
+			overlaps:     true,
+			intersection: [2]float64{2, 3},
+			union:        [2]float64{0, 10},
+		},
+		{
+			name:         "Touching",
+			x:            [2]float64{0, 5},
+			y:            [2]float64{5, 10},
+			overlaps:     true,
+			intersection: [2]float64{5, 5},
+			union:        [2]float64{0, 10},
+		},
+		{
+			name:     "Disjoint",
+			x:        [2]float64{0, 1},
+			y:        [2]float64{2, 3},
+			overlaps: false,
+			union:    [2]float64{0, 3},
+		},
+		{
+			name:         "Reversed bounds",
+			x:            [2]float64{10, 0},
+			y:            [2]float64{15, 5},
+			overlaps:     true,
+			intersection: [2]float64{5, 10},
+			union:        [2]float64{0, 15},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Overlaps(tc.x, tc.y); got != tc.overlaps {
+				t.Errorf("Overlaps(%v, %v) = %v, want %v", tc.x, tc.y, got, tc.overlaps)
+			}
+			got, ok := Intersect(tc.x, tc.y)
+			if ok != tc.overlaps || (ok && got != tc.intersection) {
+				t.Errorf("Intersect(%v, %v) = %v, %v, want %v, %v", tc.x, tc.y, got, ok, tc.intersection, tc.overlaps)
+			}
+			if got := Union(tc.x, tc.y); got != tc.union {
+				t.Errorf("Union(%v, %v) = %v, want %v", tc.x, tc.y, got, tc.union)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	testCases := []struct {
+		name  string
+		pairs [][2]float64
+		want  [][2]float64
+	}{
+		{
+			name:  "Empty",
+			pairs: nil,
+			want:  nil,
+		},
+		{
+			name:  "Overlapping and unsorted",
+			pairs: [][2]float64{{8, 10}, {1, 3}, {2, 6}, {15, 18}},
+			want:  [][2]float64{{1, 6}, {8, 10}, {15, 18}},
+		},
+		{
+			name:  "Touching",
+			pairs: [][2]float64{{1, 4}, {4, 5}},
+			want:  [][2]float64{{1, 5}},
+		},
+		{
+			name:  "Nested",
+			pairs: [][2]float64{{0, 10}, {2, 3}, {4, 12}},
+			want:  [][2]float64{{0, 12}},
+		},
+		{
+			name:  "Reversed bounds",
+			pairs: [][2]float64{{6, 2}, {1, 3}},
+			want:  [][2]float64{{1, 6}},
+		},
+		{
+			name:  "NaN is ignored",
+			pairs: [][2]float64{{math.NaN(), 1}, {2, 3}},
+			want:  [][2]float64{{2, 3}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Merge(tc.pairs); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Merge(%v) = %v, want %v", tc.pairs, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGaps(t *testing.T) {
+	pairs := [][2]float64{{8, 10}, {1, 3}, {2, 6}, {15, 18}}
+	want := [][2]float64{{6, 8}, {10, 15}}
+	if got := Gaps(pairs); !reflect.DeepEqual(got, want) {
+		t.Errorf("Gaps(%v) = %v, want %v", pairs, got, want)
+	}
+	if got := Gaps([][2]float64{{1, 4}, {4, 5}}); got != nil {
+		t.Errorf("Gaps() of touching intervals = %v, want none", got)
+	}
+}
+
+func TestComplement(t *testing.T) {
+	testCases := []struct {
+		name   string
+		pairs  [][2]float64
+		within [2]float64
+		want   [][2]float64
+	}{
+		{
+			name:   "Gaps and ends",
+			pairs:  [][2]float64{{8, 10}, {2, 6}},
+			within: [2]float64{0, 12},
+			want:   [][2]float64{{0, 2}, {6, 8}, {10, 12}},
+		},
+		{
+			name:   "Covered ends",
+			pairs:  [][2]float64{{-5, 2}, {6, 20}},
+			within: [2]float64{0, 12},
+			want:   [][2]float64{{2, 6}},
+		},
+		{
+			name:   "Fully covered",
+			pairs:  [][2]float64{{0, 12}},
+			within: [2]float64{0, 12},
+			want:   nil,
+		},
+		{
+			name:   "Intervals outside",
+			pairs:  [][2]float64{{-10, -5}, {20, 30}},
+			within: [2]float64{0, 12},
+			want:   [][2]float64{{0, 12}},
+		},
+		{
+			name:   "No intervals",
+			pairs:  nil,
+			within: [2]float64{12, 0},
+			want:   [][2]float64{{0, 12}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Complement(tc.pairs, tc.within); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Complement(%v, %v) = %v, want %v", tc.pairs, tc.within, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pairs    [][2]float64
+		within   [2]float64
+		length   float64
+		fraction float64
+	}{
+		{
+			name:     "Overlaps counted once",
+			pairs:    [][2]float64{{0, 4}, {2, 6}, {8, 10}},
+			within:   [2]float64{0, 10},
+			length:   8,
+			fraction: 0.8,
+		},
+		{
+			name:     "Clipped to the interval",
+			pairs:    [][2]float64{{-5, 5}, {15, 20}},
+			within:   [2]float64{0, 20},
+			length:   10,
+			fraction: 0.5,
+		},
+		{
+			name:     "Nothing covered",
+			pairs:    nil,
+			within:   [2]float64{0, 10},
+			length:   0,
+			fraction: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			length, fraction, err := Coverage(tc.pairs, tc.within)
+			if err != nil {
+				t.Fatalf("Coverage() returned an unexpected error: %v", err)
+			}
+			if length != tc.length || fraction != tc.fraction {
+				t.Errorf("Coverage(%v, %v) = %v, %v, want %v, %v", tc.pairs, tc.within, length, fraction, tc.length, tc.fraction)
+			}
+		})
+	}
+
+	if _, _, err := Coverage(nil, [2]float64{1, 1}); err == nil {
+		t.Error("Coverage() of an interval of zero length expected an error")
+	}
+}
+
+func TestRelation(t *testing.T) {
+	y := [2]float64{10, 20}
+	testCases := []struct {
+		x    [2]float64
+		want AllenRelation
+	}{
+		{[2]float64{0, 5}, RelationBefore},
+		{[2]float64{0, 10}, RelationMeets},
+		{[2]float64{5, 15}, RelationOverlaps},
+		{[2]float64{10, 15}, RelationStarts},
+		{[2]float64{12, 18}, RelationDuring},
+		{[2]float64{15, 20}, RelationFinishes},
+		{[2]float64{10, 20}, RelationEquals},
+		{[2]float64{5, 20}, RelationFinishedBy},
+		{[2]float64{5, 25}, RelationContains},
+		{[2]float64{10, 25}, RelationStartedBy},
+		{[2]float64{15, 25}, RelationOverlappedBy},
+		{[2]float64{20, 25}, RelationMetBy},
+		{[2]float64{25, 30}, RelationAfter},
+		{[2]float64{15, 5}, RelationOverlaps},
+	}
+	for _, tc := range testCases {
+		if got := Relation(tc.x, y); got != tc.want {
+			t.Errorf("Relation(%v, %v) = %v, want %v", tc.x, y, got, tc.want)
+		}
+	}
+}
+
+func TestClip(t *testing.T) {
+	within := [2]float64{0, 10}
+	testCases := []struct {
+		name string
+		x    [2]float64
+		want [2]float64
+		ok   bool
+	}{
+		{"Inside", [2]float64{2, 3}, [2]float64{2, 3}, true},
+		{"Across a bound", [2]float64{-5, 5}, [2]float64{0, 5}, true},
+		{"Around", [2]float64{-5, 15}, [2]float64{0, 10}, true},
+		{"Outside", [2]float64{12, 15}, [2]float64{}, false},
+		{"Touching", [2]float64{10, 15}, [2]float64{}, false},
+		{"Point inside", [2]float64{4, 4}, [2]float64{4, 4}, true},
+		{"Point on a bound", [2]float64{10, 10}, [2]float64{10, 10}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := Clip(tc.x, within)
+			if ok != tc.ok || got != tc.want {
+				t.Errorf("Clip(%v, %v) = %v, %v, want %v, %v", tc.x, within, got, ok, tc.want, tc.ok)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		name   string
+		x      [2]float64
+		points []float64
+		want   [][2]float64
+	}{
+		{
+			name:   "Unsorted points",
+			x:      [2]float64{0, 24},
+			points: []float64{16, 8},
+			want:   [][2]float64{{0, 8}, {8, 16}, {16, 24}},
+		},
+		{
+			name:   "Points outside or on a bound",
+			x:      [2]float64{0, 10},
+			points: []float64{-5, 0, 10, 15},
+			want:   [][2]float64{{0, 10}},
+		},
+		{
+			name:   "Repeated points",
+			x:      [2]float64{0, 10},
+			points: []float64{5, 5},
+			want:   [][2]float64{{0, 5}, {5, 10}},
+		},
+		{
+			name:   "Reversed bounds",
+			x:      [2]float64{10, 0},
+			points: []float64{4},
+			want:   [][2]float64{{0, 4}, {4, 10}},
+		},
+		{
+			name:   "NaN is ignored",
+			x:      [2]float64{0, 10},
+			points: []float64{math.NaN()},
+			want:   [][2]float64{{0, 10}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Split(tc.x, tc.points); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Split(%v, %v) = %v, want %v", tc.x, tc.points, got, tc.want)
+			}
+		})
+	}
+}
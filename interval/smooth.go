@@ -0,0 +1,120 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+)
+
+// MovingAverage computes a simple moving average over a sliding window of a stream.
+type MovingAverage struct {
+	buffer *circularBuffer
+	sum    kahanSum
+}
+
+// NewMovingAverage returns a moving average over the last window values.
+func NewMovingAverage(window int) (*MovingAverage, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be a positive integer")
+	}
+	return &MovingAverage{buffer: newCircularBuffer(window)}, nil
+}
+
+// Add records a value and returns the average of the values in the window.
+// Until the window fills up, the average covers the values seen so far.
+func (m *MovingAverage) Add(val float64) (float64, error) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, fmt.Errorf("cannot smooth: NaN and infinite values are not supported")
+	}
+	if m.buffer.full {
+		// The oldest value is about to be overwritten.
+		m.sum.add(-m.buffer.data[m.buffer.head])
+	}
+	m.buffer.Add(val)
+	m.sum.add(val)
+	return m.sum.value() / float64(len(m.buffer.GetAll())), nil
+}
+
+// EMA computes an exponential moving average of a stream. It only keeps the
+// current average, so it can run on unbounded streams.
+type EMA struct {
+	alpha   float64
+	value   float64
+	started bool
+}
+
+// NewEMA returns an exponential moving average with smoothing factor alpha in (0, 1].
+// Higher values of alpha discount older values faster.
+func NewEMA(alpha float64) (*EMA, error) {
+	if math.IsNaN(alpha) || alpha <= 0 || alpha > 1 {
+		return nil, fmt.Errorf("alpha must be in the range (0, 1]")
+	}
+	return &EMA{alpha: alpha}, nil
+}
+
+// Add records a value and returns the updated average. The first value
+// initializes the average.
+func (e *EMA) Add(val float64) (float64, error) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, fmt.Errorf("cannot smooth: NaN and infinite values are not supported")
+	}
+	if !e.started {
+		e.value = val
+		e.started = true
+		return e.value, nil
+	}
+	e.value = Eval(e.alpha, e.value, val)
+	return e.value, nil
+}
+
+// RollingNormalizer remaps each value of a stream from the min/max of the last
+// window values (including itself) to a target interval, so slowly drifting
+// signals keep using the whole target range.
+type RollingNormalizer struct {
+	buffer     *circularBuffer
+	dstA, dstB float64
+}
+
+// NewRollingNormalizer returns a normalizer over the last window values that
+// remaps into the interval [dstA, dstB].
+func NewRollingNormalizer(window int, dstA, dstB float64) (*RollingNormalizer, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be a positive integer")
+	}
+	if math.IsNaN(dstA) || math.IsNaN(dstB) || math.IsInf(dstA, 0) || math.IsInf(dstB, 0) {
+		return nil, fmt.Errorf("target interval must be finite")
+	}
+	return &RollingNormalizer{buffer: newCircularBuffer(window), dstA: dstA, dstB: dstB}, nil
+}
+
+// Add records a value and returns it remapped from the window's range. While all
+// values in the window are equal, the result is dstA.
+func (n *RollingNormalizer) Add(val float64) (float64, error) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, fmt.Errorf("cannot normalize: NaN and infinite values are not supported")
+	}
+	n.buffer.Add(val)
+	min, max := n.buffer.MinMax()
+	if min == max {
+		return n.dstA, nil
+	}
+	return Remap(val, min, max, n.dstA, n.dstB)
+}
+
+// Differ computes the differences between consecutive values of a stream.
+type Differ struct {
+	prev    float64
+	started bool
+}
+
+// Add records a value and returns its difference with the previous value.
+// For the first value there is no previous value, and ok is false.
+func (d *Differ) Add(val float64) (diff float64, ok bool) {
+	if !d.started {
+		d.prev = val
+		d.started = true
+		return 0, false
+	}
+	diff = val - d.prev
+	d.prev = val
+	return diff, true
+}
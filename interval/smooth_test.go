@@ -0,0 +1,165 @@
+package interval
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		name   string
+		window int
+		input  []float64
+		want   []float64
+	}{
+		{"window of one is identity", 1, []float64{1, 5, 3}, []float64{1, 5, 3}},
+		{"warm-up then sliding", 3, []float64{3, 6, 9, 12, 0}, []float64{3, 4.5, 6, 9, 7}},
+		{"window larger than stream", 10, []float64{2, 4}, []float64{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMovingAverage(tt.window)
+			if err != nil {
+				t.Fatalf("NewMovingAverage() returned an unexpected error: %v", err)
+			}
+			got := make([]float64, len(tt.input))
+			for i, v := range tt.input {
+				got[i], _ = m.Add(v)
+			}
+			if !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("MovingAverage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no drift over long streams", func(t *testing.T) {
+		m, _ := NewMovingAverage(2)
+		m.Add(1e16)
+		var got float64
+		for i := 0; i < 1000000; i++ {
+			got, _ = m.Add(0.1)
+		}
+		if got != 0.1 {
+			t.Errorf("MovingAverage after a large value = %v, want 0.1", got)
+		}
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		if _, err := NewMovingAverage(0); err == nil {
+			t.Error("NewMovingAverage() expected an error for a zero window, but got nil")
+		}
+	})
+
+	t.Run("NaN is rejected and not recorded", func(t *testing.T) {
+		m, _ := NewMovingAverage(2)
+		m.Add(4)
+		if _, err := m.Add(math.NaN()); err == nil {
+			t.Error("Add() expected an error for NaN, but got nil")
+		}
+		if got, _ := m.Add(6); got != 5 {
+			t.Errorf("Add() = %v, want 5", got)
+		}
+	})
+}
+
+func TestEMA(t *testing.T) {
+	tests := []struct {
+		name  string
+		alpha float64
+		input []float64
+		want  []float64
+	}{
+		{"alpha of one is identity", 1, []float64{1, 5, 3}, []float64{1, 5, 3}},
+		{"half", 0.5, []float64{4, 8, 0, 4}, []float64{4, 6, 3, 3.5}},
+		{"slow", 0.1, []float64{10, 20}, []float64{10, 11}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEMA(tt.alpha)
+			if err != nil {
+				t.Fatalf("NewEMA() returned an unexpected error: %v", err)
+			}
+			got := make([]float64, len(tt.input))
+			for i, v := range tt.input {
+				got[i], _ = e.Add(v)
+			}
+			if !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("EMA = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, alpha := range []float64{0, -0.5, 1.5, math.NaN()} {
+		if _, err := NewEMA(alpha); err == nil {
+			t.Errorf("NewEMA(%v) expected an error, but got nil", alpha)
+		}
+	}
+
+	t.Run("Inf is rejected", func(t *testing.T) {
+		e, _ := NewEMA(0.5)
+		if _, err := e.Add(math.Inf(1)); err == nil {
+			t.Error("Add() expected an error for Inf, but got nil")
+		}
+	})
+}
+
+func TestRollingNormalizer(t *testing.T) {
+	tests := []struct {
+		name   string
+		window int
+		a, b   float64
+		input  []float64
+		want   []float64
+	}{
+		{"unit interval", 3, 0, 1, []float64{5, 10, 0, 5, 20}, []float64{0, 1, 0, 0.5, 1}},
+		{"drifting signal", 2, 0, 100, []float64{100, 101, 102, 101}, []float64{0, 100, 100, 0}},
+		{"inverted target", 2, 1, 0, []float64{0, 10}, []float64{1, 0}},
+		{"constant stream", 3, 0, 1, []float64{7, 7, 7}, []float64{0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := NewRollingNormalizer(tt.window, tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("NewRollingNormalizer() returned an unexpected error: %v", err)
+			}
+			got := make([]float64, len(tt.input))
+			for i, v := range tt.input {
+				got[i], _ = n.Add(v)
+			}
+			if !slicesAlmostEqual(got, tt.want) {
+				t.Errorf("RollingNormalizer = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewRollingNormalizer(0, 0, 1); err == nil {
+		t.Error("NewRollingNormalizer() expected an error for a zero window, but got nil")
+	}
+	if _, err := NewRollingNormalizer(3, 0, math.Inf(1)); err == nil {
+		t.Error("NewRollingNormalizer() expected an error for an infinite target, but got nil")
+	}
+}
+
+func TestDiffer(t *testing.T) {
+	var d Differ
+	input := []float64{5, 7, 4, 4, 10}
+	want := []float64{2, -3, 0, 6}
+
+	if _, ok := d.Add(input[0]); ok {
+		t.Error("Add() ok = true for the first value, want false")
+	}
+	var got []float64
+	for _, v := range input[1:] {
+		diff, ok := d.Add(v)
+		if !ok {
+			t.Fatalf("Add(%v) ok = false, want true", v)
+		}
+		got = append(got, diff)
+	}
+	if !slicesAlmostEqual(got, want) {
+		t.Errorf("Differ = %v, want %v", got, want)
+	}
+}
@@ -2,15 +2,29 @@ package interval
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// sparkCharacters are the default characters used to render the sparkline.
+var sparkCharacters = []rune{' ', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
 // SparkCharacters are the default characters used to render the sparkline.
-var SparkCharacters = []rune{' ', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+//
+// Deprecated: Set SparkConfig.Chars to draw with other characters.
+var SparkCharacters = sparkCharacters
+
+// asciiCharacters render the sparkline in plain ASCII, for terminals and logs
+// where block characters render badly.
+var asciiCharacters = []rune{'_', '.', '-', '=', '#'}
+
+// DefaultRefresh is the minimum time between two frames of a sliding window.
+const DefaultRefresh = 250 * time.Millisecond
 
 // SparkColor represents an ANSI color code for sparklines.
 type SparkColor string
@@ -27,13 +41,144 @@ const (
 	ColorReset   SparkColor = "\033[0m"
 )
 
+// SparkStyle selects the characters a sparkline is drawn with.
+type SparkStyle string
+
+const (
+	// StyleBlock draws one value per character with block characters (8 levels).
+	StyleBlock SparkStyle = "block"
+	// StyleBraille draws two values per character with braille patterns
+	// (5 levels each, from an empty cell to a full column of 4 dots).
+	StyleBraille SparkStyle = "braille"
+	// StyleWinLoss draws the sign of each value only: an upper tick for a
+	// positive value, a lower tick for a negative one and a blank for zero.
+	// It is always drawn on a single row.
+	StyleWinLoss SparkStyle = "winloss"
+)
+
+// ParseStyle translates a string name into a SparkStyle.
+func ParseStyle(s string) (SparkStyle, error) {
+	switch strings.ToLower(s) {
+	case "", "block":
+		return StyleBlock, nil
+	case "braille":
+		return StyleBraille, nil
+	case "winloss":
+		return StyleWinLoss, nil
+	default:
+		return StyleBlock, fmt.Errorf("unknown sparkline style: %s", s)
+	}
+}
+
+// valuesPerCell returns how many values one character of the style shows.
+func (s SparkStyle) valuesPerCell() int {
+	if s == StyleBraille {
+		return 2
+	}
+	return 1
+}
+
 // SparkConfig holds the configuration for generating a sparkline.
 type SparkConfig struct {
 	Min, Max float64
 	HasMin   bool
 	HasMax   bool
-	Width    int
+	Width    int // Width of the sliding window, in characters.
 	Color    SparkColor
+	Style    SparkStyle // Empty means StyleBlock.
+	Height   int        // Rows to draw across; 0 or 1 draws a single row.
+	// Gradient, when set, colors each character by its own value instead of
+	// coloring the whole sparkline with Color.
+	Gradient *Gradient
+	// ASCII draws with plain ASCII characters, and writes each frame of the
+	// sliding window on a line of its own instead of redrawing it in place.
+	// It cannot be combined with StyleBraille.
+	ASCII bool
+	// Chars is the ramp of characters to draw with, from the lowest level to
+	// the highest, with any number of levels. Empty means the default ramp.
+	// It is ignored with StyleBraille and StyleWinLoss.
+	Chars []rune
+	// Labels, when set, formats the min, max and last value printed around
+	// the sparkline, as in "3.2 ▁▂▃▅▇ 9.8 (last 7.1)".
+	Labels func(float64) string
+	// Label is printed before the sparkline, to tell apart the sparklines of a
+	// dashboard. Over several rows, the other rows are indented to line up.
+	Label string
+	// Log scales values through log10 before drawing them, for values spanning
+	// several orders of magnitude. Values that are not positive are skipped.
+	Log bool
+	// Baseline, when HasBaseline is set, draws values above it as bars rising
+	// from a middle line and values below it as bars hanging under it, over
+	// twice as many rows. The lower half is drawn in reverse video. It cannot
+	// be combined with ASCII, Chars, StyleBraille or StyleWinLoss.
+	Baseline    float64
+	HasBaseline bool
+	// Follow reads the input concurrently with the drawing of the sliding
+	// window, so it is redrawn every Refresh even while the input stalls.
+	Follow bool
+	// Refresh, when positive, is the minimum time between two frames of the
+	// sliding window. Zero draws a frame per value, or one per DefaultRefresh
+	// in follow mode.
+	Refresh time.Duration
+	// Fit, when positive, downsamples a sparkline drawn once the input ends so
+	// it fits in that many columns, such as the width of the terminal. With a
+	// sliding window, the oldest values that do not fit next to the
+	// annotations are left out of the frame instead.
+	Fit int
+	// Gap, when set, is drawn for each blank line and NaN value of the input,
+	// so missing data keeps the sparkline aligned in time. Zero skips them.
+	Gap rune
+	// NaN is how NaN and infinite values are drawn. NaNDefault leaves NaN to
+	// Gap. NaNPropagate draws them as gaps with Gap, and NaNClamp draws
+	// infinities at Min and Max when both are set and drops the others.
+	// NaNError makes the functions reading a stream fail with an error
+	// wrapping ErrNonFinite; Sparkline.Add skips the value.
+	NaN NaNPolicy
+	// Strict makes the functions reading a stream fail on a field that is not
+	// a number, with an error wrapping ErrUnparsable, instead of skipping it.
+	Strict bool
+}
+
+// ErrUnparsable is returned for a field that is not a number, in strict mode.
+var ErrUnparsable = errors.New("unparsable value")
+
+// rowCount returns the number of rows the sparkline is drawn across.
+func (c SparkConfig) rowCount() int {
+	rows := 1
+	if c.Height > 1 && c.Style != StyleWinLoss {
+		rows = c.Height
+	}
+	if c.HasBaseline {
+		rows *= 2
+	}
+	return rows
+}
+
+// characters returns the ramp of characters of a single-row sparkline, from
+// the lowest level to the highest.
+func (c SparkConfig) characters() []rune {
+	switch {
+	case len(c.Chars) > 0:
+		return c.Chars
+	case c.ASCII:
+		return asciiCharacters
+	default:
+		return sparkCharacters
+	}
+}
+
+// stackCharacters returns the ramp of characters stacked across several rows,
+// from an empty cell to a full one. A custom ramp gets a blank first level
+// when it has none, so the rows above a bar stay empty.
+func (c SparkConfig) stackCharacters() []rune {
+	if len(c.Chars) == 0 && !c.ASCII {
+		return stackCharacters
+	}
+	chars := c.characters()
+	if chars[0] == ' ' {
+		return chars
+	}
+	return append([]rune{' '}, chars...)
 }
 
 // ParseColor translates a string name into a SparkColor.
@@ -60,17 +205,53 @@ func ParseColor(s string) (SparkColor, error) {
 
 // GenerateSparkline is a dispatcher that chooses the correct sparkline generation method.
 func GenerateSparkline(scanner *bufio.Scanner, writer io.Writer, config SparkConfig) error {
-	// Use streaming for fixed-width or fixed-interval modes.
-	if config.Width > 0 || config.HasMin {
+	if config.Log {
+		var err error
+		if config, err = config.logScale(); err != nil {
+			return err
+		}
+	}
+
+	// Use streaming for fixed-width or fixed-interval modes. A growing
+	// sparkline of several rows cannot be streamed, as rows are written in turn,
+	// nor a labeled one, as its max and last value follow it.
+	if config.Width > 0 || (config.HasMin && config.rowCount() == 1 && config.Labels == nil) {
 		return generateSparklineStream(scanner, writer, config)
 	}
 
 	// For auto-scaled, growing sparklines, we must buffer.
-	numbers, err := readAllNumbers(scanner)
-	if err != nil {
+	sparkline := newSparkline(config)
+	if err := scanNumbers(scanner, config, sparkline.add); err != nil {
 		return err
 	}
-	return generateSparklineFromSlice(numbers, writer, config)
+	fmt.Fprint(writer, sparkline.Render())
+	return nil
+}
+
+// logScale returns the config with the fixed interval in log10 scale, and
+// labels that print values back in linear scale.
+func (c SparkConfig) logScale() (SparkConfig, error) {
+	if (c.HasMin && c.Min <= 0) || (c.HasMax && c.Max <= 0) {
+		return c, fmt.Errorf("a log-scale interval must be positive")
+	}
+	c.Min, c.Max = math.Log10(c.Min), math.Log10(c.Max)
+	if format := c.Labels; format != nil {
+		// Rounding to 15 significant digits drops the error of the round trip
+		// through log10, so 5 is labeled 5 rather than 4.999999999999999.
+		c.Labels = func(val float64) string {
+			linear, _ := strconv.ParseFloat(strconv.FormatFloat(math.Pow(10, val), 'g', 15, 64), 64)
+			return format(linear)
+		}
+	}
+	return c, nil
+}
+
+// logValue returns the log10 of val, and false if val is not positive.
+func logValue(val float64) (float64, bool) {
+	if !(val > 0) {
+		return 0, false
+	}
+	return math.Log10(val), true
 }
 
 // generateSparklineFromSlice renders a sparkline from a slice of numbers already in memory.
@@ -101,76 +282,504 @@ func generateSparklineFromSlice(numbers []float64, writer io.Writer, config Spar
 		}
 	}
 
-	var output strings.Builder
-
-	for _, num := range numbers {
-		charIndex := 0.0
-		if max > min {
-			charIndex, _ = Remap(num, min, max, 0, float64(len(SparkCharacters)-1))
-		}
-		clampedIndex := int(Limit(charIndex, 0, float64(len(SparkCharacters)-1)))
-		output.WriteRune(SparkCharacters[clampedIndex])
+	if config.Fit > 0 {
+		numbers = fitWidth(numbers, min, max, config)
 	}
-
-	fmt.Fprint(writer, applyColor(output.String(), config.Color))
+	fmt.Fprint(writer, renderFrame(numbers, min, max, config))
 	return nil
 }
 
 // generateSparklineStream renders a sparkline by processing the input stream number by number.
 func generateSparklineStream(scanner *bufio.Scanner, writer io.Writer, config SparkConfig) error {
-	var buffer *circularBuffer
 	if config.Width > 0 {
-		buffer = newCircularBuffer(config.Width)
+		return generateSlidingWindow(scanner, writer, config)
+	}
+
+	var pending []float64 // Values of the character being drawn.
+	drawn := false        // Whether a character was drawn.
+
+	// drawPending draws the next character of a growing sparkline, after its label.
+	drawPending := func() {
+		cell := config
+		cell.Label = ""
+		if !drawn && config.Label != "" {
+			fmt.Fprint(writer, config.Label+" ")
+		}
+		fmt.Fprint(writer, renderFrame(pending, config.Min, config.Max, cell))
+		pending = pending[:0]
+		drawn = true
 	}
 
+	err := scanNumbers(scanner, config, func(val float64) {
+		pending = append(pending, val)
+		if len(pending) == config.Style.valuesPerCell() {
+			drawPending()
+		}
+	})
+	if len(pending) > 0 {
+		drawPending()
+	}
+	return err
+}
+
+// generateSlidingWindow renders the last values of the stream as a sliding
+// window redrawn in place, as a LiveSparkline does. The window is redrawn for
+// every value, or at most once per config.Refresh when it is set. In follow
+// mode, the input is read concurrently and the window is redrawn on a timer,
+// so the latest values show even when the input stalls.
+func generateSlidingWindow(scanner *bufio.Scanner, writer io.Writer, config SparkConfig) error {
+	live := newLiveSparkline(writer, newSparkline(config))
+	if !config.Follow {
+		err := scanNumbers(scanner, config, func(val float64) {
+			live.add(val)
+			if config.Refresh <= 0 || time.Since(live.last) >= live.refresh {
+				live.draw()
+			}
+		})
+		if flushErr := live.Flush(); err == nil {
+			err = flushErr
+		}
+		return err
+	}
+
+	values := make(chan float64)
+	done := make(chan error, 1)
+	go func() {
+		err := scanNumbers(scanner, config, func(val float64) { values <- val })
+		close(values)
+		done <- err
+	}()
+
+	ticker := time.NewTicker(live.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case val, ok := <-values:
+			if !ok {
+				err := <-done
+				if flushErr := live.Flush(); err == nil {
+					err = flushErr
+				}
+				return err
+			}
+			live.add(val)
+		case <-ticker.C:
+			live.Flush()
+		}
+	}
+}
+
+// scanNumbers calls fn with each number of the stream, scaled as configured.
+// Fields that are not numbers are skipped. Blank lines and NaN values are
+// passed on as NaN when config.Gap is set, and skipped otherwise, unless
+// config.NaN handles non-finite values; under NaNError, the first one ends the
+// scan with its error.
+func scanNumbers(scanner *bufio.Scanner, config SparkConfig, fn func(float64)) error {
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 && config.Gap != 0 {
+			fn(math.NaN())
+		}
 		for _, field := range fields {
-			val, err := strconv.ParseFloat(field, 64)
-			if err != nil {
+			val, err := ParseHuman(field)
+			if err != nil && config.Strict {
+				return fmt.Errorf("%w: %v", ErrUnparsable, err)
+			} else if err != nil {
 				continue // Skip non-numeric fields
 			}
-
-			if config.Width > 0 {
-				buffer.Add(val)
-				min, max := config.Min, config.Max
-				if !config.HasMin { // If no fixed interval, calculate from buffer
-					min, max = buffer.MinMax()
-				}
-				renderSlidingWindow(writer, buffer, min, max, config.Color)
-			} else { // Growing sparkline with fixed interval
-				var charBuilder strings.Builder
-				renderGrowingCharacter(&charBuilder, val, config.Min, config.Max)
-				fmt.Fprint(writer, applyColor(charBuilder.String(), config.Color))
+			val, ok, err := config.resolve(val)
+			if err != nil {
+				return err
+			}
+			if ok {
+				fn(val)
 			}
 		}
 	}
 	return scanner.Err()
 }
 
-func renderSlidingWindow(writer io.Writer, buffer *circularBuffer, min, max float64, color SparkColor) {
+// scale returns val as it is drawn, through log10 with Log, and false if it is
+// not drawn: NaN values without a Gap, values that are not positive with Log,
+// and non-finite values that the NaN policy drops or rejects.
+func (c SparkConfig) scale(val float64) (float64, bool) {
+	val, ok, _ := c.resolve(val)
+	return val, ok
+}
+
+// resolve is scale, with the error of a non-finite value under NaNError.
+func (c SparkConfig) resolve(val float64) (float64, bool, error) {
+	if c.NaN != NaNDefault && (math.IsNaN(val) || math.IsInf(val, 0)) {
+		switch c.NaN {
+		case NaNPropagate:
+			return math.NaN(), c.Gap != 0, nil
+		case NaNClamp:
+			if !c.HasMin || !c.HasMax {
+				return 0, false, nil // There are no bounds to clamp to.
+			}
+		}
+		resolved, err := c.NaN.Resolve(val, c.Min, c.Max)
+		switch {
+		case errors.Is(err, ErrSkipped):
+			return 0, false, nil
+		case err != nil:
+			return 0, false, err
+		case c.NaN == NaNClamp:
+			return resolved, true, nil // The bounds are already in log scale.
+		}
+		val = resolved
+	}
+	val, ok := c.scaleFinite(val)
+	return val, ok, nil
+}
+
+// scaleFinite is scale, for a value that the NaN policy let through.
+func (c SparkConfig) scaleFinite(val float64) (float64, bool) {
+	if math.IsNaN(val) {
+		return val, c.Gap != 0
+	}
+	if c.Log {
+		return logValue(val)
+	}
+	return val, true
+}
+
+// clearLine is the ANSI code that clears the rest of the line.
+const clearLine = "\033[K"
+
+// renderFrame draws numbers scaled from [min, max] as configured, one line
+// per row of the sparkline, without a trailing newline.
+func renderFrame(numbers []float64, min, max float64, config SparkConfig) string {
+	var rows []string
+	switch {
+	case config.HasBaseline:
+		rows = renderSigned(numbers, min, max, config)
+	case config.Height > 1 && config.Style != StyleWinLoss:
+		rows = renderRows(numbers, min, max, config)
+	default:
+		rows = []string{renderCells(numbers, min, max, config)}
+	}
+	for i, row := range rows {
+		if config.Gradient != nil {
+			rows[i] = applyGradient(row, cellValues(numbers, config.Style), min, max, config.Gradient)
+		} else {
+			rows[i] = applyColor(row, config.Color)
+		}
+	}
+	if config.HasBaseline {
+		for i := len(rows) / 2; i < len(rows); i++ {
+			rows[i] = reverseVideo + rows[i] + reverseVideoOff
+		}
+	}
+	annotate(rows, numbers, min, max, config)
+	return strings.Join(rows, "\n")
+}
+
+// annotate adds the labels and the label prefix of the config around rows.
+func annotate(rows []string, numbers []float64, min, max float64, config SparkConfig) {
+	if config.Labels != nil && len(numbers) > 0 {
+		last := numbers[len(numbers)-1]
+		for i := len(numbers) - 1; i >= 0 && math.IsNaN(last); i-- {
+			last = numbers[i]
+		}
+		addLabels(rows, min, max, last, config.Labels)
+	}
+	if config.Label != "" {
+		indent := strings.Repeat(" ", len([]rune(config.Label)))
+		for i := range rows {
+			if i == 0 {
+				rows[i] = config.Label + " " + rows[i]
+			} else {
+				rows[i] = indent + " " + rows[i]
+			}
+		}
+	}
+}
+
+// fitCells returns the number of characters left for the sparkline of numbers
+// once its annotations take their share of config.Fit. It is at least one.
+func fitCells(numbers []float64, min, max float64, config SparkConfig) int {
+	rows := make([]string, config.rowCount())
+	annotate(rows, numbers, min, max, config)
+	cells := config.Fit
+	for _, row := range rows {
+		if free := config.Fit - len([]rune(row)); free < cells {
+			cells = free
+		}
+	}
+	if cells < 1 {
+		cells = 1
+	}
+	return cells
+}
+
+// fitWidth downsamples numbers so the sparkline, with its annotations, is at
+// most config.Fit characters wide. At least one character is kept.
+func fitWidth(numbers []float64, min, max float64, config SparkConfig) []float64 {
+	values := fitCells(numbers, min, max, config) * config.Style.valuesPerCell()
+	if len(numbers) <= values {
+		return numbers
+	}
+	downsample := LTTB
+	for _, val := range numbers {
+		if math.IsNaN(val) { // LTTB cannot weigh gaps, so pick them evenly instead.
+			downsample = DownsampleUniform
+			break
+		}
+	}
+	fitted, err := downsample(numbers, values)
+	if err != nil {
+		return numbers
+	}
+	return fitted
+}
+
+// addLabels prints min before the bottom row, and max and the last value after
+// it. Over several rows, max moves before the top row, and the other rows are
+// indented to line up.
+func addLabels(rows []string, min, max, last float64, format func(float64) string) {
+	minLabel, maxLabel := format(min), format(max)
+	lastLabel := fmt.Sprintf(" (last %s)", format(last))
+	bottom := len(rows) - 1
+	if bottom == 0 {
+		rows[0] = minLabel + " " + rows[0] + " " + maxLabel + lastLabel
+		return
+	}
+
+	width := len(maxLabel)
+	if len(minLabel) > width {
+		width = len(minLabel)
+	}
+	for i := range rows {
+		label := ""
+		switch i {
+		case 0:
+			label = maxLabel
+		case bottom:
+			label = minLabel
+		}
+		rows[i] = fmt.Sprintf("%*s %s", width, label, rows[i])
+	}
+	rows[bottom] += lastLabel
+}
+
+// cellValues returns the value each character of the style stands for. A
+// braille character shows two values and takes the color of the larger one.
+func cellValues(numbers []float64, style SparkStyle) []float64 {
+	if style != StyleBraille {
+		return numbers
+	}
+	values := make([]float64, 0, (len(numbers)+1)/2)
+	for i := 0; i < len(numbers); i += 2 {
+		val := numbers[i]
+		if i+1 < len(numbers) && (math.IsNaN(val) || numbers[i+1] > val) {
+			val = numbers[i+1]
+		}
+		values = append(values, val)
+	}
+	return values
+}
+
+// renderCells draws numbers scaled from [min, max] on a single row.
+func renderCells(numbers []float64, min, max float64, config SparkConfig) string {
 	var output strings.Builder
-	numbers := buffer.GetAll()
+	if config.Style == StyleBraille {
+		for i := 0; i < len(numbers); i += 2 {
+			if isGap(numbers, i) {
+				output.WriteRune(config.Gap)
+				continue
+			}
+			bits := brailleColumn(numbers[i], min, max, brailleLeft)
+			if i+1 < len(numbers) {
+				bits |= brailleColumn(numbers[i+1], min, max, brailleRight)
+			}
+			output.WriteRune(rune(0x2800 | bits))
+		}
+		return output.String()
+	}
+	if config.Style == StyleWinLoss {
+		ticks := winLossCharacters
+		if config.ASCII {
+			ticks = asciiWinLossCharacters
+		}
+		for _, num := range numbers {
+			switch {
+			case math.IsNaN(num):
+				output.WriteRune(config.Gap)
+			case num > 0:
+				output.WriteRune(ticks[0])
+			case num < 0:
+				output.WriteRune(ticks[1])
+			default:
+				output.WriteRune(' ')
+			}
+		}
+		return output.String()
+	}
 
+	chars := config.characters()
 	for _, num := range numbers {
+		if math.IsNaN(num) {
+			output.WriteRune(config.Gap)
+			continue
+		}
 		charIndex := 0.0
 		if max > min {
-			charIndex, _ = Remap(num, min, max, 0, float64(len(SparkCharacters)-1))
+			charIndex, _ = Remap(num, min, max, 0, float64(len(chars)-1))
 		}
-		clampedIndex := int(Limit(charIndex, 0, float64(len(SparkCharacters)-1)))
-		output.WriteRune(SparkCharacters[clampedIndex])
+		clampedIndex := int(Limit(charIndex, 0, float64(len(chars)-1)))
+		output.WriteRune(chars[clampedIndex])
 	}
+	return output.String()
+}
 
-	fmt.Fprintf(writer, "\r%s", applyColor(output.String(), color))
+// The ticks of StyleWinLoss for a positive and a negative value.
+var (
+	winLossCharacters      = []rune{'▀', '▄'}
+	asciiWinLossCharacters = []rune{'\'', '.'}
+)
+
+// isGap reports whether the braille character starting at numbers[i] stands
+// only for missing values.
+func isGap(numbers []float64, i int) bool {
+	return math.IsNaN(numbers[i]) && (i+1 == len(numbers) || math.IsNaN(numbers[i+1]))
 }
 
-func renderGrowingCharacter(builder *strings.Builder, val, min, max float64) {
-	charIndex := 0.0
+// stackCharacters are the eighths used to stack a bar across several rows.
+var stackCharacters = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// renderRows draws numbers scaled from [min, max] as bars stacked over
+// config.Height rows, from the top row down. Each row adds the levels of one
+// character. Gaps are drawn on the bottom row, under blank cells.
+func renderRows(numbers []float64, min, max float64, config SparkConfig) []string {
+	style, height, chars := config.Style, config.Height, config.stackCharacters()
+	perRow := len(chars) - 1
+	if style == StyleBraille {
+		perRow = len(brailleLeft)
+	}
+	levels := make([]int, len(numbers))
+	for i, num := range numbers {
+		level := 0.0
+		if max > min {
+			level, _ = Remap(num, min, max, 0, float64(perRow*height))
+		}
+		levels[i] = int(Limit(level, 0, float64(perRow*height)))
+	}
+	// fill returns how many levels of a bar fall within the given row.
+	fill := func(level, row int) int {
+		return int(Limit(float64(level-row*perRow), 0, float64(perRow)))
+	}
+	// gap returns the character of a gap on the given row.
+	gap := func(row int) rune {
+		if row == 0 {
+			return config.Gap
+		}
+		return ' '
+	}
+
+	rows := make([]string, height)
+	for row := height - 1; row >= 0; row-- {
+		var output strings.Builder
+		if style == StyleBraille {
+			for i := 0; i < len(levels); i += 2 {
+				if isGap(numbers, i) {
+					output.WriteRune(gap(row))
+					continue
+				}
+				bits := brailleDots(brailleLeft, fill(levels[i], row))
+				if i+1 < len(levels) {
+					bits |= brailleDots(brailleRight, fill(levels[i+1], row))
+				}
+				output.WriteRune(rune(0x2800 | bits))
+			}
+		} else {
+			for i, level := range levels {
+				if math.IsNaN(numbers[i]) {
+					output.WriteRune(gap(row))
+					continue
+				}
+				output.WriteRune(chars[fill(level, row)])
+			}
+		}
+		rows[height-1-row] = output.String()
+	}
+	return rows
+}
+
+// ANSI codes that swap, then restore, the foreground and background colors.
+const (
+	reverseVideo    = "\033[7m"
+	reverseVideoOff = "\033[27m"
+)
+
+// renderSigned draws numbers around config.Baseline, scaled so the farthest of
+// min and max from the baseline fills a half. The upper half holds the bars of
+// the values above the baseline, from the top row down. The lower half holds the
+// bars of the values below it, from the baseline down, with the ramp inverted:
+// drawn in reverse video, the empty part of a character shows as the bar, so
+// bars hang from the baseline at full resolution.
+func renderSigned(numbers []float64, min, max float64, config SparkConfig) []string {
+	extent := math.Max(math.Abs(max-config.Baseline), math.Abs(min-config.Baseline))
+	above := make([]float64, len(numbers))
+	below := make([]float64, len(numbers))
+	for i, num := range numbers {
+		if math.IsNaN(num) { // The gap is drawn on the baseline, atop an empty lower half.
+			above[i] = num
+			continue
+		}
+		above[i] = math.Max(num-config.Baseline, 0)
+		below[i] = math.Max(config.Baseline-num, 0)
+	}
+
+	half := config
+	if half.Height < 1 {
+		half.Height = 1
+	}
+	rows := renderRows(above, 0, extent, half)
+	lower := renderRows(below, 0, extent, half)
+	chars := half.stackCharacters()
+	for i := len(lower) - 1; i >= 0; i-- {
+		var output strings.Builder
+		for _, r := range lower[i] {
+			output.WriteRune(chars[len(chars)-1-runeIndex(chars, r)])
+		}
+		rows = append(rows, output.String())
+	}
+	return rows
+}
+
+// runeIndex returns the position of r in chars, or 0 if it is not there.
+func runeIndex(chars []rune, r rune) int {
+	for i, c := range chars {
+		if c == r {
+			return i
+		}
+	}
+	return 0
+}
+
+// The dots of the two columns of a braille cell, from the bottom up.
+var (
+	brailleLeft  = []int{0x40, 0x04, 0x02, 0x01}
+	brailleRight = []int{0x80, 0x20, 0x10, 0x08}
+)
+
+// brailleColumn returns the dots of a column filled up to the level of val.
+func brailleColumn(val, min, max float64, dots []int) int {
+	level := 0.0
 	if max > min {
-		charIndex, _ = Remap(val, min, max, 0, float64(len(SparkCharacters)-1))
+		level, _ = Remap(val, min, max, 0, float64(len(dots)))
+	}
+	return brailleDots(dots, int(Limit(level, 0, float64(len(dots)))))
+}
+
+// brailleDots returns the lowest n dots of a column.
+func brailleDots(dots []int, n int) int {
+	bits := 0
+	for _, dot := range dots[:n] {
+		bits |= dot
 	}
-	clampedIndex := int(Limit(charIndex, 0, float64(len(SparkCharacters)-1)))
-	builder.WriteRune(SparkCharacters[clampedIndex])
+	return bits
 }
 
 func readAllNumbers(scanner *bufio.Scanner) ([]float64, error) {
@@ -178,7 +787,7 @@ func readAllNumbers(scanner *bufio.Scanner) ([]float64, error) {
 	for scanner.Scan() {
 		fields := strings.Fields(scanner.Text())
 		for _, field := range fields {
-			val, err := strconv.ParseFloat(field, 64)
+			val, err := ParseHuman(field)
 			if err != nil {
 				continue
 			}
@@ -188,6 +797,24 @@ func readAllNumbers(scanner *bufio.Scanner) ([]float64, error) {
 	return numbers, scanner.Err()
 }
 
+// applyGradient colors each character of s with the truecolor found along g
+// for the matching value, scaled from [min, max].
+func applyGradient(s string, values []float64, min, max float64, g *Gradient) string {
+	var output strings.Builder
+	for i, r := range []rune(s) {
+		t := 0.5
+		if max != min {
+			t, _ = Deval(values[i], min, max)
+		}
+		c := g.At(t)
+		fmt.Fprintf(&output, "\033[38;2;%d;%d;%dm%c", c.R, c.G, c.B, r)
+	}
+	if output.Len() > 0 {
+		output.WriteString(string(ColorReset))
+	}
+	return output.String()
+}
+
 func applyColor(s string, color SparkColor) string {
 	if color == ColorNone {
 		return s
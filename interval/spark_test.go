@@ -3,8 +3,11 @@ package interval
 import (
 	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGenerateSparkline(t *testing.T) {
@@ -54,13 +57,13 @@ func TestGenerateSparkline(t *testing.T) {
 			name:  "Sliding Window (Width)",
 			input: "10\n20\n30\n40\n50",
 			config: SparkConfig{Width: 3},
-			want:   "\r▄▆█", // Renders based on 30, 40, 50; scales based on 10-50
+			want:   "\r \r █\r ▄█\r ▄█\r ▄█", // Redrawn per value, scaled to the values in the window
 		},
 		{
 			name:  "Sliding Window with Color",
 			input: "10\n20\n30\n40\n50",
 			config: SparkConfig{Width: 3, Color: ColorRed},
-			want:   "\r\033[31m▄▆█\033[0m",
+			want:   "\r\033[31m \033[0m\r\033[31m █\033[0m\r\033[31m ▄█\033[0m\r\033[31m ▄█\033[0m\r\033[31m ▄█\033[0m",
 		},
 		{
 			name:  "Single Value",
@@ -113,3 +116,737 @@ func TestParseColor(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSparklineBraille(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Auto-scaled",
+			input:  "0 1 2 3 4 4 3 2 1 0",
+			config: SparkConfig{Style: StyleBraille},
+			want:   "⢀⣴⣿⣦⡀",
+		},
+		{
+			name:   "Fixed interval with odd count",
+			input:  "0 1 2 3 4",
+			config: SparkConfig{Min: 0, Max: 4, HasMin: true, HasMax: true, Style: StyleBraille},
+			want:   "⢀⣴⡇",
+		},
+		{
+			name:   "Out of range values are clamped",
+			input:  "-10 10",
+			config: SparkConfig{Min: 0, Max: 4, HasMin: true, HasMax: true, Style: StyleBraille},
+			want:   "⢸",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineHeight(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Two rows of blocks",
+			input:  "0 4 8 12 16",
+			config: SparkConfig{Height: 2},
+			want:   "   ▄█\n ▄███",
+		},
+		{
+			name:   "Fixed interval is buffered",
+			input:  "0 8",
+			config: SparkConfig{Min: 0, Max: 16, HasMin: true, HasMax: true, Height: 2},
+			want:   "  \n █",
+		},
+		{
+			name:   "Braille rows",
+			input:  "0 1 2 3 4 5 6 7 8",
+			config: SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true, Height: 2, Style: StyleBraille},
+			want:   "⠀⠀⢀⣴⡇\n⢀⣴⣿⣿⡇",
+		},
+		{
+			name:   "Colored rows",
+			input:  "0 16",
+			config: SparkConfig{Height: 2, Color: ColorRed},
+			want:   "\033[31m █\033[0m\n\033[31m █\033[0m",
+		},
+		{
+			name:   "Sliding window redraws from the top row",
+			input:  "0 16",
+			config: SparkConfig{Min: 0, Max: 16, HasMin: true, HasMax: true, Width: 2, Height: 2},
+			want:   "\r \n \033[1A\r █\n █",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineGradient(t *testing.T) {
+	g, err := ParseColorRange("#000000..#ff0000")
+	if err != nil {
+		t.Fatalf("ParseColorRange() returned an unexpected error: %v", err)
+	}
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Each character has its own color",
+			input:  "0 10",
+			config: SparkConfig{Gradient: g},
+			want:   "\033[38;2;0;0;0m \033[38;2;255;0;0m█\033[0m",
+		},
+		{
+			name:   "Braille takes the larger value of a pair",
+			input:  "0 10 0 0",
+			config: SparkConfig{Gradient: g, Style: StyleBraille},
+			want:   "\033[38;2;255;0;0m⢸\033[38;2;0;0;0m⠀\033[0m",
+		},
+		{
+			name:   "Fixed interval streams colored characters",
+			input:  "5",
+			config: SparkConfig{Min: 0, Max: 10, HasMin: true, HasMax: true, Gradient: g},
+			want:   "\033[38;2;128;0;0m▄\033[0m",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineASCII(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Auto-scaled",
+			input:  "0 1 2 3 4",
+			config: SparkConfig{ASCII: true},
+			want:   "_.-=#",
+		},
+		{
+			name:   "Two rows",
+			input:  "0 5 10",
+			config: SparkConfig{ASCII: true, Height: 2},
+			want:   "  #\n ##",
+		},
+		{
+			name:   "Sliding window writes one line per frame",
+			input:  "0 4 2",
+			config: SparkConfig{Min: 0, Max: 4, HasMin: true, HasMax: true, Width: 2, ASCII: true},
+			want:   "_\n_#\n#-\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineWinLoss(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Signs only",
+			input:  "1 -2 3 0 -1 4",
+			config: SparkConfig{Style: StyleWinLoss},
+			want:   "▀▄▀ ▄▀",
+		},
+		{
+			name:   "Magnitude is ignored",
+			input:  "0.01 1000 -0.01 -1000",
+			config: SparkConfig{Style: StyleWinLoss},
+			want:   "▀▀▄▄",
+		},
+		{
+			name:   "ASCII",
+			input:  "1 -1 0 2",
+			config: SparkConfig{Style: StyleWinLoss, ASCII: true},
+			want:   "'. '",
+		},
+		{
+			name:   "Height is ignored",
+			input:  "1 -1",
+			config: SparkConfig{Style: StyleWinLoss, Height: 3},
+			want:   "▀▄",
+		},
+		{
+			name:   "Fixed interval",
+			input:  "5 -5",
+			config: SparkConfig{Min: -10, Max: 10, HasMin: true, HasMax: true, Style: StyleWinLoss},
+			want:   "▀▄",
+		},
+		{
+			name:   "Sliding window",
+			input:  "1 -1 2 -2",
+			config: SparkConfig{Width: 3, Style: StyleWinLoss, ASCII: true},
+			want:   "'\n'.\n'.'\n.'.\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineGaps(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Skipped without a gap character",
+			input:  "0\n\nnan\n8",
+			config: SparkConfig{},
+			want:   " █",
+		},
+		{
+			name:   "Blank lines and nan",
+			input:  "0\n4\n\n8\nnan\n8",
+			config: SparkConfig{Gap: '·'},
+			want:   " ▄·█·█",
+		},
+		{
+			name:   "Fixed interval",
+			input:  "0 nan 8",
+			config: SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true, Gap: '·'},
+			want:   " ·█",
+		},
+		{
+			name:   "Sliding window",
+			input:  "0\n\n8",
+			config: SparkConfig{Width: 3, ASCII: true, Gap: ' '},
+			want:   "_\n_ \n_ #\n",
+		},
+		{
+			name:   "Two rows",
+			input:  "0 nan 8",
+			config: SparkConfig{Height: 2, Gap: '·'},
+			want:   "  █\n ·█",
+		},
+		{
+			name:   "Braille",
+			input:  "0 nan nan nan 4",
+			config: SparkConfig{Style: StyleBraille, Gap: '·'},
+			want:   "⠀·⡇",
+		},
+		{
+			name:   "Baseline",
+			input:  "2 nan -2",
+			config: SparkConfig{Baseline: 0, HasBaseline: true, Gap: '·'},
+			want:   "█· \n\033[7m██ \033[27m",
+		},
+		{
+			name:   "Last label skips gaps",
+			input:  "0 8 nan",
+			config: SparkConfig{Gap: '·', Labels: func(v float64) string { return fmt.Sprint(v) }},
+			want:   "0  █· 8 (last 8)",
+		},
+		{
+			name:   "Log scale keeps gaps",
+			input:  "1 nan 100",
+			config: SparkConfig{Log: true, Gap: '·'},
+			want:   " ·█",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineNaNPolicy(t *testing.T) {
+	fixed := SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true}
+	withPolicy := func(config SparkConfig, policy NaNPolicy, gap rune) SparkConfig {
+		config.NaN, config.Gap = policy, gap
+		return config
+	}
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{"skip drops gaps", "0 nan 8", withPolicy(SparkConfig{}, NaNSkip, '·'), " █"},
+		{"zero", "4 nan 8", withPolicy(fixed, NaNZero, 0), "▄ █"},
+		{"zero in auto scale", "4 -inf 8", withPolicy(SparkConfig{}, NaNZero, 0), "▄ █"},
+		{"clamp", "4 inf -inf nan", withPolicy(fixed, NaNClamp, 0), "▄█ "},
+		{"clamp without bounds drops", "0 inf 8", withPolicy(SparkConfig{}, NaNClamp, 0), " █"},
+		{"propagate", "0 inf nan 8", withPolicy(SparkConfig{}, NaNPropagate, '·'), " ··█"},
+		{"propagate in log scale", "1 nan 100", withPolicy(SparkConfig{Log: true}, NaNPropagate, '·'), " ·█"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		scanner := bufio.NewScanner(strings.NewReader("0 inf 8"))
+		var writer bytes.Buffer
+		if err := GenerateSparkline(scanner, &writer, withPolicy(SparkConfig{}, NaNError, 0)); !errors.Is(err, ErrNonFinite) {
+			t.Errorf("GenerateSparkline() error = %v, want %v", err, ErrNonFinite)
+		}
+	})
+}
+
+func TestGenerateSparklineChars(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Custom ramp",
+			input:  "0 1 2 3 4 5 6 7 8 9",
+			config: SparkConfig{Chars: []rune(" .:-=+*#%@")},
+			want:   " .:-=+*#%@",
+		},
+		{
+			name:   "Two levels",
+			input:  "0 10 4 6",
+			config: SparkConfig{Chars: []rune("ab")},
+			want:   "abaa",
+		},
+		{
+			name:   "Overrides ASCII",
+			input:  "0 1",
+			config: SparkConfig{Chars: []rune("xy"), ASCII: true},
+			want:   "xy",
+		},
+		{
+			name:   "Stacked rows get a blank level",
+			input:  "0 5 10",
+			config: SparkConfig{Chars: []rune("abc"), Height: 2},
+			want:   "  c\n cc",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineLabels(t *testing.T) {
+	format := func(val float64) string { return fmt.Sprintf("%g", val) }
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Auto-scaled",
+			input:  "3 9 5",
+			config: SparkConfig{Labels: format},
+			want:   "3  █▃ 9 (last 5)",
+		},
+		{
+			name:   "Fixed interval labels the bounds",
+			input:  "5",
+			config: SparkConfig{Min: 0, Max: 10, HasMin: true, HasMax: true, Labels: format},
+			want:   "0 ▄ 10 (last 5)",
+		},
+		{
+			name:   "Labels stay outside of colors",
+			input:  "1 2",
+			config: SparkConfig{Labels: format, Color: ColorRed},
+			want:   "1 \033[31m █\033[0m 2 (last 2)",
+		},
+		{
+			name:   "Several rows",
+			input:  "0 100",
+			config: SparkConfig{Labels: format, Height: 2},
+			want:   "100  █\n  0  █ (last 100)",
+		},
+		{
+			name:   "Empty input",
+			input:  "",
+			config: SparkConfig{Labels: format},
+			want:   "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineLabel(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Auto-scaled",
+			input:  "1 2",
+			config: SparkConfig{Label: "cpu"},
+			want:   "cpu  █",
+		},
+		{
+			name:   "Growing stream is labeled once",
+			input:  "0 5 10",
+			config: SparkConfig{Min: 0, Max: 10, HasMin: true, HasMax: true, Label: "cpu"},
+			want:   "cpu  ▄█",
+		},
+		{
+			name:   "Several rows are indented",
+			input:  "0 16",
+			config: SparkConfig{Height: 2, Label: "io"},
+			want:   "io  █\n    █",
+		},
+		{
+			name:   "Sliding window redraws the label",
+			input:  "0 8",
+			config: SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true, Width: 2, Label: "io"},
+			want:   "\rio  \rio  █",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineLog(t *testing.T) {
+	format := func(val float64) string { return fmt.Sprintf("%g", val) }
+	testCases := []struct {
+		name    string
+		input   string
+		config  SparkConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Orders of magnitude are evenly spaced",
+			input:  "1 10 100 1000 10000 100000 1000000 10000000",
+			config: SparkConfig{Log: true},
+			want:   " ▂▃▄▅▆▇█",
+		},
+		{
+			name:   "Values that are not positive are skipped",
+			input:  "1 0 -5 10",
+			config: SparkConfig{Log: true},
+			want:   " █",
+		},
+		{
+			name:   "Fixed interval streams",
+			input:  "10 1000",
+			config: SparkConfig{Min: 1, Max: 1000, HasMin: true, HasMax: true, Log: true},
+			want:   "▃█",
+		},
+		{
+			name:   "Labels are in linear scale",
+			input:  "1 5 100",
+			config: SparkConfig{Log: true, Labels: format},
+			want:   "1  ▃█ 100 (last 100)",
+		},
+		{
+			name:    "Interval must be positive",
+			input:   "1",
+			config:  SparkConfig{Min: 0, Max: 10, HasMin: true, HasMax: true, Log: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			err := GenerateSparkline(scanner, &writer, tc.config)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("GenerateSparkline() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineBaseline(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Values above and below the baseline",
+			input:  "8 -4 0 -8",
+			config: SparkConfig{HasBaseline: true},
+			want:   "█   \n\033[7m█▄█ \033[27m",
+		},
+		{
+			name:   "Baseline other than zero",
+			input:  "10 14 6",
+			config: SparkConfig{Baseline: 10, HasBaseline: true},
+			want:   " █ \n\033[7m██ \033[27m",
+		},
+		{
+			name:   "Fixed interval sets the scale",
+			input:  "2",
+			config: SparkConfig{Min: -8, Max: 8, HasMin: true, HasMax: true, HasBaseline: true},
+			want:   "▂\n\033[7m█\033[27m",
+		},
+		{
+			name:   "Two rows per half",
+			input:  "16 -4",
+			config: SparkConfig{HasBaseline: true, Height: 2},
+			want:   "█ \n█ \n\033[7m█▄\033[27m\n\033[7m██\033[27m",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineFit(t *testing.T) {
+	format := func(val float64) string { return fmt.Sprintf("%g", val) }
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Short series is left alone",
+			input:  "1 2 3",
+			config: SparkConfig{Fit: 10},
+			want:   " ▄█",
+		},
+		{
+			name:   "Long series keeps first, last and peaks",
+			input:  "0 1 0 8 0 1 0 1 0 2",
+			config: SparkConfig{Fit: 4},
+			want:   " █ ▂",
+		},
+		{
+			name:   "Annotations are left room",
+			input:  "0 1 2 3 4 5 6 7 8 9",
+			config: SparkConfig{Fit: 20, Label: "x", Labels: format},
+			want:   "x 0   ▃▅█ 9 (last 9)",
+		},
+		{
+			name:   "Braille fits two values per column",
+			input:  "0 1 2 3 4 4 3 2 1 0",
+			config: SparkConfig{Fit: 3, Style: StyleBraille},
+			want:   "⢀⣿⡄",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSparklineRefresh(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Every value is drawn when the refresh is short",
+			input:  "0 8 4",
+			config: SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true, Width: 2, Refresh: time.Nanosecond},
+			want:   "\r \r █\r█▄",
+		},
+		{
+			name:   "Follow clears the end of the line",
+			input:  "0 8 4",
+			config: SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true, Width: 2, Follow: true, Refresh: time.Hour},
+			want:   "\r█▄\033[K",
+		},
+		{
+			name:   "Follow clears every row",
+			input:  "0 16",
+			config: SparkConfig{Min: 0, Max: 16, HasMin: true, HasMax: true, Width: 2, Height: 2, Follow: true, Refresh: time.Hour},
+			want:   "\r █\033[K\n █\033[K",
+		},
+		{
+			name:   "Follow with empty input draws nothing",
+			input:  "",
+			config: SparkConfig{Width: 2, Follow: true},
+			want:   "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSparkline(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSparkline() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSparkline()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, input := range []string{"", "block", "BLOCK"} {
+		if got, err := ParseStyle(input); err != nil || got != StyleBlock {
+			t.Errorf("ParseStyle(%q) = %v, %v, want %v", input, got, err, StyleBlock)
+		}
+	}
+	if got, err := ParseStyle("braille"); err != nil || got != StyleBraille {
+		t.Errorf("ParseStyle(\"braille\") = %v, %v, want %v", got, err, StyleBraille)
+	}
+	if got, err := ParseStyle("winloss"); err != nil || got != StyleWinLoss {
+		t.Errorf("ParseStyle(\"winloss\") = %v, %v, want %v", got, err, StyleWinLoss)
+	}
+	if _, err := ParseStyle("dots"); err == nil {
+		t.Error("ParseStyle(\"dots\") expected an error")
+	}
+}
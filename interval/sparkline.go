@@ -0,0 +1,172 @@
+package interval
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Sparkline accumulates values one at a time and renders them as a sparkline,
+// for programs that produce their values themselves rather than reading them
+// from a stream. With config.Width, it keeps only the values of the last
+// config.Width characters, as a sliding window.
+type Sparkline struct {
+	config SparkConfig
+	window *circularBuffer // The last values, with a sliding window.
+	values []float64       // Every value, without one.
+}
+
+// NewSparkline returns an empty sparkline drawn as configured. It fails if the
+// fixed interval of a log-scale config is not positive.
+func NewSparkline(config SparkConfig) (*Sparkline, error) {
+	if config.Log {
+		var err error
+		if config, err = config.logScale(); err != nil {
+			return nil, err
+		}
+	}
+	return newSparkline(config), nil
+}
+
+// newSparkline returns an empty sparkline for a config already in log scale.
+func newSparkline(config SparkConfig) *Sparkline {
+	s := &Sparkline{config: config}
+	if config.Width > 0 {
+		s.window = newCircularBuffer(config.Width * config.Style.valuesPerCell())
+	}
+	return s
+}
+
+// Add adds a value to the sparkline. NaN values are skipped unless the config
+// has a Gap, and so are values that are not positive in log scale.
+func (s *Sparkline) Add(val float64) {
+	if val, ok := s.config.scale(val); ok {
+		s.add(val)
+	}
+}
+
+// add adds a value already scaled as configured.
+func (s *Sparkline) add(val float64) {
+	if s.window != nil {
+		s.window.Add(val)
+	} else {
+		s.values = append(s.values, val)
+	}
+}
+
+// Render draws the values added so far, scaled to the fixed interval of the
+// config or to the min and max of the values it holds: a sliding window is
+// scaled to the values still in the window, and with config.Fit keeps only the
+// newest ones that fit next to its annotations. An empty sparkline renders as
+// an empty string.
+func (s *Sparkline) Render() string {
+	if s.window == nil {
+		var output strings.Builder
+		generateSparklineFromSlice(s.values, &output, s.config)
+		return output.String()
+	}
+	numbers := s.window.GetAll()
+	if len(numbers) == 0 {
+		return ""
+	}
+	min, max := s.window.MinMax()
+	if s.config.HasMin {
+		min = s.config.Min
+	}
+	if s.config.HasMax {
+		max = s.config.Max
+	}
+	if s.config.Fit > 0 {
+		if values := fitCells(numbers, min, max, s.config) * s.config.Style.valuesPerCell(); len(numbers) > values {
+			numbers = numbers[len(numbers)-values:]
+		}
+	}
+	return renderFrame(numbers, min, max, s.config)
+}
+
+// LiveSparkline redraws a sliding-window sparkline in place on a writer, such
+// as a terminal, as values are added. It is redrawn at most once per
+// config.Refresh, so fast streams do not flicker; Flush draws the values added
+// since. With config.ASCII, each frame is written on a line of its own.
+type LiveSparkline struct {
+	sparkline *Sparkline
+	writer    io.Writer
+	refresh   time.Duration
+	drawn     bool      // Whether a frame is on screen.
+	dirty     bool      // Whether values were added since the last frame.
+	last      time.Time // When the last frame was drawn.
+	err       error     // The first error met writing a frame.
+}
+
+// NewLiveSparkline returns a live sparkline drawn on writer, whose window is
+// config.Width characters wide.
+func NewLiveSparkline(writer io.Writer, config SparkConfig) (*LiveSparkline, error) {
+	if config.Width <= 0 {
+		return nil, fmt.Errorf("a live sparkline needs a positive width")
+	}
+	sparkline, err := NewSparkline(config)
+	if err != nil {
+		return nil, err
+	}
+	return newLiveSparkline(writer, sparkline), nil
+}
+
+func newLiveSparkline(writer io.Writer, sparkline *Sparkline) *LiveSparkline {
+	refresh := sparkline.config.Refresh
+	if refresh <= 0 {
+		refresh = DefaultRefresh
+	}
+	return &LiveSparkline{sparkline: sparkline, writer: writer, refresh: refresh, last: time.Now()}
+}
+
+// Add adds a value, as Sparkline.Add does, and redraws the sparkline if the
+// last frame is older than the refresh period.
+func (l *LiveSparkline) Add(val float64) {
+	if val, ok := l.sparkline.config.scale(val); ok {
+		l.add(val)
+	}
+	if time.Since(l.last) >= l.refresh {
+		l.Flush()
+	}
+}
+
+// add adds a value already scaled as configured, without redrawing.
+func (l *LiveSparkline) add(val float64) {
+	l.sparkline.add(val)
+	l.dirty = true
+}
+
+// Flush draws the values added since the last frame, if any, and returns the
+// first error met writing a frame.
+func (l *LiveSparkline) Flush() error {
+	if l.dirty {
+		l.draw()
+	}
+	return l.err
+}
+
+// draw writes a frame over the previous one.
+func (l *LiveSparkline) draw() {
+	config := l.sparkline.config
+	frame := l.sparkline.Render()
+	var err error
+	switch {
+	case config.ASCII:
+		_, err = fmt.Fprintf(l.writer, "%s\n", frame)
+	default:
+		// Move back up to the top row of the previous frame before redrawing it.
+		if rows := config.rowCount(); l.drawn && rows > 1 {
+			fmt.Fprintf(l.writer, "\033[%dA", rows-1)
+		}
+		if config.Follow {
+			// Clear what is left of longer rows of the previous frame.
+			frame = strings.ReplaceAll(frame, "\n", clearLine+"\n") + clearLine
+		}
+		_, err = fmt.Fprintf(l.writer, "\r%s", frame)
+	}
+	if err != nil && l.err == nil {
+		l.err = err
+	}
+	l.drawn, l.dirty, l.last = true, false, time.Now()
+}
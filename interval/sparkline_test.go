@@ -0,0 +1,133 @@
+package interval
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []float64
+		config SparkConfig
+		want   string
+	}{
+		{
+			name:   "Empty",
+			values: nil,
+			config: SparkConfig{},
+			want:   "",
+		},
+		{
+			name:   "Auto-scaled",
+			values: []float64{0, 4, 8},
+			config: SparkConfig{},
+			want:   " ▄█",
+		},
+		{
+			name:   "Fixed interval",
+			values: []float64{0, 4},
+			config: SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true},
+			want:   " ▄",
+		},
+		{
+			name:   "Sliding window is scaled to the values it holds",
+			values: []float64{0, 8, 4, 8},
+			config: SparkConfig{Width: 2},
+			want:   " █",
+		},
+		{
+			name:   "Sliding window rescales once a spike slides out",
+			values: []float64{100, 1, 2, 3},
+			config: SparkConfig{Width: 3},
+			want:   " ▄█",
+		},
+		{
+			name:   "Fitted sliding window leaves room for the labels",
+			values: []float64{0, 2, 4, 6, 8},
+			config: SparkConfig{Width: 5, Fit: 16, Labels: func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }},
+			want:   "0 ▄▆█ 8 (last 8)",
+		},
+		{
+			name:   "Log scale skips values that are not positive",
+			values: []float64{1, -1, 10, 100},
+			config: SparkConfig{Log: true},
+			want:   " ▄█",
+		},
+		{
+			name:   "NaN is skipped",
+			values: []float64{0, math.NaN(), 8},
+			config: SparkConfig{},
+			want:   " █",
+		},
+		{
+			name:   "NaN is a gap",
+			values: []float64{0, math.NaN(), 8},
+			config: SparkConfig{Gap: '·'},
+			want:   " ·█",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sparkline, err := NewSparkline(tc.config)
+			if err != nil {
+				t.Fatalf("NewSparkline() returned an unexpected error: %v", err)
+			}
+			for _, val := range tc.values {
+				sparkline.Add(val)
+			}
+			if got := sparkline.Render(); got != tc.want {
+				t.Errorf("Render()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := NewSparkline(SparkConfig{Log: true, Min: 0, Max: 10, HasMin: true, HasMax: true}); err == nil {
+		t.Error("NewSparkline() with a log-scale interval from 0 expected an error")
+	}
+}
+
+func TestLiveSparkline(t *testing.T) {
+	var writer bytes.Buffer
+	live, err := NewLiveSparkline(&writer, SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true, Width: 2, Refresh: time.Hour})
+	if err != nil {
+		t.Fatalf("NewLiveSparkline() returned an unexpected error: %v", err)
+	}
+	live.Add(0)
+	live.Add(8)
+	if got := writer.String(); got != "" {
+		t.Errorf("Add() drew %q before the refresh period", got)
+	}
+	if err := live.Flush(); err != nil {
+		t.Fatalf("Flush() returned an unexpected error: %v", err)
+	}
+	live.Add(4)
+	if err := live.Flush(); err != nil {
+		t.Fatalf("Flush() returned an unexpected error: %v", err)
+	}
+	if err := live.Flush(); err != nil { // Nothing new to draw.
+		t.Fatalf("Flush() returned an unexpected error: %v", err)
+	}
+	if got, want := writer.String(), "\r █\r█▄"; got != want {
+		t.Errorf("LiveSparkline wrote %q, want %q", got, want)
+	}
+
+	writer.Reset()
+	live, err = NewLiveSparkline(&writer, SparkConfig{Min: 0, Max: 8, HasMin: true, HasMax: true, Width: 2, Refresh: time.Nanosecond, ASCII: true})
+	if err != nil {
+		t.Fatalf("NewLiveSparkline() returned an unexpected error: %v", err)
+	}
+	live.Add(0)
+	live.Add(8)
+	if got, want := writer.String(), "_\n_#\n"; got != want {
+		t.Errorf("LiveSparkline wrote %q, want %q", got, want)
+	}
+
+	if _, err := NewLiveSparkline(&writer, SparkConfig{}); err == nil {
+		t.Error("NewLiveSparkline() without a width expected an error")
+	}
+}
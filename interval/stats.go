@@ -0,0 +1,244 @@
+package interval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ErrNoValues is returned when a statistic is asked of an empty stream.
+var ErrNoValues = errors.New("no numbers found in input")
+
+// Summary holds descriptive statistics for a set of values.
+type Summary struct {
+	Count  int
+	Min    float64
+	Max    float64
+	Mean   float64
+	Median float64
+	Stddev float64 // Sample standard deviation.
+	P25    float64
+	P75    float64
+	P90    float64
+	P95    float64
+	P99    float64
+}
+
+// Describe computes descriptive statistics for a set of values. NaN values are
+// ignored. If no values remain, Count is 0 and all statistics are NaN.
+func Describe(values []float64) Summary {
+	sorted := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Float64s(sorted)
+
+	if len(sorted) == 0 {
+		nan := math.NaN()
+		return Summary{Min: nan, Max: nan, Mean: nan, Median: nan, Stddev: nan,
+			P25: nan, P75: nan, P90: nan, P95: nan, P99: nan}
+	}
+
+	// Welford's algorithm keeps the variance numerically stable.
+	mean, m2 := 0.0, 0.0
+	for i, v := range sorted {
+		delta := v - mean
+		mean += delta / float64(i+1)
+		m2 += delta * (v - mean)
+	}
+	stddev := 0.0
+	if len(sorted) > 1 {
+		stddev = math.Sqrt(m2 / float64(len(sorted)-1))
+	}
+
+	return Summary{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Mean:   mean,
+		Median: percentileSorted(sorted, 50),
+		Stddev: stddev,
+		P25:    percentileSorted(sorted, 25),
+		P75:    percentileSorted(sorted, 75),
+		P90:    percentileSorted(sorted, 90),
+		P95:    percentileSorted(sorted, 95),
+		P99:    percentileSorted(sorted, 99),
+	}
+}
+
+// Percentile returns the p-th percentile (0-100) of a set of values, linearly
+// interpolating between the closest ranks. NaN values are ignored.
+func Percentile(values []float64, p float64) (float64, error) {
+	if math.IsNaN(p) || p < 0 || p > 100 {
+		return 0, fmt.Errorf("percentile must be in the range [0, 100]")
+	}
+	sorted := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	if len(sorted) == 0 {
+		return 0, ErrNoValues
+	}
+	sort.Float64s(sorted)
+	return percentileSorted(sorted, p), nil
+}
+
+// percentileSorted computes a percentile of a non-empty, sorted slice.
+func percentileSorted(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return Eval(rank-float64(lo), sorted[lo], sorted[hi])
+}
+
+// QuantileEstimator estimates a percentile of a stream in constant memory using
+// the P² algorithm (Jain & Chlamtac), without storing the observations.
+type QuantileEstimator struct {
+	p       float64    // Target quantile in [0, 1].
+	count   int        // Number of observations so far.
+	heights [5]float64 // Marker heights.
+	pos     [5]float64 // Actual marker positions.
+	desired [5]float64 // Desired marker positions.
+	incr    [5]float64 // Increments of the desired positions.
+}
+
+// NewQuantileEstimator returns an estimator for the p-th percentile (0-100).
+func NewQuantileEstimator(p float64) (*QuantileEstimator, error) {
+	if math.IsNaN(p) || p < 0 || p > 100 {
+		return nil, fmt.Errorf("percentile must be in the range [0, 100]")
+	}
+	q := p / 100
+	return &QuantileEstimator{
+		p:       q,
+		pos:     [5]float64{1, 2, 3, 4, 5},
+		desired: [5]float64{1, 1 + 2*q, 1 + 4*q, 3 + 2*q, 5},
+		incr:    [5]float64{0, q / 2, q, (1 + q) / 2, 1},
+	}, nil
+}
+
+// Add records an observation. NaN values are ignored.
+func (e *QuantileEstimator) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	if e.count < 5 {
+		e.heights[e.count] = x
+		e.count++
+		if e.count == 5 {
+			sort.Float64s(e.heights[:])
+		}
+		return
+	}
+	e.count++
+
+	// Find the cell k containing x, extending the extreme markers if needed.
+	var k int
+	switch {
+	case x < e.heights[0]:
+		e.heights[0] = x
+		k = 0
+	case x >= e.heights[4]:
+		e.heights[4] = x
+		k = 3
+	default:
+		for k = 0; k < 3 && x >= e.heights[k+1]; k++ {
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		e.pos[i]++
+	}
+	for i := range e.desired {
+		e.desired[i] += e.incr[i]
+	}
+
+	// Adjust the heights of the middle markers if they drifted off position.
+	for i := 1; i <= 3; i++ {
+		d := e.desired[i] - e.pos[i]
+		if (d >= 1 && e.pos[i+1]-e.pos[i] > 1) || (d <= -1 && e.pos[i-1]-e.pos[i] < -1) {
+			sign := math.Copysign(1, d)
+			h := e.parabolic(i, sign)
+			if e.heights[i-1] >= h || h >= e.heights[i+1] {
+				h = e.linear(i, sign)
+			}
+			e.heights[i] = h
+			e.pos[i] += sign
+		}
+	}
+}
+
+// Count returns the number of observations recorded so far.
+func (e *QuantileEstimator) Count() int {
+	return e.count
+}
+
+// Value returns the current estimate. With fewer than five observations the exact
+// percentile is returned. It returns an error if no observations were recorded.
+func (e *QuantileEstimator) Value() (float64, error) {
+	if e.count == 0 {
+		return 0, ErrNoValues
+	}
+	if e.count < 5 {
+		sorted := append([]float64(nil), e.heights[:e.count]...)
+		sort.Float64s(sorted)
+		return percentileSorted(sorted, e.p*100), nil
+	}
+	return e.heights[2], nil
+}
+
+func (e *QuantileEstimator) parabolic(i int, d float64) float64 {
+	q, n := e.heights, e.pos
+	return q[i] + d/(n[i+1]-n[i-1])*((n[i]-n[i-1]+d)*(q[i+1]-q[i])/(n[i+1]-n[i])+
+		(n[i+1]-n[i]-d)*(q[i]-q[i-1])/(n[i]-n[i-1]))
+}
+
+func (e *QuantileEstimator) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return e.heights[i] + d*(e.heights[j]-e.heights[i])/(e.pos[j]-e.pos[i])
+}
+
+// OutlierFence holds the bounds outside of which values are considered outliers.
+type OutlierFence struct {
+	Lower, Upper float64
+}
+
+// IQRFence computes Tukey's fences: values further than k times the interquartile
+// range below the first quartile or above the third quartile are outliers.
+// The conventional value of k is 1.5.
+func IQRFence(values []float64, k float64) (OutlierFence, error) {
+	if math.IsNaN(k) || k < 0 {
+		return OutlierFence{}, fmt.Errorf("k must be a non-negative number")
+	}
+	summary := Describe(values)
+	if summary.Count == 0 {
+		return OutlierFence{}, ErrNoValues
+	}
+	iqr := summary.P75 - summary.P25
+	return OutlierFence{Lower: summary.P25 - k*iqr, Upper: summary.P75 + k*iqr}, nil
+}
+
+// ZScoreFence computes fences at k standard deviations from the mean.
+// The conventional value of k is 3.
+func ZScoreFence(values []float64, k float64) (OutlierFence, error) {
+	if math.IsNaN(k) || k < 0 {
+		return OutlierFence{}, fmt.Errorf("k must be a non-negative number")
+	}
+	summary := Describe(values)
+	if summary.Count == 0 {
+		return OutlierFence{}, ErrNoValues
+	}
+	return OutlierFence{Lower: summary.Mean - k*summary.Stddev, Upper: summary.Mean + k*summary.Stddev}, nil
+}
+
+// IsOutlier reports whether a value lies outside the fence.
+func (f OutlierFence) IsOutlier(val float64) bool {
+	return val < f.Lower || val > f.Upper
+}
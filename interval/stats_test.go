@@ -0,0 +1,174 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	t.Run("simple case", func(t *testing.T) {
+		got := Describe([]float64{4, 1, 3, 2, 5})
+		want := Summary{Count: 5, Min: 1, Max: 5, Mean: 3, Median: 3, Stddev: math.Sqrt(2.5),
+			P25: 2, P75: 4, P90: 4.6, P95: 4.8, P99: 4.96}
+		fields := [][2]float64{
+			{got.Min, want.Min}, {got.Max, want.Max}, {got.Mean, want.Mean},
+			{got.Median, want.Median}, {got.Stddev, want.Stddev}, {got.P25, want.P25},
+			{got.P75, want.P75}, {got.P90, want.P90}, {got.P95, want.P95}, {got.P99, want.P99},
+		}
+		if got.Count != want.Count {
+			t.Errorf("Describe() Count = %v, want %v", got.Count, want.Count)
+		}
+		for _, f := range fields {
+			if !almostEqual(f[0], f[1]) {
+				t.Errorf("Describe() = %+v, want %+v", got, want)
+				break
+			}
+		}
+	})
+
+	t.Run("single value", func(t *testing.T) {
+		got := Describe([]float64{7})
+		if got.Count != 1 || got.Min != 7 || got.Max != 7 || got.Median != 7 || got.Stddev != 0 {
+			t.Errorf("Describe() = %+v, want all statistics 7 and stddev 0", got)
+		}
+	})
+
+	t.Run("NaN values are ignored", func(t *testing.T) {
+		got := Describe([]float64{1, math.NaN(), 3})
+		if got.Count != 2 || !almostEqual(got.Mean, 2) {
+			t.Errorf("Describe() = %+v, want count 2 and mean 2", got)
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		got := Describe(nil)
+		if got.Count != 0 || !math.IsNaN(got.Mean) || !math.IsNaN(got.Min) {
+			t.Errorf("Describe() = %+v, want count 0 and NaN statistics", got)
+		}
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{15, 20, 35, 40, 50}
+
+	tests := []struct {
+		name    string
+		values  []float64
+		p       float64
+		want    float64
+		wantErr bool
+	}{
+		{"minimum", values, 0, 15, false},
+		{"maximum", values, 100, 50, false},
+		{"median", values, 50, 35, false},
+		{"interpolated", values, 40, 29, false},
+		{"unsorted input", []float64{50, 15, 40, 20, 35}, 50, 35, false},
+		{"out of range", values, 101, 0, true},
+		{"negative", values, -1, 0, true},
+		{"empty input", nil, 50, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Percentile(tt.values, tt.p)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Percentile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !almostEqual(got, tt.want) {
+				t.Errorf("Percentile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuantileEstimator(t *testing.T) {
+	t.Run("invalid percentile", func(t *testing.T) {
+		if _, err := NewQuantileEstimator(120); err == nil {
+			t.Error("NewQuantileEstimator() expected an error for p > 100, but got nil")
+		}
+	})
+
+	t.Run("empty stream", func(t *testing.T) {
+		e, _ := NewQuantileEstimator(50)
+		if _, err := e.Value(); err == nil {
+			t.Error("Value() expected an error for an empty stream, but got nil")
+		}
+	})
+
+	t.Run("few observations are exact", func(t *testing.T) {
+		e, _ := NewQuantileEstimator(50)
+		for _, v := range []float64{3, 1, 2} {
+			e.Add(v)
+		}
+		if got, _ := e.Value(); got != 2 {
+			t.Errorf("Value() = %v, want 2", got)
+		}
+	})
+
+	for _, p := range []float64{10, 50, 90, 99} {
+		t.Run(fmt.Sprintf("large stream p%v", p), func(t *testing.T) {
+			e, _ := NewQuantileEstimator(p)
+			// A deterministic permutation of 0..9999.
+			for i := 0; i < 10000; i++ {
+				e.Add(float64((i * 7919) % 10000))
+			}
+			got, _ := e.Value()
+			want := p / 100 * 9999
+			if math.Abs(got-want) > 100 {
+				t.Errorf("Value() for p%v = %v, want close to %v", p, got, want)
+			}
+			if e.Count() != 10000 {
+				t.Errorf("Count() = %v, want 10000", e.Count())
+			}
+		})
+	}
+}
+
+func TestOutlierFence(t *testing.T) {
+	values := []float64{10, 12, 11, 13, 12, 11, 100, -50}
+
+	t.Run("IQR", func(t *testing.T) {
+		fence, err := IQRFence(values, 1.5)
+		if err != nil {
+			t.Fatalf("IQRFence() returned an unexpected error: %v", err)
+		}
+		// Q1 = 10.75, Q3 = 12.25, IQR = 1.5.
+		if !almostEqual(fence.Lower, 8.5) || !almostEqual(fence.Upper, 14.5) {
+			t.Errorf("IQRFence() = %+v, want {8.5 14.5}", fence)
+		}
+		for _, v := range values {
+			want := v == 100 || v == -50
+			if got := fence.IsOutlier(v); got != want {
+				t.Errorf("IsOutlier(%v) = %v, want %v", v, got, want)
+			}
+		}
+	})
+
+	t.Run("z-score", func(t *testing.T) {
+		fence, err := ZScoreFence([]float64{1, 2, 3, 4, 5}, 1)
+		if err != nil {
+			t.Fatalf("ZScoreFence() returned an unexpected error: %v", err)
+		}
+		sd := math.Sqrt(2.5)
+		if !almostEqual(fence.Lower, 3-sd) || !almostEqual(fence.Upper, 3+sd) {
+			t.Errorf("ZScoreFence() = %+v, want {%v %v}", fence, 3-sd, 3+sd)
+		}
+		if !fence.IsOutlier(5) || fence.IsOutlier(4) {
+			t.Errorf("IsOutlier() misclassified values for fence %+v", fence)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := IQRFence(nil, 1.5); err == nil {
+			t.Error("IQRFence() expected an error for empty input, but got nil")
+		}
+		if _, err := IQRFence(values, -1); err == nil {
+			t.Error("IQRFence() expected an error for negative k, but got nil")
+		}
+		if _, err := ZScoreFence(nil, 3); err == nil {
+			t.Error("ZScoreFence() expected an error for empty input, but got nil")
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package interval
+
+import "math"
+
+// kahanSum is a running sum with Neumaier's compensated summation: the low
+// order bits lost by each addition are kept aside and added back. Compensated
+// summation reduces rounding error, so the error of the sum of millions of
+// values barely grows with their count, where a plain sum's does.
+type kahanSum struct {
+	sum, c float64
+}
+
+// add adds val to the sum.
+func (k *kahanSum) add(val float64) {
+	t := k.sum + val
+	if math.Abs(k.sum) >= math.Abs(val) {
+		k.c += (k.sum - t) + val
+	} else {
+		k.c += (val - t) + k.sum
+	}
+	k.sum = t
+}
+
+// value returns the sum.
+func (k *kahanSum) value() float64 {
+	return k.sum + k.c
+}
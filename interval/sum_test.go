@@ -0,0 +1,30 @@
+package interval
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestKahanSum(t *testing.T) {
+	t.Run("many small values", func(t *testing.T) {
+		var sum kahanSum
+		exact := new(big.Float).SetPrec(256)
+		for i := 0; i < 1000000; i++ {
+			sum.add(0.1)
+			exact.Add(exact, big.NewFloat(0.1))
+		}
+		if want, _ := exact.Float64(); sum.value() != want {
+			t.Errorf("kahanSum of a million 0.1 = %v, want %v", sum.value(), want)
+		}
+	})
+
+	t.Run("large values cancel", func(t *testing.T) {
+		var sum kahanSum
+		for _, val := range []float64{1, 1e100, 1, -1e100} {
+			sum.add(val)
+		}
+		if sum.value() != 2 {
+			t.Errorf("kahanSum(1, 1e100, 1, -1e100) = %v, want 2", sum.value())
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package interval
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ImageConfig holds the configuration for rendering a sparkline as an SVG or
+// PNG image.
+type ImageConfig struct {
+	Width, Height int // Size of the image, in pixels.
+	Min, Max      float64
+	HasRange      bool // Use Min and Max instead of the range of the input.
+	Color         RGB
+}
+
+// imageInset keeps the line clear of the edges, so it is not clipped at the min
+// and the max.
+const imageInset = 1.0
+
+// GenerateSVG renders the numbers read from scanner as an SVG image: a line
+// through the values over a lighter area down to the bottom edge. Values are
+// spread evenly across the width, and scaled to the range of the input, or to
+// [Min, Max], across the height. Without numbers, the image is empty.
+func GenerateSVG(scanner *bufio.Scanner, writer io.Writer, config ImageConfig) error {
+	points, err := readImagePoints(scanner, config)
+	if err != nil {
+		return err
+	}
+
+	color := config.Color.Hex()
+	fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		config.Width, config.Height, config.Width, config.Height)
+	if len(points) > 0 {
+		coords := make([]string, len(points))
+		for i, p := range points {
+			coords[i] = svgNumber(p[0]) + "," + svgNumber(p[1])
+		}
+		line := strings.Join(coords, " ")
+		first, last := svgNumber(points[0][0]), svgNumber(points[len(points)-1][0])
+		bottom := strconv.Itoa(config.Height)
+		fmt.Fprintf(writer, `<polygon points="%s,%s %s %s,%s" fill="%s" fill-opacity="0.2"/>`+"\n", first, bottom, line, last, bottom, color)
+		fmt.Fprintf(writer, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1" stroke-linejoin="round"/>`+"\n", line, color)
+	}
+	_, err = fmt.Fprintln(writer, "</svg>")
+	return err
+}
+
+// readImagePoints reads the numbers of the stream and places them in the image:
+// spread evenly across the width, and scaled across the height, with the max
+// at the top. A single value is placed in the middle.
+func readImagePoints(scanner *bufio.Scanner, config ImageConfig) ([][2]float64, error) {
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("the size of the image must be positive")
+	}
+	numbers, err := readAllNumbers(scanner)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := config.Min, config.Max
+	if !config.HasRange {
+		var r RunningRange
+		for _, n := range numbers {
+			r.Add(n)
+		}
+		min, max = r.Min, r.Max
+	}
+
+	w, h := float64(config.Width), float64(config.Height)
+	points := make([][2]float64, len(numbers))
+	for i, n := range numbers {
+		x := w / 2
+		if len(numbers) > 1 {
+			x = float64(i) / float64(len(numbers)-1) * w
+		}
+		t := 0.5
+		if max != min {
+			t, _ = Deval(n, min, max)
+			t = Limit(t, 0, 1)
+		}
+		points[i] = [2]float64{x, h - imageInset - t*(h-2*imageInset)}
+	}
+	return points, nil
+}
+
+// svgNumber formats a coordinate with at most two decimals.
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,75 @@
+package interval
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSVG(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		config ImageConfig
+		want   string
+	}{
+		{
+			name:   "Auto-scaled",
+			input:  "0 5 10",
+			config: ImageConfig{Width: 10, Height: 12, Color: RGB{255, 0, 0}},
+			want: `<svg xmlns="http://www.w3.org/2000/svg" width="10" height="12" viewBox="0 0 10 12">
+<polygon points="0,12 0,11 5,6 10,1 10,12" fill="#ff0000" fill-opacity="0.2"/>
+<polyline points="0,11 5,6 10,1" fill="none" stroke="#ff0000" stroke-width="1" stroke-linejoin="round"/>
+</svg>
+`,
+		},
+		{
+			name:   "Fixed range clamps values",
+			input:  "-5 20",
+			config: ImageConfig{Width: 10, Height: 12, HasRange: true, Min: 0, Max: 10},
+			want: `<svg xmlns="http://www.w3.org/2000/svg" width="10" height="12" viewBox="0 0 10 12">
+<polygon points="0,12 0,11 10,1 10,12" fill="#000000" fill-opacity="0.2"/>
+<polyline points="0,11 10,1" fill="none" stroke="#000000" stroke-width="1" stroke-linejoin="round"/>
+</svg>
+`,
+		},
+		{
+			name:   "Single value is centered",
+			input:  "3",
+			config: ImageConfig{Width: 10, Height: 12},
+			want: `<svg xmlns="http://www.w3.org/2000/svg" width="10" height="12" viewBox="0 0 10 12">
+<polygon points="5,12 5,6 5,12" fill="#000000" fill-opacity="0.2"/>
+<polyline points="5,6" fill="none" stroke="#000000" stroke-width="1" stroke-linejoin="round"/>
+</svg>
+`,
+		},
+		{
+			name:   "Empty input",
+			input:  "",
+			config: ImageConfig{Width: 10, Height: 12},
+			want: `<svg xmlns="http://www.w3.org/2000/svg" width="10" height="12" viewBox="0 0 10 12">
+</svg>
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scanner := bufio.NewScanner(strings.NewReader(tc.input))
+			var writer bytes.Buffer
+
+			if err := GenerateSVG(scanner, &writer, tc.config); err != nil {
+				t.Fatalf("GenerateSVG() returned an unexpected error: %v", err)
+			}
+			if got := writer.String(); got != tc.want {
+				t.Errorf("GenerateSVG()\n  got: %q\n want: %q", got, tc.want)
+			}
+		})
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader("1"))
+	if err := GenerateSVG(scanner, &bytes.Buffer{}, ImageConfig{}); err == nil {
+		t.Error("GenerateSVG() expected an error for an empty size, but got nil")
+	}
+}
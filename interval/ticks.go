@@ -0,0 +1,129 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// maxTicks is the most ticks Ticks creates.
+const maxTicks = 1 << 20
+
+// Ticks returns about count "nice" tick values covering the interval [a, b],
+// as for the axis of a chart: the multiples of a step of 1, 2 or 5 times a
+// power of ten, from the last one at or below the start of the interval to the
+// first one at or above its end. The ticks follow the direction of the
+// interval. An interval of zero length has the single tick a.
+func Ticks(count int, a, b float64) ([]float64, error) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return nil, fmt.Errorf("cannot create ticks: NaN bounds")
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return nil, fmt.Errorf("cannot create ticks: infinite bounds")
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be a positive integer")
+	}
+	if a == b {
+		return []float64{a}, nil
+	}
+	lo, hi := min(a, b), max(a, b)
+	if math.IsInf(hi-lo, 0) {
+		return nil, fmt.Errorf("cannot create ticks: the interval is too wide")
+	}
+
+	mantissa, exponent := tickStep((hi - lo) / float64(count))
+	step := tickValue(1, mantissa, exponent)
+	first, last := tickIndex(lo/step, math.Floor), tickIndex(hi/step, math.Ceil)
+	// Past 2^53, consecutive tick indices are no longer distinct floats.
+	if max(math.Abs(first), math.Abs(last)) > 1<<53 {
+		return nil, fmt.Errorf("cannot create ticks: the step is too fine for the magnitude of the bounds")
+	}
+	n := int(last - first)
+	if n >= maxTicks {
+		return nil, fmt.Errorf("cannot create ticks: more than %d ticks", maxTicks)
+	}
+	ticks := make([]float64, 0, n+1)
+	for i := 0; i <= n; i++ {
+		ticks = append(ticks, tickValue(first+float64(i), mantissa, exponent))
+	}
+	if a > b {
+		slices.Reverse(ticks)
+	}
+	return ticks, nil
+}
+
+// tickStep returns the smallest step of the form mantissa * 10^exponent, with a
+// mantissa of 1, 2 or 5, that is at least raw, give or take rounding error.
+func tickStep(raw float64) (mantissa float64, exponent int) {
+	exponent = int(math.Floor(math.Log10(raw)))
+	switch fraction := raw / math.Pow10(exponent) / (1 + 1e-9); {
+	case fraction <= 1:
+		return 1, exponent
+	case fraction <= 2:
+		return 2, exponent
+	case fraction <= 5:
+		return 5, exponent
+	}
+	return 1, exponent + 1
+}
+
+// tickIndex rounds x, a bound divided by the step, to the index of a tick with
+// round. A quotient within rounding error of an integer is that integer, so
+// that a bound on a tick does not gain a tick beyond it.
+func tickIndex(x float64, round func(float64) float64) float64 {
+	if k := math.Round(x); math.Abs(x-k) <= 1e-9*max(1, math.Abs(x)) {
+		return k
+	}
+	return round(x)
+}
+
+// tickValue returns the k-th multiple of mantissa * 10^exponent. Negative powers
+// of ten divide instead of multiplying, so that decimal ticks such as 0.3 come
+// out as the closest float to their decimal value.
+func tickValue(k, mantissa float64, exponent int) float64 {
+	if exponent < 0 {
+		return k * mantissa / math.Pow10(-exponent)
+	}
+	return k * mantissa * math.Pow10(exponent)
+}
+
+// Nice expands the interval [a, b] outward to round bounds, multiples of the
+// step of about ten ticks over it, so that a chart of the values it holds
+// starts and ends on labeled ticks, as d3's nice() does. The bounds keep their
+// direction. An interval of zero length is returned as is.
+func Nice(a, b float64) (float64, float64, error) {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return 0, 0, fmt.Errorf("cannot round an interval: NaN bounds")
+	}
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return 0, 0, fmt.Errorf("cannot round an interval: infinite bounds")
+	}
+	if a == b {
+		return a, b, nil
+	}
+
+	lo, hi := min(a, b), max(a, b)
+	// Rounding the bounds widens the interval, which may call for a larger
+	// step: round again until they settle.
+	for range 10 {
+		if math.IsInf(hi-lo, 0) {
+			return 0, 0, fmt.Errorf("cannot round an interval: the interval is too wide")
+		}
+		mantissa, exponent := tickStep((hi - lo) / 10)
+		step := tickValue(1, mantissa, exponent)
+		niceLo := tickValue(tickIndex(lo/step, math.Floor), mantissa, exponent)
+		niceHi := tickValue(tickIndex(hi/step, math.Ceil), mantissa, exponent)
+		if niceLo == lo && niceHi == hi {
+			break
+		}
+		lo, hi = niceLo, niceHi
+	}
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return 0, 0, fmt.Errorf("cannot round an interval: the interval is too wide")
+	}
+	if a > b {
+		return hi, lo, nil
+	}
+	return lo, hi, nil
+}
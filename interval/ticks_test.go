@@ -0,0 +1,84 @@
+package interval
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTicks(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		a       float64
+		b       float64
+		want    []float64
+		wantErr bool
+	}{
+		{"unit interval", 5, 0, 1, []float64{0, 0.2, 0.4, 0.6, 0.8, 1}, false},
+		{"exact decimals", 10, 0, 1, []float64{0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}, false},
+		{"rounded up to a power of ten", 10, 0, 97, []float64{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, false},
+		{"covers the bounds", 5, 0.3, 1.7, []float64{0, 0.5, 1, 1.5, 2}, false},
+		{"bounds on ticks", 3, 0.3, 0.9, []float64{0.2, 0.4, 0.6, 0.8, 1}, false},
+		{"negative start", 4, -3, 7, []float64{-5, 0, 5, 10}, false},
+		{"large values", 5, 1200, 5300, []float64{1000, 2000, 3000, 4000, 5000, 6000}, false},
+		{"inverted interval", 4, 10, 0, []float64{10, 5, 0}, false},
+		{"single tick", 1, 2, 3, []float64{2, 3}, false},
+		{"zero length", 5, 4, 4, []float64{4}, false},
+		{"zero count", 0, 0, 1, nil, true},
+		{"a is NaN", 5, math.NaN(), 1, nil, true},
+		{"b is Inf", 5, 0, math.Inf(1), nil, true},
+		{"too wide", 5, -math.MaxFloat64, math.MaxFloat64, nil, true},
+		{"step too fine for the bounds", 10, 1e15, 1e15 + 1, nil, true},
+		{"too many ticks", 1 << 30, 0, 1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Ticks(tt.count, tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ticks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ticks(%d, %v, %v) = %v, want %v", tt.count, tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNice(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       float64
+		b       float64
+		wantA   float64
+		wantB   float64
+		wantErr bool
+	}{
+		{"already nice", 0, 100, 0, 100, false},
+		{"fractions", 0.201, 0.996, 0.2, 1, false},
+		{"exact decimals", 0.31, 0.87, 0.3, 0.9, false},
+		{"large values", 1234, 5678, 1000, 6000, false},
+		{"negative values", -3.7, 12.2, -4, 14, false},
+		{"inverted interval", 97, 3, 100, 0, false},
+		{"within one step", 9.5, 10.5, 9.5, 10.5, false},
+		{"zero length", 4, 4, 4, 4, false},
+		{"a is NaN", math.NaN(), 1, 0, 0, true},
+		{"b is Inf", 0, math.Inf(-1), 0, 0, true},
+		{"too wide", -math.MaxFloat64, math.MaxFloat64, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotA, gotB, err := Nice(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Nice() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (gotA != tt.wantA || gotB != tt.wantB) {
+				t.Errorf("Nice(%v, %v) = %v, %v, want %v, %v", tt.a, tt.b, gotA, gotB, tt.wantA, tt.wantB)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package interval
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// TimeScale maps a domain of times to a numeric range in proportion, as the
+// time axis of a chart does. Its ticks fall on calendar boundaries, such as
+// whole minutes, hours, days or months, in the location of the start of the
+// domain.
+type TimeScale struct {
+	Domain [2]time.Time
+	Range  [2]float64
+	// Clamp restricts mapped values to the range, and inverted ones to the domain.
+	Clamp bool
+}
+
+// Map returns the value of the range that t maps to.
+func (s TimeScale) Map(t time.Time) (float64, error) {
+	span := secondsSince(s.Domain[1], s.Domain[0])
+	return scaleMap(secondsSince(t, s.Domain[0]), [2]float64{0, span}, s.Range, s.Clamp, identity)
+}
+
+// Invert returns the time of the domain that maps to val, in the location of
+// the start of the domain.
+func (s TimeScale) Invert(val float64) (time.Time, error) {
+	span := secondsSince(s.Domain[1], s.Domain[0])
+	offset, err := scaleInvert(val, [2]float64{0, span}, s.Range, s.Clamp, identity, identity)
+	switch {
+	case err != nil:
+		return time.Time{}, err
+	case offset == span:
+		return s.Domain[1], nil
+	case math.Abs(offset) > 1e15:
+		return time.Time{}, fmt.Errorf("%v is out of the range of timestamps", val)
+	}
+	whole, frac := math.Modf(offset)
+	sec, nsec := s.Domain[0].Unix()+int64(whole), int64(s.Domain[0].Nanosecond())+int64(math.Round(frac*1e9))
+	return time.Unix(sec, nsec).In(s.Domain[0].Location()), nil
+}
+
+// Ticks returns about count times within the domain that fall on calendar
+// boundaries: the interval between them is picked among 1, 5, 15 and 30
+// seconds or minutes, 1, 3, 6 and 12 hours, 1 and 2 days, 1 week, 1 and 3
+// months, and nice numbers of years. Intervals under a second use the ticks of
+// Ticks, in seconds.
+func (s TimeScale) Ticks(count int) ([]time.Time, error) {
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be a positive integer")
+	}
+	loc := s.Domain[0].Location()
+	lo, hi := s.Domain[0], s.Domain[1].In(loc)
+	if hi.Before(lo) {
+		lo, hi = hi, lo
+	}
+	span := secondsSince(hi, lo)
+	if span > 1e15 {
+		return nil, fmt.Errorf("cannot create ticks: the interval is too wide")
+	}
+
+	var ticks []time.Time
+	target := span / float64(count)
+	iv, ok := pickCalendarInterval(target)
+	switch {
+	case span == 0:
+		ticks = []time.Time{lo}
+	case !ok:
+		// Sub-second ticks, counted from the second before the start.
+		origin := lo.Truncate(time.Second)
+		offsets, err := Ticks(count, secondsSince(lo, origin), secondsSince(hi, origin))
+		if err != nil {
+			return nil, err
+		}
+		for _, offset := range offsets {
+			if t := origin.Add(time.Duration(math.Round(offset * 1e9))); !t.Before(lo) && !t.After(hi) {
+				ticks = append(ticks, t)
+			}
+		}
+	default:
+		if iv.unit == calendarYear {
+			mantissa, exponent := tickStep(target / calendarYear.seconds)
+			iv.step = max(1, int(tickValue(1, mantissa, exponent)))
+		}
+		for t := iv.floor(lo); !t.After(hi); t = iv.next(t) {
+			if !t.Before(lo) {
+				ticks = append(ticks, t)
+			}
+		}
+	}
+	if s.Domain[0].After(s.Domain[1]) {
+		slices.Reverse(ticks)
+	}
+	return ticks, nil
+}
+
+// calendarUnit is a unit of calendar time that ticks are counted in.
+type calendarUnit struct {
+	seconds float64 // The typical length of the unit.
+}
+
+var (
+	calendarSecond = &calendarUnit{1}
+	calendarMinute = &calendarUnit{60}
+	calendarHour   = &calendarUnit{3600}
+	calendarDay    = &calendarUnit{86400}
+	calendarWeek   = &calendarUnit{7 * 86400}
+	calendarMonth  = &calendarUnit{30 * 86400}
+	calendarYear   = &calendarUnit{365 * 86400}
+)
+
+// calendarInterval is an interval between ticks, a number of calendar units.
+type calendarInterval struct {
+	unit *calendarUnit
+	step int
+}
+
+// calendarIntervals are the intervals between ticks, by increasing length.
+var calendarIntervals = []calendarInterval{
+	{calendarSecond, 1}, {calendarSecond, 5}, {calendarSecond, 15}, {calendarSecond, 30},
+	{calendarMinute, 1}, {calendarMinute, 5}, {calendarMinute, 15}, {calendarMinute, 30},
+	{calendarHour, 1}, {calendarHour, 3}, {calendarHour, 6}, {calendarHour, 12},
+	{calendarDay, 1}, {calendarDay, 2}, {calendarWeek, 1},
+	{calendarMonth, 1}, {calendarMonth, 3}, {calendarYear, 1},
+}
+
+// seconds returns the typical length of the interval.
+func (iv calendarInterval) seconds() float64 {
+	return iv.unit.seconds * float64(iv.step)
+}
+
+// pickCalendarInterval returns the interval closest to target seconds, in
+// proportion. It reports false if target is under a second.
+func pickCalendarInterval(target float64) (calendarInterval, bool) {
+	if target < 1 {
+		return calendarInterval{}, false
+	}
+	i, _ := slices.BinarySearchFunc(calendarIntervals, target, func(iv calendarInterval, t float64) int {
+		return cmp.Compare(iv.seconds(), t)
+	})
+	switch {
+	case i == len(calendarIntervals):
+		return calendarIntervals[i-1], true
+	case i > 0 && target/calendarIntervals[i-1].seconds() < calendarIntervals[i].seconds()/target:
+		return calendarIntervals[i-1], true
+	}
+	return calendarIntervals[i], true
+}
+
+// floor returns the last boundary of the interval at or before t: a multiple
+// of the step counted from the start of the enclosing minute, hour, day, month
+// or year, or the Monday starting the week.
+func (iv calendarInterval) floor(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	h, mi, sec := t.Clock()
+	loc := t.Location()
+	switch iv.unit {
+	case calendarSecond:
+		return time.Date(y, mo, d, h, mi, sec-sec%iv.step, 0, loc)
+	case calendarMinute:
+		return time.Date(y, mo, d, h, mi-mi%iv.step, 0, 0, loc)
+	case calendarHour:
+		return time.Date(y, mo, d, h-h%iv.step, 0, 0, 0, loc)
+	case calendarDay:
+		return time.Date(y, mo, d-(d-1)%iv.step, 0, 0, 0, 0, loc)
+	case calendarWeek:
+		return time.Date(y, mo, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)
+	case calendarMonth:
+		return time.Date(y, mo-(mo-1)%time.Month(iv.step), 1, 0, 0, 0, 0, loc)
+	}
+	return time.Date(y-((y%iv.step)+iv.step)%iv.step, time.January, 1, 0, 0, 0, 0, loc)
+}
+
+// next returns the boundary of the interval that follows t, a boundary itself.
+// A boundary that the end of a month or a clock change lands on twice is left
+// out.
+func (iv calendarInterval) next(t time.Time) time.Time {
+	for k := 1; ; k++ {
+		if next := iv.floor(iv.add(t, k*iv.step)); next.After(t) {
+			return next
+		}
+	}
+}
+
+// add returns t moved by n units on the calendar.
+func (iv calendarInterval) add(t time.Time, n int) time.Time {
+	y, mo, d := t.Date()
+	h, mi, sec := t.Clock()
+	loc := t.Location()
+	switch iv.unit {
+	case calendarSecond:
+		sec += n
+	case calendarMinute:
+		mi += n
+	case calendarHour:
+		h += n
+	case calendarDay:
+		d += n
+	case calendarWeek:
+		d += 7 * n
+	case calendarMonth:
+		mo += time.Month(n)
+	default:
+		y += n
+	}
+	return time.Date(y, mo, d, h, mi, sec, 0, loc)
+}
+
+// secondsSince returns the number of seconds from origin to t, keeping
+// nanosecond precision over spans of centuries.
+func secondsSince(t, origin time.Time) float64 {
+	return float64(t.Unix()-origin.Unix()) + float64(t.Nanosecond()-origin.Nanosecond())/1e9
+}
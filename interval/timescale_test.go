@@ -0,0 +1,150 @@
+package interval
+
+import (
+	"testing"
+	"time"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	tm, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		t.Fatalf("time.Parse(%q) returned an unexpected error: %v", s, err)
+	}
+	return tm
+}
+
+func TestTimeScale(t *testing.T) {
+	s := TimeScale{
+		Domain: [2]time.Time{mustTime(t, "2023-10-15T09:00:00Z"), mustTime(t, "2023-10-15T10:00:00Z")},
+		Range:  [2]float64{0, 100},
+	}
+	tests := []struct {
+		name string
+		time string
+		want float64
+	}{
+		{"start", "2023-10-15T09:00:00Z", 0},
+		{"quarter", "2023-10-15T09:15:00Z", 25},
+		{"end", "2023-10-15T10:00:00Z", 100},
+		{"extrapolates", "2023-10-15T11:00:00Z", 200},
+		{"sub-second", "2023-10-15T09:00:00.036Z", 0.001},
+		{"other time zone", "2023-10-15T11:30:00+02:00", 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := mustTime(t, tt.time)
+			got, err := s.Map(tm)
+			if err != nil {
+				t.Fatalf("Map() returned an unexpected error: %v", err)
+			}
+			if !almostEqual(got, tt.want) {
+				t.Errorf("Map(%v) = %v, want %v", tt.time, got, tt.want)
+			}
+			back, err := s.Invert(got)
+			if err != nil {
+				t.Fatalf("Invert(%v) returned an unexpected error: %v", got, err)
+			}
+			if !back.Equal(tm) {
+				t.Errorf("Invert(%v) = %v, want %v", got, back, tm)
+			}
+		})
+	}
+
+	t.Run("clamped", func(t *testing.T) {
+		clamped := s
+		clamped.Clamp = true
+		if got, _ := clamped.Map(mustTime(t, "2023-10-15T12:00:00Z")); got != 100 {
+			t.Errorf("Map() = %v, want 100", got)
+		}
+		if got, _ := clamped.Invert(-50); !got.Equal(s.Domain[0]) {
+			t.Errorf("Invert(-50) = %v, want %v", got, s.Domain[0])
+		}
+	})
+
+	t.Run("zero delta", func(t *testing.T) {
+		empty := TimeScale{Domain: [2]time.Time{s.Domain[0], s.Domain[0]}, Range: s.Range}
+		if _, err := empty.Map(s.Domain[1]); err == nil {
+			t.Error("Map() expected an error, but got nil")
+		}
+	})
+}
+
+func TestTimeScaleTicks(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		count int
+		want  []string
+	}{
+		{"minutes", "2023-10-15T09:07:00Z", "2023-10-15T09:58:00Z", 4,
+			[]string{"2023-10-15T09:15:00Z", "2023-10-15T09:30:00Z", "2023-10-15T09:45:00Z"}},
+		{"hours", "2023-10-15T01:30:00Z", "2023-10-15T20:00:00Z", 6,
+			[]string{"2023-10-15T03:00:00Z", "2023-10-15T06:00:00Z", "2023-10-15T09:00:00Z", "2023-10-15T12:00:00Z", "2023-10-15T15:00:00Z", "2023-10-15T18:00:00Z"}},
+		{"hours in a time zone", "2023-10-15T01:30:00+05:30", "2023-10-15T08:00:00+05:30", 3,
+			[]string{"2023-10-15T03:00:00+05:30", "2023-10-15T06:00:00+05:30"}},
+		{"days", "2023-10-14T12:00:00Z", "2023-10-18T00:00:00Z", 4,
+			[]string{"2023-10-15T00:00:00Z", "2023-10-16T00:00:00Z", "2023-10-17T00:00:00Z", "2023-10-18T00:00:00Z"}},
+		{"every other day restarts each month", "2023-01-28T00:00:00Z", "2023-02-05T00:00:00Z", 4,
+			[]string{"2023-01-29T00:00:00Z", "2023-01-31T00:00:00Z", "2023-02-01T00:00:00Z", "2023-02-03T00:00:00Z", "2023-02-05T00:00:00Z"}},
+		{"weeks start on mondays", "2023-10-01T00:00:00Z", "2023-10-31T00:00:00Z", 4,
+			[]string{"2023-10-02T00:00:00Z", "2023-10-09T00:00:00Z", "2023-10-16T00:00:00Z", "2023-10-23T00:00:00Z", "2023-10-30T00:00:00Z"}},
+		{"months", "2023-01-15T00:00:00Z", "2023-05-01T00:00:00Z", 4,
+			[]string{"2023-02-01T00:00:00Z", "2023-03-01T00:00:00Z", "2023-04-01T00:00:00Z", "2023-05-01T00:00:00Z"}},
+		{"quarters", "2023-01-01T00:00:00Z", "2023-12-31T00:00:00Z", 4,
+			[]string{"2023-01-01T00:00:00Z", "2023-04-01T00:00:00Z", "2023-07-01T00:00:00Z", "2023-10-01T00:00:00Z"}},
+		{"decades", "1971-06-01T00:00:00Z", "2023-06-01T00:00:00Z", 6,
+			[]string{"1980-01-01T00:00:00Z", "1990-01-01T00:00:00Z", "2000-01-01T00:00:00Z", "2010-01-01T00:00:00Z", "2020-01-01T00:00:00Z"}},
+		{"sub-second", "2023-10-15T09:00:00.1Z", "2023-10-15T09:00:00.9Z", 4,
+			[]string{"2023-10-15T09:00:00.2Z", "2023-10-15T09:00:00.4Z", "2023-10-15T09:00:00.6Z", "2023-10-15T09:00:00.8Z"}},
+		{"inverted domain", "2023-10-15T10:00:00Z", "2023-10-15T09:00:00Z", 2,
+			[]string{"2023-10-15T10:00:00Z", "2023-10-15T09:30:00Z", "2023-10-15T09:00:00Z"}},
+		{"zero length", "2023-10-15T09:00:00Z", "2023-10-15T09:00:00Z", 5,
+			[]string{"2023-10-15T09:00:00Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := TimeScale{Domain: [2]time.Time{mustTime(t, tt.a), mustTime(t, tt.b)}}
+			got, err := s.Ticks(tt.count)
+			if err != nil {
+				t.Fatalf("Ticks() returned an unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Ticks(%d) = %v, want %v", tt.count, got, tt.want)
+			}
+			for i, want := range tt.want {
+				if got[i].Format(time.RFC3339Nano) != want {
+					t.Errorf("Ticks(%d)[%d] = %v, want %v", tt.count, i, got[i].Format(time.RFC3339Nano), want)
+				}
+			}
+		})
+	}
+
+	t.Run("clock changes", func(t *testing.T) {
+		paris, err := time.LoadLocation("Europe/Paris")
+		if err != nil {
+			t.Skip("time zone database not available")
+		}
+		// Clocks go back from 03:00 to 02:00 on that night.
+		s := TimeScale{Domain: [2]time.Time{
+			time.Date(2023, 10, 29, 0, 0, 0, 0, paris),
+			time.Date(2023, 10, 29, 6, 0, 0, 0, paris),
+		}}
+		got, err := s.Ticks(7)
+		if err != nil {
+			t.Fatalf("Ticks() returned an unexpected error: %v", err)
+		}
+		for i := 1; i < len(got); i++ {
+			if !got[i].After(got[i-1]) {
+				t.Fatalf("Ticks() = %v, want increasing times", got)
+			}
+		}
+		if len(got) < 6 || got[0].Hour() != 0 || got[len(got)-1].Hour() != 6 {
+			t.Errorf("Ticks() = %v, want the hours from 00:00 to 06:00", got)
+		}
+	})
+
+	if _, err := (TimeScale{}).Ticks(0); err == nil {
+		t.Error("Ticks(0) expected an error, but got nil")
+	}
+}
@@ -0,0 +1,72 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayout resolves a layout name ("rfc3339", "rfc1123", "datetime", "date")
+// to a Go time layout. Any other string is taken to be a Go layout already,
+// such as "2006-01-02 15:04". An empty name means RFC 3339.
+func TimeLayout(name string) string {
+	switch strings.ToLower(name) {
+	case "", "rfc3339":
+		return time.RFC3339Nano
+	case "rfc1123":
+		return time.RFC1123
+	case "datetime":
+		return time.DateTime
+	case "date":
+		return time.DateOnly
+	default:
+		return name
+	}
+}
+
+// ParseTime parses a timestamp with the given Go layout and returns it as a
+// number of units since the Unix epoch (e.g. seconds when unit is time.Second).
+// Plain numbers are taken to be Unix timestamps in that unit already.
+// Timestamps without a time zone are interpreted as UTC.
+func ParseTime(s, layout string, unit time.Duration) (float64, error) {
+	if unit <= 0 {
+		return 0, fmt.Errorf("unit must be a positive duration")
+	}
+	s = strings.TrimSpace(s)
+	if val, err := strconv.ParseFloat(s, 64); err == nil {
+		return val, nil
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %q", s)
+	}
+	return UnixValue(t, unit), nil
+}
+
+// FormatTime renders a number of units since the Unix epoch as a UTC timestamp
+// with the given Go layout.
+func FormatTime(val float64, layout string, unit time.Duration) (string, error) {
+	t, err := UnixTime(val, unit)
+	if err != nil {
+		return "", fmt.Errorf("cannot render %g as a timestamp", val)
+	}
+	return t.Format(layout), nil
+}
+
+// UnixValue returns t as a number of units since the Unix epoch.
+func UnixValue(t time.Time, unit time.Duration) float64 {
+	// Split seconds and nanoseconds to keep sub-second precision.
+	return (float64(t.Unix()) + float64(t.Nanosecond())/1e9) * (float64(time.Second) / float64(unit))
+}
+
+// UnixTime returns the UTC time that is val units after the Unix epoch.
+func UnixTime(val float64, unit time.Duration) (time.Time, error) {
+	secs := val * (float64(unit) / float64(time.Second))
+	if math.IsNaN(secs) || math.IsInf(secs, 0) || math.Abs(secs) > 1e15 {
+		return time.Time{}, fmt.Errorf("%g is out of the range of timestamps", val)
+	}
+	whole, frac := math.Modf(secs)
+	return time.Unix(int64(whole), int64(math.Round(frac*1e9))).UTC(), nil
+}
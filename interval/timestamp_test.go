@@ -0,0 +1,67 @@
+package interval
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		input   string
+		layout  string
+		unit    time.Duration
+		want    float64
+		wantErr bool
+	}{
+		{"1970-01-01T00:01:00Z", TimeLayout("rfc3339"), time.Second, 60, false},
+		{"2023-10-15T09:00:00Z", TimeLayout(""), time.Second, 1697360400, false},
+		{"2023-10-15T11:00:00+02:00", TimeLayout(""), time.Second, 1697360400, false},
+		{"1970-01-01T00:00:01.5Z", TimeLayout(""), time.Millisecond, 1500, false},
+		{"2023-10-15 09:00:00", TimeLayout("datetime"), time.Second, 1697360400, false},
+		{"15/10/2023", "02/01/2006", time.Hour, 1697328000.0 / 3600, false},
+		{"1697360400", TimeLayout(""), time.Second, 1697360400, false},
+		{"yesterday", TimeLayout(""), time.Second, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseTime(tt.input, tt.layout, tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTime(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !almostEqual(got, tt.want) {
+				t.Errorf("ParseTime(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tests := []struct {
+		val    float64
+		layout string
+		unit   time.Duration
+		want   string
+	}{
+		{1697360400, TimeLayout(""), time.Second, "2023-10-15T09:00:00Z"},
+		{1697360400.25, TimeLayout(""), time.Second, "2023-10-15T09:00:00.25Z"},
+		{1500, TimeLayout(""), time.Millisecond, "1970-01-01T00:00:01.5Z"},
+		{1697360400, TimeLayout("datetime"), time.Second, "2023-10-15 09:00:00"},
+	}
+
+	for _, tt := range tests {
+		got, err := FormatTime(tt.val, tt.layout, tt.unit)
+		if err != nil {
+			t.Errorf("FormatTime(%v) returned an unexpected error: %v", tt.val, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FormatTime(%v) = %q, want %q", tt.val, got, tt.want)
+		}
+	}
+
+	if _, err := FormatTime(1e300, TimeLayout(""), time.Second); err == nil {
+		t.Error("FormatTime() expected an error for an out-of-range value, but got nil")
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// findJSONNumber locates the number at a dotted path (e.g. "a.b.0.c", where
+// numeric segments index arrays) within a JSON document, and returns its byte
+// offsets, so the document can be re-emitted with only that number replaced.
+func findJSONNumber(doc string, path string) (rec record, err error) {
+	dec := json.NewDecoder(strings.NewReader(doc))
+	dec.UseNumber()
+
+	segments := strings.Split(path, ".")
+	for _, seg := range segments {
+		tok, err := dec.Token()
+		if err != nil {
+			return record{}, fmt.Errorf("invalid JSON: %v", err)
+		}
+		switch tok {
+		case json.Delim('{'):
+			if err := seekKey(dec, seg); err != nil {
+				return record{}, err
+			}
+		case json.Delim('['):
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 {
+				return record{}, fmt.Errorf("key '%s' not found: value is an array", seg)
+			}
+			for i := 0; i < idx; i++ {
+				if !dec.More() {
+					return record{}, fmt.Errorf("index %d out of range", idx)
+				}
+				if err := skipValue(dec); err != nil {
+					return record{}, err
+				}
+			}
+			if !dec.More() {
+				return record{}, fmt.Errorf("index %d out of range", idx)
+			}
+		default:
+			return record{}, fmt.Errorf("key '%s' not found: value is not an object or array", seg)
+		}
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return record{}, fmt.Errorf("invalid JSON: %v", err)
+	}
+	num, ok := tok.(json.Number)
+	if !ok {
+		return record{}, fmt.Errorf("value at '%s' is not a number", path)
+	}
+	end := int(dec.InputOffset())
+	return record{line: doc, start: end - len(num), end: end}, nil
+}
+
+// seekKey advances the decoder, positioned inside an object, to the value of key.
+func seekKey(dec *json.Decoder, key string) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %v", err)
+		}
+		if tok == key {
+			return nil
+		}
+		if err := skipValue(dec); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("key '%s' not found", key)
+}
+
+// skipValue consumes the next complete value from the decoder.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %v", err)
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// jsonNumber checks that a formatted value is a valid JSON number, since formats
+// such as "%g" print NaN and infinities in ways JSON cannot represent.
+func jsonNumber(s string) (string, error) {
+	var f float64
+	if err := json.Unmarshal([]byte(s), &f); err != nil {
+		return "", fmt.Errorf("'%s' is not a valid JSON number", s)
+	}
+	return s, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestFindJSONNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"top level", `{"a":1.5,"b":2}`, "b", "2", false},
+		{"nested", `{"req":{"id":"x","latency":12e-3}}`, "req.latency", "12e-3", false},
+		{"array index", `{"a":[1,{"b":[3,4]}]}`, "a.1.b.1", "4", false},
+		{"skips nested values", `{"x":{"latency":1},"latency":[{"a":2}],"y":3}`, "y", "3", false},
+		{"spaces", `{ "a" : { "b" :  -7 } }`, "a.b", "-7", false},
+		{"missing key", `{"req":{}}`, "req.latency", "", true},
+		{"index out of range", `{"a":[1,2]}`, "a.2", "", true},
+		{"numeric key of an object", `{"a":{"0":1}}`, "a.0", "1", false},
+		{"key of an array", `{"a":[1]}`, "a.b", "", true},
+		{"string", `{"a":"12"}`, "a", "", true},
+		{"object", `{"a":{"b":1}}`, "a", "", true},
+		{"not an object", `12`, "a", "", true},
+		{"invalid JSON", `{"a":`, "a", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := findJSONNumber(tt.doc, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findJSONNumber(%q, %q) error = %v, wantErr %v", tt.doc, tt.path, err, tt.wantErr)
+			}
+			if !tt.wantErr && rec.value() != tt.want {
+				t.Errorf("findJSONNumber(%q, %q) = %q, want %q", tt.doc, tt.path, rec.value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONStream(t *testing.T) {
+	input := `{"req":{"latency":5,"path":"/a"}}
+{"req":{}}
+{"req":{"latency":"slow"}}
+{"req":{"latency": 10 },"n":[1,2]}
+`
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantCode int
+	}{
+		{"objects", nil, "{\"req\":{\"latency\":50,\"path\":\"/a\"}}\n{\"req\":{\"latency\": 100 },\"n\":[1,2]}\n", 0},
+		{"bare", []string{"--bare"}, "50\n100\n", 0},
+		{"strict stops at a missing key", []string{"--strict"}, "{\"req\":{\"latency\":50,\"path\":\"/a\"}}\n", exitParse},
+		{"strict bare", []string{"--strict", "--bare"}, "50\n", exitParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--json", "req.latency", "-r", "0", "10", "0", "100"}, tt.args...)
+			got, _, code := runSpan(t, input, args...)
+			if code != tt.wantCode || got != tt.want {
+				t.Errorf("span %q = %q, exit code %d, want %q, exit code %d", args, got, code, tt.want, tt.wantCode)
+			}
+		})
+	}
+
+	// A value that is not a number fails under --strict too.
+	args := []string{"--json", "req.latency", "--strict", "-l", "0", "1"}
+	if got, _, code := runSpan(t, "{\"req\":{\"latency\":0.5}}\n{\"req\":{\"latency\":\"slow\"}}\n", args...); code != exitParse || got != "{\"req\":{\"latency\":0.5}}\n" {
+		t.Errorf("span %q = %q, exit code %d, want the first object, exit code %d", args, got, code, exitParse)
+	}
+}
@@ -0,0 +1,53 @@
+//go:build cgo
+
+// Command libspan exports the interval functions of span as a C shared library,
+// so Python, Rust or C programs get the same handling of NaN, inverted intervals
+// and zero deltas as span. The functions are declared in span.h.
+//
+// Build it with: go build -buildmode=c-shared -o libspan.so ./libspan
+package main
+
+// #define SPAN_OK 0
+// #define SPAN_ERROR (-1)
+import "C"
+
+import "github.com/gregory-chatelier/span/interval"
+
+// result stores val in *out and returns SPAN_OK, or returns SPAN_ERROR on err.
+func result(val float64, err error, out *C.double) C.int {
+	if err != nil {
+		return C.SPAN_ERROR
+	}
+	*out = C.double(val)
+	return C.SPAN_OK
+}
+
+//export span_remap
+func span_remap(val, srcA, srcB, dstA, dstB C.double, out *C.double) C.int {
+	r, err := interval.Remap(float64(val), float64(srcA), float64(srcB), float64(dstA), float64(dstB))
+	return result(r, err, out)
+}
+
+//export span_eval
+func span_eval(t, a, b C.double) C.double {
+	return C.double(interval.Eval(float64(t), float64(a), float64(b)))
+}
+
+//export span_deval
+func span_deval(val, a, b C.double, out *C.double) C.int {
+	t, err := interval.Deval(float64(val), float64(a), float64(b))
+	return result(t, err, out)
+}
+
+//export span_limit
+func span_limit(val, min, max C.double) C.double {
+	return C.double(interval.Limit(float64(val), float64(min), float64(max)))
+}
+
+//export span_snap
+func span_snap(val C.double, steps C.int, a, b C.double, out *C.double) C.int {
+	r, err := interval.Snap(float64(val), int(steps), float64(a), float64(b))
+	return result(r, err, out)
+}
+
+func main() {}
@@ -0,0 +1,87 @@
+//go:build cgo
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// The tests cannot import "C" to name its types, so they take them from the
+// values of the exported functions.
+
+// cDouble converts val to the type of like, a C.double.
+func cDouble[T ~float64](like T, val float64) T {
+	return T(val)
+}
+
+// The status codes of span.h.
+const (
+	spanOK    = 0
+	spanError = -1
+)
+
+func TestStatusExports(t *testing.T) {
+	out := span_eval(0, 0, 1)
+	nan, inf := cDouble(out, math.NaN()), cDouble(out, math.Inf(1))
+	tests := []struct {
+		name string
+		call func() int
+		want float64
+	}{
+		{"remap", func() int { return int(span_remap(5, 0, 10, 0, 100, &out)) }, 50},
+		{"remap inverted", func() int { return int(span_remap(2, 10, 0, 0, 1, &out)) }, 0.8},
+		{"remap NaN", func() int { return int(span_remap(nan, 0, 10, 0, 100, &out)) }, math.NaN()},
+		{"remap infinite bound", func() int { return int(span_remap(5, 0, inf, 0, 100, &out)) }, math.NaN()},
+		{"remap zero delta", func() int { return int(span_remap(5, 3, 3, 0, 100, &out)) }, math.NaN()},
+		{"deval", func() int { return int(span_deval(15, 10, 20, &out)) }, 0.5},
+		{"deval zero delta, value on it", func() int { return int(span_deval(3, 3, 3, &out)) }, 0},
+		{"deval zero delta", func() int { return int(span_deval(4, 3, 3, &out)) }, math.NaN()},
+		{"deval NaN", func() int { return int(span_deval(nan, 0, 1, &out)) }, math.NaN()},
+		{"snap", func() int { return int(span_snap(0.3, 4, 0, 1, &out)) }, 0.25},
+		{"snap out of the interval", func() int { return int(span_snap(7, 4, 0, 1, &out)) }, 1},
+		{"snap no steps", func() int { return int(span_snap(0.3, 0, 0, 1, &out)) }, math.NaN()},
+		{"snap infinite", func() int { return int(span_snap(inf, 4, 0, 1, &out)) }, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const unchanged = -12345
+			out = unchanged
+			code := tt.call()
+			switch {
+			case math.IsNaN(tt.want):
+				if code != spanError || out != unchanged {
+					t.Errorf("status = %d, out = %v, want SPAN_ERROR with out unchanged", code, out)
+				}
+			case code != spanOK:
+				t.Errorf("status = %d, want SPAN_OK", code)
+			case math.Abs(float64(out)-tt.want) > 1e-12:
+				t.Errorf("out = %v, want %v", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestExports(t *testing.T) {
+	zero := span_eval(0, 0, 1)
+	nan, inf := cDouble(zero, math.NaN()), cDouble(zero, math.Inf(1))
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"eval", float64(span_eval(0.25, 10, 20)), 12.5},
+		{"eval ends", float64(span_eval(1, 0.1, 0.7)), 0.7},
+		{"eval NaN", float64(span_eval(nan, 0, 1)), math.NaN()},
+		{"limit", float64(span_limit(15, 0, 10)), 10},
+		{"limit inverted", float64(span_limit(-5, 10, 0)), 0},
+		{"limit +Inf", float64(span_limit(inf, 0, 10)), 10},
+		{"limit -Inf", float64(span_limit(-inf, 0, 10)), 0},
+		{"limit NaN", float64(span_limit(nan, 0, 10)), math.NaN()},
+	}
+	for _, tt := range tests {
+		if !(tt.got == tt.want || math.IsNaN(tt.got) && math.IsNaN(tt.want)) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * span.h - the interval functions of span, for C and FFI callers.
+ *
+ * Link against libspan, built with: make libspan
+ *
+ * The functions that can fail return SPAN_OK and store their result in *out,
+ * or return SPAN_ERROR and leave *out unchanged: on NaN or infinite input, or
+ * an interval with a zero delta the value cannot be placed in, or a number of
+ * steps that is not positive. The others return NaN for NaN input, as span does.
+ */
+#ifndef SPAN_H
+#define SPAN_H
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+#define SPAN_OK 0
+#define SPAN_ERROR (-1)
+
+/* Translates val from [src_a, src_b] to [dst_a, dst_b]. */
+int span_remap(double val, double src_a, double src_b, double dst_a, double dst_b, double *out);
+
+/* Evaluates the parameter t (0-1) within [a, b]. */
+double span_eval(double t, double a, double b);
+
+/* Returns the parameter t of val within [a, b]. */
+int span_deval(double val, double a, double b, double *out);
+
+/* Clamps val to [min, max], in either order. +Inf gives max and -Inf gives min. */
+double span_limit(double val, double min, double max);
+
+/* Snaps val to the nearest of steps + 1 points evenly spaced over [a, b]. */
+int span_snap(double val, int steps, double a, double b, double *out);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif /* SPAN_H */
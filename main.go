@@ -1,12 +1,17 @@
 package main
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/rand"
 	"os"
+	"runtime"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/gregory-chatelier/span/interval"
 	flag "github.com/spf13/pflag"
@@ -15,47 +20,464 @@ import (
 // Version will be set during the build process
 var Version = "v0.0.1-dev"
 
-// processFunc defines a function signature for processing a single float64 value.
-// It's used to pass different interval operations to the stream processor.
-type processFunc func(float64) (float64, error)
+// defaultGap is the character --gap-char draws missing values with.
+const defaultGap = "·"
 
-// processStream reads numbers from stdin, applies a processing function to each,
-// and prints the result to stdout.
-func processStream(format string, proc processFunc) {
-	outputFormat := format + "\n"
-	scanner := bufio.NewScanner(os.Stdin)
+// Exit codes, so that scripts can tell failures apart.
+const (
+	exitFailure = 1 // Other failures, such as I/O errors.
+	exitUsage   = 2 // Invalid flags or arguments.
+	exitParse   = 3 // Unparsable input in --strict mode.
+	exitEmpty   = 4 // No numbers in the input.
+	exitDomain  = 5 // Arguments or values outside the domain of the operation (e.g. a zero-delta source interval).
+)
+
+// exitCode returns the exit code for an error returned by an operation.
+func exitCode(err error) int {
+	if errors.Is(err, interval.ErrNoValues) {
+		return exitEmpty
+	}
+	return exitDomain
+}
+
+// encompassEvery prints the running min and max of the stream on stdin every n
+// values, or every time period when every is a duration, and once more at the end
+// of the stream. It lets --encompass be used on live streams that never end.
+func encompassEvery(every string, opts streamOptions) {
+	var r interval.RunningRange
+	printHeader(opts, "min", "max")
+	emitted := 0 // Value count at the last emission.
+	emit := func() {
+		if r.Count > 0 && r.Count != emitted {
+			printValues(opts, r.Min, r.Max)
+			flushOutput()
+			emitted = r.Count
+		}
+	}
+
+	if n, err := strconv.Atoi(every); err == nil {
+		if n <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --every requires a positive count or a duration")
+			exit(exitUsage)
+		}
+		forEachValue(opts, func(val float64) {
+			r.Add(val)
+			if r.Count%n == 0 {
+				emit()
+			}
+		})
+	} else {
+		period, err := time.ParseDuration(every)
+		if err != nil || period <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: could not parse --every value '%s' as a count or a duration\n", every)
+			exit(exitUsage)
+		}
+
+		// The stream is read in a goroutine, which hands the exit code of a
+		// failure back instead of exiting while values are printed.
+		values := make(chan float64)
+		failed := make(chan int, 1)
+		reader := opts
+		reader.onExit = func(code int) { failed <- code }
+		go func() {
+			defer close(values)
+			forEachValue(reader, func(val float64) { values <- val })
+		}()
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+	loop:
+		for {
+			select {
+			case val, ok := <-values:
+				if !ok {
+					break loop
+				}
+				r.Add(val)
+			case <-ticker.C:
+				emit()
+			}
+		}
+		select {
+		case code := <-failed:
+			exit(code)
+		default:
+		}
+	}
+
+	if r.Count == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no numbers found in input")
+		exit(exitEmpty)
+	}
+	emit()
+}
+
+// gaugeStream renders the last value of the stream on stdin as a gauge. When
+// stdout is a terminal, the gauge is also redrawn in place as values arrive, at
+// most once per refresh, so it shows the current value of a live stream.
+func gaugeStream(opts streamOptions, config interval.GaugeConfig, refresh time.Duration) {
+	stat, err := os.Stdout.Stat()
+	live := err == nil && stat.Mode()&os.ModeCharDevice != 0 && !config.ASCII
+
+	var last float64
+	count := 0
+	render := func() string {
+		gauge, err := interval.RenderGauge(last, config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitDomain)
+		}
+		return gauge
+	}
+	drawn := time.Time{}
+	forEachValue(opts, func(val float64) {
+		last = val
+		count++
+		if live && time.Since(drawn) >= refresh {
+			stdout.WriteString("\r" + render() + clearLine)
+			flushOutput()
+			drawn = time.Now()
+		}
+	})
+
+	if count == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no numbers found in input")
+		exit(exitEmpty)
+	}
+	if live {
+		stdout.WriteString("\r" + render() + clearLine + "\n")
+		return
+	}
+	printLine(opts, render())
+}
+
+// parseRow parses the fields of a line as numbers.
+func parseRow(opts streamOptions, fields []record) ([]float64, error) {
+	row := make([]float64, len(fields))
+	for i, f := range fields {
+		val, err := opts.parseValue(f.value())
+		if err != nil {
+			return nil, err
+		}
+		row[i] = val
+	}
+	return row, nil
+}
+
+// readVector reads a keyframe of --lerp-columns: the numbers of the first line
+// of the file at path that is not blank.
+func readVector(opts streamOptions, path string) ([]float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := opts.newScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			row, err := parseRow(opts, splitFields(line, opts.delimiter))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", path, err)
+			}
+			return row, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return nil, fmt.Errorf("%s: no numbers found", path)
+}
+
+// readProbabilities reads the probabilities of --icdf, one per line of the file
+// at path. Blank lines and lines starting with # are skipped.
+func readProbabilities(opts streamOptions, path string) ([]float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var probs []float64
+	scanner := opts.newScanner(file)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: invalid probability %q", path, lineNum, line)
+		}
+		probs = append(probs, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return probs, nil
+}
+
+// lerpColumns interpolates between two vectors by the t of each line of stdin,
+// in its column tCol (1-based, fields split as for --delimiter), and prints the
+// interpolated vectors. The vectors are from and to when given, and otherwise
+// the other columns of the line, the first half holding the start vector and
+// the second half the end one.
+func lerpColumns(opts streamOptions, tCol int, from, to []float64) {
+	scanner := opts.newScanner(os.Stdin)
 	for scanner.Scan() {
 		line := scanner.Text()
-		if line == "" {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := splitFields(line, opts.delimiter)
+		if len(fields) < tCol {
+			inputFailed(opts, "record", line, fmt.Errorf("no column %d", tCol))
+			continue
+		}
+		var t float64
+		var row []float64
+		var err error
+		if from != nil {
+			t, err = opts.parseValue(fields[tCol-1].value())
+		} else if row, err = parseRow(opts, fields); err == nil {
+			t = row[tCol-1]
+		}
+		if err != nil {
+			inputFailed(opts, "record", line, err)
 			continue
 		}
-		val, err := strconv.ParseFloat(line, 64)
+		summary.addInput(opts, t)
+		a, b := from, to
+		if from == nil {
+			rest := append(row[:tCol-1:tCol-1], row[tCol:]...)
+			a, b = rest[:len(rest)/2], rest[len(rest)/2:]
+		}
+		result, err := interval.EvalVector(t, a, b)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: could not parse input value '%s', skipping: %v\n", line, err)
+			processFailed(t, err)
 			continue
 		}
+		printValues(opts, result...)
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", scanError(err))
+		exit(exitFailure)
+	}
+}
+
+// bucketLabels splits a comma-separated list of bucket labels.
+func bucketLabels(spec string) []string {
+	labels := strings.Split(spec, ",")
+	for i, label := range labels {
+		labels[i] = strings.TrimSpace(label)
+	}
+	return labels
+}
+
+// renderBucket returns the renderer of the bucket indexes that --quantize and
+// --threshold yield: the label of the bucket, or its index without labels.
+// Anything but the index of one of the buckets is an error.
+func renderBucket(buckets int, labels []string) func(float64) (string, error) {
+	return func(i float64) (string, error) {
+		if i != math.Trunc(i) || i < 0 || i >= float64(buckets) {
+			return "", fmt.Errorf("%v is not a bucket index", i)
+		}
+		if labels == nil {
+			return strconv.Itoa(int(i)), nil
+		}
+		return labels[int(i)], nil
+	}
+}
+
+// unixTime returns the time that is val units after the Unix epoch, or exits
+// with a usage error if it is out of range.
+func unixTime(val float64, unit time.Duration) time.Time {
+	t, err := interval.UnixTime(val, unit)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		exit(exitUsage)
+	}
+	return t
+}
+
+// parseScale returns the scale of --scale from domain to rng, or exits with a
+// usage error.
+func parseScale(spec string, domain, rng [2]float64, clamp bool) interval.Scale {
+	scale, err := interval.ParseScale(spec, domain, rng, clamp)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: --scale:", err)
+		exit(exitUsage)
+	}
+	return scale
+}
 
-		processedVal, err := proc(val)
+// failsAs prefixes the errors of fn with the operation it implements, such as
+// "remap", since the errors of scales do not name it.
+func failsAs(operation string, fn processFunc) processFunc {
+	return func(val float64) (float64, error) {
+		res, err := fn(val)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: could not process value %f, skipping: %v\n", val, err)
+			return 0, fmt.Errorf("cannot %s: %w", operation, err)
+		}
+		return res, nil
+	}
+}
+
+// formatColor renders a color as --color-format asks: "#RRGGBB" for hex, or
+// "r g b" for rgb.
+func formatColor(c interval.RGB, format string) string {
+	if format == "rgb" {
+		return fmt.Sprintf("%d %d %d", c.R, c.G, c.B)
+	}
+	return c.Hex()
+}
+
+// newRand returns the random generator used by stochastic operations. It is seeded
+// from --seed when that flag is given, so output can be reproduced, and from the
+// clock otherwise.
+func newRand(seed int64) *rand.Rand {
+	if !flag.CommandLine.Changed("seed") {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// colorEnabled reports whether ANSI colors may be written for the --color mode.
+// In auto mode, colors are written only to a terminal and only when the
+// NO_COLOR environment variable is unset or empty (https://no-color.org).
+func colorEnabled(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		stat, err := os.Stdout.Stat()
+		return err == nil && stat.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("unknown color mode: %s (expected always, never or auto)", mode)
+	}
+}
+
+// expandIntervals replaces the arguments written in interval notation, such as
+// "[0, 10)", with their two bounds, so every operation taking <a> <b> accepts
+// it. Unquoted, the notation spans two arguments, split after the comma. The
+// parsed intervals are also returned, for the operations that honor open bounds.
+func expandIntervals(args []string) ([]string, []interval.Interval) {
+	var expanded []string
+	var intervals []interval.Interval
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "[") && !strings.HasPrefix(arg, "(") {
+			expanded = append(expanded, arg)
 			continue
 		}
-		fmt.Printf(outputFormat, processedVal)
+		for !strings.HasSuffix(arg, "]") && !strings.HasSuffix(arg, ")") && i+1 < len(args) {
+			i++
+			arg += " " + args[i]
+		}
+		iv, err := interval.Parse(arg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		expanded = append(expanded, strconv.FormatFloat(iv.Lo, 'g', -1, 64), strconv.FormatFloat(iv.Hi, 'g', -1, 64))
+		intervals = append(intervals, iv)
 	}
+	return expanded, intervals
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", err)
-		os.Exit(1)
+// writeImage renders the sparkline read from stdin as an image in format (svg
+// or png), written to path, or to stdout if path is empty.
+func writeImage(opts streamOptions, spark interval.SparkConfig, format, color, size, path string) {
+	config := interval.ImageConfig{Min: spark.Min, Max: spark.Max, HasRange: spark.HasMin}
+	width, height, ok := strings.Cut(size, "x")
+	var errW, errH error
+	config.Width, errW = strconv.Atoi(width)
+	config.Height, errH = strconv.Atoi(height)
+	if !ok || errW != nil || errH != nil || config.Width <= 0 || config.Height <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: could not parse size '%s' (expected <width>x<height> in pixels)\n", size)
+		exit(exitUsage)
+	}
+	if color != "" {
+		var err error
+		if config.Color, err = interval.ParseRGB(color); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+	}
+
+	var out io.Writer = stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitFailure)
+		}
+		defer f.Close()
+		out = f
+	}
+	generate := interval.GenerateSVG
+	if format == "png" {
+		generate = interval.GeneratePNG
+	}
+	if err := generate(opts.newScanner(os.Stdin), out, config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating %s: %v\n", format, scanError(err))
+		exit(exitFailure)
+	}
+}
+
+// columns returns the width of the terminal stdout is attached to, falling back
+// to the COLUMNS environment variable, or 0 if neither is known.
+func columns() int {
+	if width := terminalWidth(os.Stdout); width > 0 {
+		return width
+	}
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width < 0 {
+		return 0
+	}
+	return width
+}
+
+// detachedJoinSep returns the separator given apart from a bare --join, as in
+// "--join ,", which would be read as --join with its default separator and a
+// stray argument. Only an argument made of punctuation, symbols or spaces is
+// taken for a separator, as no operation takes one.
+func detachedJoinSep(args []string) (string, bool) {
+	for i := 0; i+1 < len(args) && args[i] != "--"; i++ {
+		next := args[i+1]
+		if args[i] != "--join" || (len(next) > 1 && strings.HasPrefix(next, "-")) {
+			continue
+		}
+		separator := true
+		for _, r := range next {
+			if !unicode.IsPunct(r) && !unicode.IsSymbol(r) && !unicode.IsSpace(r) {
+				separator = false
+				break
+			}
+		}
+		if separator {
+			return next, true
+		}
 	}
+	return "", false
 }
 
 func usage() {
+	if command != "" {
+		commandUsage()
+		return
+	}
 	fmt.Fprintf(os.Stderr, `NAME:
     span - A Unix-style tool for interval manipulation.
 
 SYNOPSIS:
     span [operation] [flags] [arguments...]
     command | span [operation] [flags] [arguments...]
+    span help [operation]
+    span completion <bash|zsh|fish>
+    span bench [benchmark...]
 
 DESCRIPTION:
     span reads numbers from stdin, performs an interval-based mathematical
@@ -72,277 +494,2211 @@ func main() {
 
 	// --- Global Flags ---
 	format := flag.StringP("format", "f", "%g", "Specifies the printf format for floating-point output (e.g., \"%.3f\").")
+	precision := flag.Int("precision", 0, "Prints floating-point output with <n> decimals (shorthand for -f %.<n>f)")
+	intFlag := flag.Bool("int", false, "Prints floating-point output rounded to integers (shorthand for -f %.0f)")
+	human := flag.String("human", "", "Prints output with SI suffixes (12.3k, 4.5M), or binary suffixes with --human=binary")
+	flag.Lookup("human").NoOptDefVal = "si"
 	versionFlag := flag.Bool("version", false, "Prints version information and exits.")
+	colorMode := flag.String("color", "auto", "Emits ANSI colors: always, never, or auto (only to a terminal, and not when NO_COLOR is set)")
+	flag.String("preset", "", "Runs the flags and arguments defined as the preset <name> in ~/.config/span/presets.toml")
+	seed := flag.Int64("seed", 0, "Seeds the random generator for reproducible output (default: seeded from the clock).")
 
 	// --- Operation Flags ---
 	remapFlag := flag.BoolP("remap", "r", false, "Remaps a value from a source interval to a target interval.")
+	remapDynamicFlag := flag.Bool("remap-dynamic", false, "Reads \"value src_a src_b\" lines and remaps each value from its own source interval to [dst_a, dst_b].")
+	remap2Flag := flag.Bool("remap2", false, "Reads \"x y\" points and remaps x from [sx0, sx1] to [dx0, dx1] and y from [sy0, sy1] to [dy0, dy1].")
+	clampAxes := flag.String("clamp-axes", "", "For --remap2: clamps the remapped x, y or both (x, y, xy) to their target interval")
+	geoTileFlag := flag.Bool("geo-tile", false, "Reads \"lat lon\" pairs and outputs the Web Mercator tile holding each one at <zoom>.")
+	geoPixel := flag.Bool("geo-pixel", false, "For --geo-tile: outputs (or, with --geo-inverse, reads) global pixel coordinates instead of tiles")
+	geoInverse := flag.Bool("geo-inverse", false, "For --geo-tile: reads \"x y\" tile (or pixel) coordinates and outputs their lat lon")
 	limitFlag := flag.BoolP("limit", "l", false, "Restricts (clamps) a value to a given interval.")
 	encompassFlag := flag.BoolP("encompass", "E", false, "Reads a stream and outputs the min and max values.")
+	statsFlag := flag.Bool("stats", false, "Reads a stream and outputs descriptive statistics.")
+	percentileFlag := flag.Bool("percentile", false, "Estimates the <p>-th percentile (0-100) of a stream in constant memory.")
+	cdfFlag := flag.Bool("cdf", false, "Reads a stream and outputs the empirical cumulative probability (0-1) of each of its values.")
+	icdfFlag := flag.Bool("icdf", false, "Reads a stream and outputs its empirical quantile at each probability (0-1) of <p-file>.")
+	histFlag := flag.Bool("hist", false, "Reads a stream and counts its values into <bins> equal bins over its range or [a, b].")
+	outliersMode := flag.String("outliers", "", "Reads a stream and drops, keeps only, or marks outliers (drop, keep, mark).")
+	downsampleFlag := flag.Bool("downsample", false, "Reads a stream and reduces it to <n> values.")
 	divideFlag := flag.BoolP("divide", "n", false, "Generates a sequence by dividing an interval.")
+	divideEaseFlag := flag.Bool("divide-ease", false, "Generates a sequence by dividing an interval with eased spacing.")
+	niceFlag := flag.Bool("nice", false, "Expands [a b] or the \"start end\" pairs read from stdin outward to round bounds, e.g. the range from --encompass before charting.")
+	ticksFlag := flag.Bool("ticks", false, "Generates about <count> nice tick values (1, 2 or 5 times a power of ten) covering an interval.")
 	evalFlag := flag.BoolP("eval", "e", false, "Evaluates a parameter 't' (0-1) within an interval.")
+	lerpColumnsFlag := flag.Bool("lerp-columns", false, "Interpolates every component between two vectors by the t in column <t-column>, the vectors being the other columns or the rows of <from> and <to>.")
 	devalFlag := flag.BoolP("deval", "d", false, "De-evaluates a number to a parameter 't' (0-1).")
 	randomFlag := flag.BoolP("random", "R", false, "Generates <count> random numbers in an interval.")
+	randomIntFlag := flag.Bool("random-int", false, "Generates <count> random integers in an interval (bounds included).")
+	randomNormalFlag := flag.Bool("random-normal", false, "Generates <count> normally distributed random numbers, optionally truncated to an interval.")
+	randomWeightedFlag := flag.Bool("random-weighted", false, "Generates <count> random numbers following the weights listed in <file>.")
+	jitterFlag := flag.Bool("jitter", false, "Generates one random number in each of <steps> equal subintervals.")
+	quasiFlag := flag.Bool("quasi", false, "Generates <count> quasi-random (Halton) numbers in an interval.")
+	smoothFlag := flag.Bool("smooth", false, "Smooths a stream with a moving average over <window> values.")
+	emaFlag := flag.Bool("ema", false, "Smooths a stream with an exponential moving average of factor <alpha>.")
+	rollingNormalizeFlag := flag.Bool("rolling-normalize", false, "Remaps values from the min/max of the last <window> values to [0, 1] or [a, b].")
+	diffFlag := flag.Bool("diff", false, "Outputs the differences between consecutive values of a stream.")
+	fillSpec := flag.String("fill", "", "Fills gaps (blank lines, nan) in a stream (linear, previous, value:<x>).")
+	exprSpec := flag.String("expr", "", "Applies a pipeline of functions to each value (e.g. \"clamp(0,100) | remap(0,100,0,1) | ease(outCubic)\").")
+	mapCmd := flag.String("map-cmd", "", "Transforms each value with an external filter, run with sh -c on chunks of values, one number per line in and out.")
+	serveAddr := flag.String("serve", "", "Serves a WebSocket endpoint at /ws on <addr> that answers messages of numbers with transformed values or sparkline frames.")
 	snapFlag := flag.BoolP("snap", "S", false, "Snaps input values to the nearest point on a grid.")
+	intersectFlag := flag.Bool("intersect", false, "Reads \"start end\" pairs and outputs their intersection with [a, b], dropping those outside of it.")
+	clipFlag := flag.Bool("clip", false, "Reads \"start end\" pairs and restricts each one to [a, b], dropping those left empty.")
+	splitSpec := flag.String("split", "", "Reads \"start end\" pairs and cuts each one at the points of the comma-separated list <p1,p2,...>.")
+	hullFlag := flag.Bool("hull", false, "Reads \"start end\" pairs and outputs the smallest interval holding both the pair and [a, b].")
+	gapsFlag := flag.Bool("gaps", false, "Reads \"start end\" pairs and outputs the gaps between them, or the parts of [a, b] they leave uncovered.")
+	coverageFlag := flag.Bool("coverage", false, "Reads \"start end\" pairs and outputs the length they cover and its fraction of their hull or [a, b].")
+	relateFlag := flag.Bool("relate", false, "Reads \"start end\" pairs and outputs the Allen relation of each one to [a, b] (before, meets, overlaps, during...).")
+	mergeFlag := flag.Bool("merge", false, "Reads \"start end\" pairs and outputs the sorted, non-overlapping intervals covering them.")
 	subintervalsFlag := flag.BoolP("subintervals", "s", false, "Divides an interval into <steps> equal subintervals.")
+	goldenFlag := flag.Bool("golden", false, "Splits an interval into <n> segments by the golden ratio.")
+	fibonacciFlag := flag.Bool("fibonacci", false, "Splits an interval into <n> Fibonacci-proportioned segments.")
 	sparkFlag := flag.Bool("spark", false, "Generates a sparkline visualization from a stream of numbers.")
+	heatFlag := flag.Bool("heat", false, "Renders a stream as a row of colored cells, a 1-D heatmap.")
+	quantizeFlag := flag.Bool("quantize", false, "Maps values to <n> equal buckets of [a, b], printing the index of each one's bucket or its label in <out1,out2,...>.")
+	thresholdFlag := flag.Bool("threshold", false, "Maps values to the buckets cut by the break points <t1,t2,...>, printing the index of each one's bucket or its label in <out0,out1,...>.")
+	colorizeFlag := flag.Bool("colorize", false, "Maps input values in [a, b] to colors along a palette.")
+	lerpColorFlag := flag.Bool("lerp-color", false, "Maps t values (0-1) to colors interpolated from <from> to <to>.")
+	dashboardFlag := flag.Bool("dashboard", false, "Redraws the columns of a stream as labeled sparklines with statistics, a terminal monitor.")
+	boxplotFlag := flag.Bool("boxplot", false, "Renders the quartiles, whiskers and outliers of a stream as a one-line box plot.")
+	gaugeFlag := flag.Bool("gauge", false, "Renders the latest value of a stream as a gauge over [a, b], with its percentage.")
 
 	// --- Spark-specific Flags ---
-	sparkWidth := flag.Int("spark-width", 0, "For --spark: fixed-width sliding window animation")
+	sparkWidth := flag.String("spark-width", "", "For --spark: fixed-width sliding window animation, or auto to fit the terminal")
 	sparkColor := flag.String("spark-color", "", "For --spark: sparkline color (red, green, blue, etc.)")
+	colorGradient := flag.String("color-gradient", "", "For --spark: colors each character by its value along <from>..<to> (e.g. green..red)")
+	ascii := flag.Bool("ascii", false, "For --spark and --gauge: draws with plain ASCII characters (_.-=#) and without in-place animation")
+	sparkChars := flag.String("chars", "", "For --spark: ramp of characters to draw with, from the lowest level to the highest (e.g. \" .:-=+*#%@\")")
+	gapChar := flag.String("gap-char", "", "For --spark: draws blank lines and nan values as <char> (default ·, or a space with --ascii) instead of skipping them")
+	flag.Lookup("gap-char").NoOptDefVal = defaultGap
+	series := flag.Bool("series", false, "For --spark: draws one sparkline per column of the input, stacked")
+	sharedScale := flag.Bool("shared-scale", false, "For --spark --series: scales every sparkline to the range of all columns")
+	sparkLabels := flag.Bool("labels", false, "For --spark: prints the min, max and last value around the sparkline; for --boxplot: the min and max")
+	label := flag.String("label", "", "For --spark, --heat, --gauge, --boxplot and --hist --chart: prints <text> before the output")
+	sparkLog := flag.Bool("log", false, "For --spark: scales values through log10, skipping values that are not positive")
+	baseline := flag.Float64("baseline", 0, "For --spark: draws values above <value> upward and values below it downward")
+	follow := flag.Bool("follow", false, "For --spark --spark-width: redraws the window on a timer while reading, so it stays current when the input stalls")
+	refresh := flag.Duration("interval", interval.DefaultRefresh, "For --spark --spark-width, --gauge and --dashboard: minimum time between two redraws")
+	sparkHeight := flag.Int("height", 1, "For --spark: number of terminal rows to draw the sparkline across")
+	sparkStyle := flag.String("style", "block", "For --spark: characters to draw with (block, braille for 2 values per character, or winloss for the sign of each value)")
 
-	flag.Parse()
+	// --- Hist-specific Flags ---
+	chart := flag.Bool("chart", false, "For --hist: renders the bin counts as a bar chart")
+	chartWidth := flag.Int("chart-width", 40, "For --hist --chart: width of the longest bar, in characters; for --gauge: width of the bar; for --boxplot: width of the plot (default: the terminal width)")
+
+	// --- Gauge-specific Flags ---
+	warn := flag.Float64("warn", 0, "For --gauge: draws the bar in yellow from <value> (below it when --crit is lower)")
+	crit := flag.Float64("crit", 0, "For --gauge: draws the bar in red from <value> (below it when lower than --warn)")
+
+	// --- Colorize-specific Flags ---
+	palette := flag.String("palette", "viridis", "For --colorize and --heat: palette (viridis, heat, or rgb:<color>,<color>... with #RRGGBB or color names)")
+	colorFormat := flag.String("color-format", "hex", "For --colorize and --lerp-color: output format (hex for #RRGGBB, rgb for \"r g b\" triplets)")
+	colorSpace := flag.String("color-space", "srgb", "For --lerp-color: space the colors are interpolated in (srgb, hsl, oklab)")
+
+	// --- Random-specific Flags ---
+	dist := flag.String("dist", "uniform", "For --random: distribution (uniform, exponential, lognormal, triangular, beta), with optional parameters as name:p1,p2")
+
+	// --- Quasi-specific Flags ---
+	base := flag.Int("base", 2, "For --quasi: base of the Halton sequence")
+
+	// --- Encompass-specific Flags ---
+	every := flag.String("every", "", "For --encompass: emit the running min and max every <n> values or every <duration> (e.g. 5s)")
+
+	// --- Outliers-specific Flags ---
+	outlierK := flag.Float64("k", 1.5, "For --outliers and --boxplot: fence multiplier (IQRs for iqr, standard deviations for zscore; zscore defaults to 3)")
+	outlierMethod := flag.String("outlier-method", "iqr", "For --outliers: detection method (iqr, zscore)")
+
+	// --- Downsample-specific Flags ---
+	downsampleMethod := flag.String("method", "lttb", "For --downsample: method (lttb, uniform)")
+
+	// --- Diff-specific Flags ---
+	diffFirst := flag.String("diff-first", "drop", "For --diff: what to output for the first value (drop, zero)")
+
+	// --- Map-cmd-specific Flags ---
+	chunk := flag.Int("chunk", 1024, "For --map-cmd: number of values handed to each run of the command")
+
+	// --- Remap, Eval and Deval Flags ---
+	scaleSpec := flag.String("scale", "linear", "For -r, -e and -d: scale values are mapped through (linear, log[:<base>], pow:<exponent>, sqrt, symlog[:<constant>])")
+	clampFlag := flag.Bool("clamp", false, "For -r, -e and -d: clamps the results to the target interval")
+
+	// --- Subintervals-specific Flags ---
+	overlap := flag.Float64("overlap", 0, "For --subintervals: fraction (0-1) by which consecutive subintervals overlap")
+
+	// --- Input Flags ---
+	field := flag.Int("field", 0, "Applies the operation to the <n>th field of each line (1-based) and re-emits the whole line")
+	delimiter := flag.String("delimiter", "", "For --field and --all-fields: field delimiter (default: runs of whitespace)")
+	withInput := flag.Bool("with-input", false, "Prints each input line before its output, separated by a tab (paste mode)")
+	allFields := flag.Bool("all-fields", false, "Applies the operation to every number of each line and re-emits the whole line")
+
+	csvFlag := flag.Bool("csv", false, "Reads and writes the stream as CSV")
+	header := flag.Bool("header", false, "For --csv: the first record is a header row, passed through unchanged")
+	column := flag.String("column", "", "For --csv: column holding the values, by header name or 1-based index (default 1)")
+
+	jsonKey := flag.String("json", "", "Reads NDJSON and applies the operation to the number at this dotted key path (e.g. \"req.latency\")")
+	bare := flag.Bool("bare", false, "For --json: outputs bare numbers instead of the updated JSON objects")
+
+	as := flag.String("as", "", "Parses input values as another kind of quantity (duration, time)")
+	unit := flag.String("unit", "s", "For --as: unit of the numbers the operation works on (ns, us, ms, s, m, h)")
+	layout := flag.String("layout", "rfc3339", "For --as time: timestamp layout (rfc3339, rfc1123, datetime, date, or a Go layout)")
+	asOutput := flag.Bool("as-output", false, "For --as: renders output values back as that kind of quantity")
+
+	maxLineSize := flag.String("max-line-size", "16Mi", "Longest input line accepted, in bytes (SI and binary suffixes allowed, e.g. 64Mi)")
+
+	nanPolicy := flag.String("nan", "", "How NaN and infinite inputs are treated: skip, zero, clamp, propagate or error (default: per operation)")
+
+	epsilon := flag.Float64("epsilon", interval.ZeroTolerance.Epsilon, "Widths below <value> count as zero when de-evaluating values in an interval (--deval, --remap, --spark...); 0 only rejects empty intervals")
+	relativeEpsilon := flag.Bool("relative-epsilon", false, "For --epsilon: the tolerance is relative to the magnitude of the interval's bounds")
+
+	recordDelim := flag.String("record-delim", "", "Splits the input into records on this character, or on NUL bytes with \"nul\" (default: newlines)")
+
+	output := flag.String("output", "", "Writes results as a table: csv or tsv (one record per output line), --stats, --hist and -E as Prometheus metrics with prom, or --spark as an svg or png image")
+	metric := flag.String("metric", "", "For --output prom and --emit statsd: name of the metric (e.g. request_latency_seconds)")
+	emit := flag.String("emit", "", "Also sends each output value over UDP to statsd:<host:port>, as a gauge named by --metric, or to osc:<host:port>, as an OSC message to --address")
+	oscAddr := flag.String("address", "/span/value", "For --emit osc: OSC address the values are sent to")
+	outputFile := flag.String("file", "", "For --output svg|png: writes the image to <path> instead of stdout")
+	imageSize := flag.String("size", "100x20", "For --output svg|png: size of the image in pixels, as <width>x<height>")
+	outputHeader := flag.Bool("output-header", false, "For --output: prints a header row for multi-value results (-E, --stats, -s, --golden, --fibonacci)")
+
+	join := flag.String("join", " ", "Prints all results on one line, separated by <sep> (use --join=<sep>)")
+	flag.Lookup("join").NoOptDefVal = " "
+
+	strict := flag.Bool("strict", false, "Exits with an error (code 3) on unparsable input instead of skipping it with a warning")
+	summaryFlag := flag.Bool("summary", false, "Prints a report to stderr at the end: values read, skipped, clamped and errored, and the input and output ranges")
+	rate := flag.String("rate", "", "Paces the output to <n> records per second, minute or hour (e.g. 10/s, 30/m), to replay a dataset in real time")
+	delay := flag.Duration("delay", 0, "Paces the output with <duration> between two records (e.g. 100ms)")
+	parallel := flag.Int("parallel", 0, "Processes lines with <n> workers, in input order, for --remap, --limit, --eval, --deval, --snap and --expr (use --parallel=<n>; default: one per CPU)")
+	flag.Lookup("parallel").NoOptDefVal = "0"
+	flushEvery := flag.Int("flush-every", 0, "Flushes the output every <n> records, for live pipelines (default: every record on a terminal, else when the buffer is full)")
+
+	binaryInSpec := flag.String("binary-in", "", "Reads packed binary floats instead of text lines (f32 or f64, optionally :le or :be, or u8 for bytes)")
+	binaryOutSpec := flag.String("binary-out", "", "Writes packed binary floats instead of text lines (f32 or f64, optionally :le or :be, or u8 for bytes)")
+	bytesMode := flag.String("bytes", "", "Clamps and rounds output values into 0-255, as pixel or PWM values, and writes them as raw bytes, or as decimal numbers with --bytes=dec")
+	flag.Lookup("bytes").NoOptDefVal = "raw"
+
+	cmdArgs := parseCommand(expandPresets(os.Args[1:]))
+	flag.CommandLine.Parse(cmdArgs)
+	if command != "" {
+		checkScope()
+	}
+
+	if *field < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --field must be a positive field number.")
+		exit(exitUsage)
+	}
+	if *allFields && (*field > 0 || *csvFlag || *jsonKey != "") {
+		fmt.Fprintln(os.Stderr, "Error: --all-fields cannot be combined with --field, --csv or --json.")
+		exit(exitUsage)
+	}
+	if flag.CommandLine.Changed("precision") || *intFlag {
+		if flag.CommandLine.Changed("format") || flag.CommandLine.Changed("precision") && *intFlag {
+			fmt.Fprintln(os.Stderr, "Error: only one of -f, --format, --precision and --int can be used.")
+			exit(exitUsage)
+		}
+		if *precision < 0 {
+			fmt.Fprintln(os.Stderr, "Error: --precision cannot be negative.")
+			exit(exitUsage)
+		}
+		*format = fmt.Sprintf("%%.%df", *precision)
+	}
+	if err := interval.CheckFormat(*format); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		exit(exitUsage)
+	}
+	opts := streamOptions{
+		format:    *format,
+		field:     *field,
+		delimiter: *delimiter,
+		allFields: *allFields,
+		withInput: *withInput,
+		csv:       *csvFlag,
+		header:    *header,
+		column:    *column,
+		jsonKey:   *jsonKey,
+		bare:      *bare,
+	}
+	lineSize, err := interval.ParseHuman(*maxLineSize)
+	if err != nil || lineSize < 1 || lineSize > math.MaxInt32 {
+		fmt.Fprintf(os.Stderr, "Error: invalid --max-line-size '%s'\n", *maxLineSize)
+		exit(exitUsage)
+	}
+	opts.maxLineSize = int(lineSize)
+	opts.nanLo, opts.nanHi = -math.MaxFloat64, math.MaxFloat64
+	if *nanPolicy != "" {
+		policy, err := interval.ParseNaNPolicy(*nanPolicy)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --nan:", err)
+			exit(exitUsage)
+		}
+		opts.nan = policy
+	}
+	if math.IsNaN(*epsilon) || math.IsInf(*epsilon, 0) || *epsilon < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --epsilon must be a non-negative number")
+		exit(exitUsage)
+	}
+	interval.ZeroTolerance = interval.Tolerance{Epsilon: *epsilon, Relative: *relativeEpsilon}
+	color, err := colorEnabled(*colorMode)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: --color:", err)
+		exit(exitUsage)
+	}
+	if *recordDelim != "" {
+		split, err := parseRecordDelim(*recordDelim)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --record-delim:", err)
+			exit(exitUsage)
+		}
+		opts.split = split
+	}
+	switch *output {
+	case "", "csv", "tsv":
+		opts.output = *output
+		opts.outputHeader = *outputHeader
+	case "svg", "png": // Handled by --spark.
+	case "prom": // Handled by --stats, --hist and --encompass.
+		if !promMetricName.MatchString(*metric) {
+			fmt.Fprintf(os.Stderr, "Error: --output prom requires a valid --metric name (got '%s')\n", *metric)
+			exit(exitUsage)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --output format '%s' (expected csv, tsv, prom, svg or png)\n", *output)
+		exit(exitUsage)
+	}
+	opts.flushEvery = *flushEvery
+	opts.summary = *summaryFlag
+	opts.strict = *strict
+	if *rate != "" && flag.CommandLine.Changed("delay") {
+		fmt.Fprintln(os.Stderr, "Error: only one of --rate and --delay can be used.")
+		exit(exitUsage)
+	}
+	if *rate != "" {
+		if opts.pace, err = parseRate(*rate); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --rate:", err)
+			exit(exitUsage)
+		}
+	}
+	if flag.CommandLine.Changed("delay") {
+		if *delay <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --delay must be positive")
+			exit(exitUsage)
+		}
+		opts.pace = *delay
+	}
+	if stat, err := os.Stdout.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 && !flag.CommandLine.Changed("flush-every") {
+		opts.flushEvery = 1
+	}
+	if opts.flushEvery < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --flush-every cannot be negative.")
+		exit(exitUsage)
+	}
+	opts.join = flag.CommandLine.Changed("join")
+	opts.joinSep = *join
+	if sep, ok := detachedJoinSep(cmdArgs); ok {
+		fmt.Fprintf(os.Stderr, "Error: the separator of --join must be attached with =, as in --join=%q\n", sep)
+		exit(exitUsage)
+	}
+	if *binaryInSpec != "" {
+		f, err := parseBinaryFormat(*binaryInSpec)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --binary-in:", err)
+			exit(exitUsage)
+		}
+		opts.binaryIn = f
+	}
+	if *binaryOutSpec != "" {
+		f, err := parseBinaryFormat(*binaryOutSpec)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --binary-out:", err)
+			exit(exitUsage)
+		}
+		opts.binaryOut = f
+	}
+	switch *bytesMode {
+	case "":
+	case "raw":
+		if opts.binaryOut != nil {
+			fmt.Fprintln(os.Stderr, "Error: only one of --bytes and --binary-out can be used.")
+			exit(exitUsage)
+		}
+		opts.binaryOut, _ = parseBinaryFormat("u8")
+	case "dec": // Applied with the other renderers, below.
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --bytes mode '%s' (expected raw or dec)\n", *bytesMode)
+		exit(exitUsage)
+	}
+	if *emit != "" {
+		e, err := parseEmit(*emit, *metric, *oscAddr)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --emit:", err)
+			exit(exitUsage)
+		}
+		opts.emit = e
+	}
+	var timeUnit time.Duration // The unit of --as time, 0 without it.
+	if *as != "" {
+		quantityUnit, err := interval.ParseUnit(*unit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		timeLayout := interval.TimeLayout(*layout)
+		switch *as {
+		case "duration":
+			opts.parse = func(s string) (float64, error) {
+				return interval.ParseDuration(s, quantityUnit)
+			}
+			if *asOutput {
+				opts.render = func(val float64) (string, error) {
+					return interval.FormatDuration(val, quantityUnit)
+				}
+			}
+		case "time":
+			timeUnit = quantityUnit
+			opts.parse = func(s string) (float64, error) {
+				return interval.ParseTime(s, timeLayout, quantityUnit)
+			}
+			if *asOutput {
+				opts.render = func(val float64) (string, error) {
+					return interval.FormatTime(val, timeLayout, quantityUnit)
+				}
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown --as kind '%s' (expected duration or time)\n", *as)
+			exit(exitUsage)
+		}
+	}
+
+	if *human != "" {
+		if opts.render != nil {
+			fmt.Fprintln(os.Stderr, "Error: --human cannot be combined with --as-output.")
+			exit(exitUsage)
+		}
+		if *human != "si" && *human != "binary" {
+			fmt.Fprintf(os.Stderr, "Error: unknown --human suffixes '%s' (expected si or binary)\n", *human)
+			exit(exitUsage)
+		}
+		binarySuffixes := *human == "binary"
+		opts.render = func(val float64) (string, error) {
+			return interval.FormatHuman(val, *format, binarySuffixes), nil
+		}
+	}
+	if *bytesMode == "dec" {
+		if opts.render != nil {
+			fmt.Fprintln(os.Stderr, "Error: --bytes=dec cannot be combined with --human or --as-output.")
+			exit(exitUsage)
+		}
+		opts.render = func(val float64) (string, error) {
+			return strconv.Itoa(int(toByte(val))), nil
+		}
+	}
 
 	if *versionFlag {
 		fmt.Println(Version)
-		os.Exit(0)
+		exit(0)
 	}
 
 	opCount := 0
 	flag.Visit(func(f *flag.Flag) {
-		switch f.Name {
-		case "remap", "limit", "encompass", "divide", "eval", "deval", "random", "snap", "subintervals", "spark":
+		if isOperation(f.Name) {
 			opCount++
 		}
 	})
 
-	args := flag.Args()
+	args, notation := expandIntervals(flag.Args())
 
 	if opCount > 1 {
 		fmt.Fprintln(os.Stderr, "Error: Only one operational flag can be used at a time.")
 		usage()
-		os.Exit(1)
+		exit(exitUsage)
 	}
 
 	if opCount == 0 {
 		stat, _ := os.Stdin.Stat()
 		if len(args) == 0 && (stat.Mode()&os.ModeCharDevice) != 0 {
 			usage()
-			os.Exit(0)
+			exit(0)
 		}
 		if (stat.Mode() & os.ModeNamedPipe) != 0 {
 			*sparkFlag = true
 		} else {
 			fmt.Fprintln(os.Stderr, "Error: An operational flag is required.")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
 	}
 
+	if flag.CommandLine.Changed("parallel") {
+		if !*remapFlag && !*limitFlag && !*evalFlag && !*devalFlag && !*snapFlag && *exprSpec == "" {
+			fmt.Fprintln(os.Stderr, "Error: --parallel only applies to --remap, --limit, --eval, --deval, --snap and --expr.")
+			exit(exitUsage)
+		}
+		if *csvFlag || *allFields || *binaryInSpec != "" {
+			fmt.Fprintln(os.Stderr, "Error: --parallel cannot be combined with --csv, --all-fields or --binary-in.")
+			exit(exitUsage)
+		}
+		if *parallel < 0 {
+			fmt.Fprintln(os.Stderr, "Error: --parallel cannot be negative.")
+			exit(exitUsage)
+		}
+		opts.parallel = *parallel
+		if opts.parallel == 0 {
+			opts.parallel = runtime.NumCPU()
+		}
+	}
+
+	promOutput := *output == "prom"
+	if promOutput && !(*statsFlag || *histFlag && !*chart || *encompassFlag && *every == "") {
+		fmt.Fprintln(os.Stderr, "Error: --output prom only applies to --stats, --hist (without --chart) and -E (without --every)")
+		exit(exitUsage)
+	}
+
+	imageOutput := *output == "svg" || *output == "png"
+	if imageOutput && !*sparkFlag {
+		fmt.Fprintf(os.Stderr, "Error: --output %s requires --spark\n", *output)
+		exit(exitUsage)
+	}
+
 	switch {
 	case *sparkFlag:
 		config := interval.SparkConfig{
-			Width: *sparkWidth,
+			Height: *sparkHeight,
+			ASCII:  *ascii,
+			Chars:  []rune(*sparkChars),
+			Label:  *label,
+			Log:    *sparkLog,
+			Follow: *follow,
+		}
+		if *follow || flag.CommandLine.Changed("interval") {
+			// Without them, the window is redrawn for every value.
+			config.Refresh = *refresh
+		}
+		switch *sparkWidth {
+		case "":
+			// A sparkline drawn to a terminal is fitted to it rather than wrapped.
+			if stat, err := os.Stdout.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+				config.Fit = columns()
+			}
+		case "auto":
+			config.Width = columns()
+			if config.Width == 0 {
+				fmt.Fprintln(os.Stderr, "Error: --spark-width auto: could not detect the terminal width (set COLUMNS)")
+				exit(exitUsage)
+			}
+			if *label != "" {
+				config.Width -= len([]rune(*label)) + 1
+			}
+			config.Width = max(config.Width, 1)
+			if *sparkLabels {
+				// The width of the labels depends on the values, so each frame
+				// is fitted to the terminal as it is drawn.
+				config.Fit = columns()
+			}
+		default:
+			n, err := strconv.Atoi(*sparkWidth)
+			if err != nil || n < 0 {
+				fmt.Fprintf(os.Stderr, "Error: could not parse spark width '%s' (expected a number of characters or auto)\n", *sparkWidth)
+				exit(exitUsage)
+			}
+			config.Width = n
+		}
+		if config.Follow && config.Width == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --follow requires --spark-width")
+			exit(exitUsage)
+		}
+		if *refresh <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+			exit(exitUsage)
+		}
+		if flag.CommandLine.Changed("baseline") {
+			if config.ASCII || flag.CommandLine.Changed("chars") || *sparkStyle == string(interval.StyleBraille) || *sparkStyle == string(interval.StyleWinLoss) {
+				fmt.Fprintln(os.Stderr, "Error: --baseline cannot be combined with --ascii, --chars, --style braille or --style winloss")
+				exit(exitUsage)
+			}
+			config.Baseline, config.HasBaseline = *baseline, true
+		}
+		if config.Height < 1 {
+			fmt.Fprintln(os.Stderr, "Error: --height must be at least 1")
+			exit(exitUsage)
 		}
 
 		var err error
-		config.Color, err = interval.ParseColor(*sparkColor)
+		if !imageOutput { // Images take any color, as --palette does.
+			config.Color, err = interval.ParseColor(*sparkColor)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		if *colorGradient != "" {
+			if *sparkColor != "" {
+				fmt.Fprintln(os.Stderr, "Error: --color-gradient cannot be combined with --spark-color")
+				exit(exitUsage)
+			}
+			config.Gradient, err = interval.ParseColorRange(*colorGradient)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				exit(exitUsage)
+			}
+		}
+		if !color {
+			config.Color, config.Gradient = interval.ColorNone, nil
+		}
+		if *sparkLabels {
+			config.Labels = formatLabel(opts)
+		}
+		if flag.CommandLine.Changed("gap-char") {
+			gap := []rune(*gapChar)
+			if len(gap) != 1 {
+				fmt.Fprintln(os.Stderr, "Error: --gap-char takes a single character")
+				exit(exitUsage)
+			}
+			config.Gap = gap[0]
+			if config.ASCII && *gapChar == defaultGap {
+				config.Gap = ' '
+			}
+		}
+		config.NaN = opts.nan
+		config.Strict = opts.strict
+		if config.NaN == interval.NaNPropagate && config.Gap == 0 {
+			config.Gap = ' ' // Propagated values are drawn as blanks.
+		}
+		config.Style, err = interval.ParseStyle(*sparkStyle)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "Error:", err)
-			os.Exit(1)
+			exit(exitUsage)
+		}
+		if config.ASCII && config.Style == interval.StyleBraille {
+			fmt.Fprintln(os.Stderr, "Error: --ascii cannot be combined with --style braille")
+			exit(exitUsage)
+		}
+		if flag.CommandLine.Changed("chars") {
+			if len(config.Chars) < 2 {
+				fmt.Fprintln(os.Stderr, "Error: --chars needs at least 2 characters")
+				exit(exitUsage)
+			}
+			if config.Style == interval.StyleBraille || config.Style == interval.StyleWinLoss {
+				fmt.Fprintf(os.Stderr, "Error: --chars cannot be combined with --style %s\n", config.Style)
+				exit(exitUsage)
+			}
+		}
+		if config.Style == interval.StyleWinLoss && config.Height > 1 {
+			fmt.Fprintln(os.Stderr, "Error: --height cannot be combined with --style winloss")
+			exit(exitUsage)
 		}
 
 		if len(args) == 2 {
 			config.Min, err = strconv.ParseFloat(args[0], 64)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: could not parse min value '%s'\n", args[0])
-				os.Exit(1)
+				exit(exitUsage)
 			}
 			config.Max, err = strconv.ParseFloat(args[1], 64)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: could not parse max value '%s'\n", args[1])
-				os.Exit(1)
+				exit(exitUsage)
 			}
 			config.HasMin = true
 			config.HasMax = true
+			if config.Log && (config.Min <= 0 || config.Max <= 0) {
+				fmt.Fprintln(os.Stderr, "Error: --log requires a positive <min> and <max>")
+				exit(exitUsage)
+			}
 		} else if len(args) != 0 {
 			fmt.Fprintln(os.Stderr, "Error: --spark requires 0 or 2 arguments: [<min> <max>]")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
 
-		scanner := bufio.NewScanner(os.Stdin)
-		err = interval.GenerateSparkline(scanner, os.Stdout, config)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating sparkline: %v\n", err)
-			os.Exit(1)
+		if *series {
+			if config.Width > 0 || imageOutput {
+				fmt.Fprintln(os.Stderr, "Error: --series cannot be combined with --spark-width or --output svg|png")
+				exit(exitUsage)
+			}
+			if err := interval.GenerateSeries(opts.newScanner(os.Stdin), stdout, config, *sharedScale); err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating sparklines: %v\n", scanError(err))
+				exit(sparkExitCode(err))
+			}
+			stdout.WriteByte('\n')
+			break
 		}
 
-		if config.Width == 0 {
-			fmt.Println()
+		if imageOutput {
+			if config.Width > 0 {
+				fmt.Fprintf(os.Stderr, "Error: --output %s cannot be combined with --spark-width\n", *output)
+				exit(exitUsage)
+			}
+			writeImage(opts, config, *output, *sparkColor, *imageSize, *outputFile)
+			break
 		}
-	case *remapFlag:
+
+		// The sliding window is an animation, so it is written unbuffered.
+		var out io.Writer = stdout
+		if config.Width > 0 {
+			out = os.Stdout
+		}
+		scanner := opts.newScanner(os.Stdin)
+		err = interval.GenerateSparkline(scanner, out, config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating sparkline: %v\n", scanError(err))
+			exit(sparkExitCode(err))
+		}
+
+		if config.Width == 0 {
+			stdout.WriteByte('\n')
+		}
+	case *remapFlag:
 		if len(args) != 4 {
 			fmt.Fprintln(os.Stderr, "Error: -r, --remap requires 4 arguments: <src_a> <src_b> <dst_a> <dst_b>")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
 		srcA, errA := strconv.ParseFloat(args[0], 64)
 		srcB, errB := strconv.ParseFloat(args[1], 64)
+		if timeUnit != 0 {
+			// The source interval is a time span, given as timestamps.
+			srcA, errA = opts.parseValue(args[0])
+			srcB, errB = opts.parseValue(args[1])
+		}
 		dstA, errC := strconv.ParseFloat(args[2], 64)
 		dstB, errD := strconv.ParseFloat(args[3], 64)
 		if errA != nil || errB != nil || errC != nil || errD != nil {
 			fmt.Fprintln(os.Stderr, "Error: could not parse all remap arguments as numbers.")
-			os.Exit(1)
+			exit(exitUsage)
+		}
+		if interval.ZeroTolerance.Empty(srcA, srcB) {
+			fmt.Fprintln(os.Stderr, "Error: cannot remap from a source interval with zero delta")
+			exit(exitDomain)
+		}
+		if timeUnit != 0 {
+			if flag.CommandLine.Changed("scale") {
+				fmt.Fprintln(os.Stderr, "Error: --scale cannot be combined with --as time.")
+				exit(exitUsage)
+			}
+			scale := interval.TimeScale{
+				Domain: [2]time.Time{unixTime(srcA, timeUnit), unixTime(srcB, timeUnit)},
+				Range:  [2]float64{dstA, dstB},
+				Clamp:  *clampFlag,
+			}
+			remap := failsAs("remap", func(val float64) (float64, error) {
+				t, err := interval.UnixTime(val, timeUnit)
+				if err != nil {
+					return 0, err
+				}
+				return scale.Map(t)
+			})
+			if *clampFlag {
+				remap = countClamped(remap, srcA, srcB)
+			}
+			processStream(opts.clampTo(srcA, srcB), remap)
+			break
+		}
+		scale := parseScale(*scaleSpec, [2]float64{srcA, srcB}, [2]float64{dstA, dstB}, *clampFlag)
+		remap := failsAs("remap", scale.Map)
+		if *clampFlag {
+			remap = countClamped(remap, srcA, srcB)
+		}
+		processStream(opts.clampTo(srcA, srcB), remap)
+
+	case *remapDynamicFlag:
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --remap-dynamic requires 2 arguments: <dst_a> <dst_b>")
+			usage()
+			exit(exitUsage)
+		}
+		dstA, errA := strconv.ParseFloat(args[0], 64)
+		dstB, errB := strconv.ParseFloat(args[1], 64)
+		if errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all remap-dynamic arguments as numbers.")
+			exit(exitUsage)
+		}
+		forEachRow(opts, 3, "record", "a value, a source start and a source end", func(row []float64) {
+			summary.addInput(opts, row[0])
+			r, err := interval.Remap(row[0], row[1], row[2], dstA, dstB)
+			if err != nil {
+				processFailed(row[0], err)
+				return
+			}
+			printValues(opts, r)
+		})
+
+	case *remap2Flag:
+		if len(args) != 8 {
+			fmt.Fprintln(os.Stderr, "Error: --remap2 requires 8 arguments: <sx0> <sx1> <sy0> <sy1> <dx0> <dx1> <dy0> <dy1>")
+			usage()
+			exit(exitUsage)
+		}
+		var bounds [8]float64
+		for i, arg := range args {
+			var err error
+			if bounds[i], err = strconv.ParseFloat(arg, 64); err != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all remap2 arguments as numbers.")
+				exit(exitUsage)
+			}
+		}
+		sx0, sx1, sy0, sy1, dx0, dx1, dy0, dy1 := bounds[0], bounds[1], bounds[2], bounds[3], bounds[4], bounds[5], bounds[6], bounds[7]
+		if interval.ZeroTolerance.Empty(sx0, sx1) || interval.ZeroTolerance.Empty(sy0, sy1) {
+			fmt.Fprintln(os.Stderr, "Error: cannot remap from a source interval with zero delta")
+			exit(exitDomain)
+		}
+		var clampX, clampY bool
+		switch *clampAxes {
+		case "":
+		case "x":
+			clampX = true
+		case "y":
+			clampY = true
+		case "xy":
+			clampX, clampY = true, true
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown --clamp-axes '%s' (expected x, y or xy)\n", *clampAxes)
+			exit(exitUsage)
+		}
+
+		printHeader(opts, "x", "y")
+		forEachRow(opts, 2, "point", "an x and a y", func(point []float64) {
+			x, err := interval.Remap(point[0], sx0, sx1, dx0, dx1)
+			if err != nil {
+				processFailed(point[0], err)
+				return
+			}
+			y, err := interval.Remap(point[1], sy0, sy1, dy0, dy1)
+			if err != nil {
+				processFailed(point[1], err)
+				return
+			}
+			if clampX {
+				x = interval.Limit(x, dx0, dx1)
+			}
+			if clampY {
+				y = interval.Limit(y, dy0, dy1)
+			}
+			printValues(opts, x, y)
+		})
+
+	case *geoTileFlag:
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Error: --geo-tile requires 1 argument: <zoom>")
+			usage()
+			exit(exitUsage)
+		}
+		zoom, err := strconv.Atoi(args[0])
+		if err != nil || zoom < 0 || zoom > interval.MaxZoom {
+			fmt.Fprintf(os.Stderr, "Error: invalid zoom '%s' (expected an integer between 0 and %d)\n", args[0], interval.MaxZoom)
+			exit(exitUsage)
+		}
+		scale := 1.0 // Pixels per unit of the tile coordinates.
+		if *geoPixel {
+			scale = interval.TileSize
 		}
-		processStream(*format, func(val float64) (float64, error) {
-			return interval.Remap(val, srcA, srcB, dstA, dstB)
+
+		if *geoInverse {
+			printHeader(opts, "lat", "lon")
+			forEachRow(opts, 2, "point", "an x and a y", func(point []float64) {
+				lat, lon, err := interval.TileToLatLon(point[0]/scale, point[1]/scale, zoom)
+				if err != nil {
+					processFailed(point[0], err)
+					return
+				}
+				printValues(opts, lat, lon)
+			})
+			break
+		}
+		// Tiles are numbered from 0 to n-1: the east and south edges of the world
+		// belong to the last ones.
+		last := math.Exp2(float64(zoom)) - 1
+		printHeader(opts, "x", "y")
+		forEachRow(opts, 2, "point", "a latitude and a longitude", func(point []float64) {
+			x, y, err := interval.LatLonToTile(point[0], point[1], zoom)
+			if err != nil {
+				processFailed(point[0], err)
+				return
+			}
+			if *geoPixel {
+				printValues(opts, x*scale, y*scale)
+				return
+			}
+			printValues(opts, min(math.Floor(x), last), min(math.Floor(y), last))
 		})
 
 	case *limitFlag:
 		if len(args) != 2 {
 			fmt.Fprintln(os.Stderr, "Error: -l, --limit requires 2 arguments: <min> <max>")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
 		min, err := strconv.ParseFloat(args[0], 64)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: could not parse min value '%s': %v\n", args[0], err)
-			os.Exit(1)
+			exit(exitUsage)
 		}
 		max, err := strconv.ParseFloat(args[1], 64)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: could not parse max value '%s': %v\n", args[1], err)
-			os.Exit(1)
+			exit(exitUsage)
+		}
+		limit := func(val float64) float64 { return interval.Limit(val, min, max) }
+		if len(notation) == 1 { // Honor the open bounds of "[a, b)".
+			limit = notation[0].Limit
 		}
-		processStream(*format, func(val float64) (float64, error) {
-			return interval.Limit(val, min, max), nil
+		processStream(opts.clampTo(min, max), func(val float64) (float64, error) {
+			res := limit(val)
+			if res != val && !math.IsNaN(val) {
+				summary.clamped.Add(1)
+			}
+			return res, nil
 		})
 
 	case *encompassFlag:
 		if len(args) != 0 {
 			fmt.Fprintln(os.Stderr, "Error: -E, --encompass takes no arguments.")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
+		}
+
+		if *every != "" {
+			encompassEvery(*every, opts)
+			break
+		}
+
+		var r interval.RunningRange
+		forEachValue(opts, r.Add)
+		if r.Count == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no numbers found in input")
+			exit(exitEmpty)
+		}
+
+		if promOutput {
+			writePromGauges(*metric, []string{"min", "max"}, r.Min, r.Max)
+			break
+		}
+		printHeader(opts, "min", "max")
+		printValues(opts, r.Min, r.Max)
+	case *statsFlag:
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --stats takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+
+		values := readStream(opts)
+		summary := interval.Describe(values)
+		if summary.Count == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no numbers found in input")
+			exit(exitEmpty)
+		}
+		if promOutput {
+			writePromStats(*metric, values, summary)
+			break
+		}
+
+		printHeader(opts, "stat", "value")
+		if opts.binaryOut != nil {
+			printValues(opts, float64(summary.Count))
+		} else {
+			printFields(opts, "count", strconv.Itoa(summary.Count))
+		}
+		printLabeled(opts, "min", summary.Min)
+		printLabeled(opts, "max", summary.Max)
+		printLabeled(opts, "mean", summary.Mean)
+		printLabeled(opts, "median", summary.Median)
+		printLabeled(opts, "stddev", summary.Stddev)
+		printLabeled(opts, "p25", summary.P25)
+		printLabeled(opts, "p75", summary.P75)
+		printLabeled(opts, "p90", summary.P90)
+		printLabeled(opts, "p95", summary.P95)
+		printLabeled(opts, "p99", summary.P99)
+	case *percentileFlag:
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Error: --percentile requires 1 argument: <p>")
+			usage()
+			exit(exitUsage)
+		}
+		p, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not parse percentile '%s'\n", args[0])
+			exit(exitUsage)
+		}
+		estimator, err := interval.NewQuantileEstimator(p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		forEachValue(opts, estimator.Add)
+
+		result, err := estimator.Value()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+		printValues(opts, result)
+	case *cdfFlag:
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --cdf takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+
+		values := readStream(opts)
+		ecdf, err := interval.NewECDF(values)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+		for _, val := range values {
+			p, err := ecdf.CDF(val)
+			if err != nil {
+				processFailed(val, err)
+				continue
+			}
+			printValues(opts, p)
+		}
+	case *icdfFlag:
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Error: --icdf requires 1 argument: <p-file>")
+			usage()
+			exit(exitUsage)
+		}
+		probs, err := readProbabilities(opts, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitFailure)
+		}
+
+		ecdf, err := interval.NewECDF(readStream(opts))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+		for _, p := range probs {
+			q, err := ecdf.Quantile(p)
+			if err != nil {
+				processFailed(p, err)
+				continue
+			}
+			printValues(opts, q)
+		}
+	case *histFlag:
+		if len(args) != 1 && len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --hist requires 1 or 3 arguments: <bins> [<a> <b>]")
+			usage()
+			exit(exitUsage)
+		}
+		bins, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not parse bins '%s'\n", args[0])
+			exit(exitUsage)
+		}
+		if *chartWidth <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --chart-width must be a positive integer")
+			exit(exitUsage)
+		}
+		// The arguments are checked before the stream is read, which may never end.
+		var a, b float64
+		if len(args) == 3 {
+			var errA, errB error
+			a, errA = strconv.ParseFloat(args[1], 64)
+			b, errB = strconv.ParseFloat(args[2], 64)
+			if errA != nil || errB != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all hist arguments as numbers.")
+				exit(exitUsage)
+			}
+			if _, err := interval.Histogram(nil, bins, a, b); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(exitCode(err))
+			}
+		} else if bins <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: the number of bins must be a positive integer")
+			exit(exitDomain)
+		}
+
+		values := readStream(opts)
+		var r interval.RunningRange
+		for _, val := range values {
+			r.Add(val)
+		}
+		if r.Count == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no numbers found in input")
+			exit(exitEmpty)
+		}
+		if len(args) == 1 {
+			a, b = r.Min, r.Max
+		}
+
+		results, err := interval.Histogram(values, bins, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		if *chart {
+			labels := make([]string, len(results))
+			for i, bin := range results {
+				start, errStart := opts.formatValue(bin.Start)
+				end, errEnd := opts.formatValue(bin.End)
+				if errStart != nil || errEnd != nil {
+					fmt.Fprintf(os.Stderr, "Error: could not format the edges of bin %d\n", i+1)
+					exit(exitDomain)
+				}
+				closing := ")"
+				if i == len(results)-1 {
+					closing = "]"
+				}
+				labels[i] = "[" + start + ", " + end + closing
+			}
+			if *label != "" {
+				stdout.WriteString(*label + "\n")
+			}
+			if err := interval.RenderHistogram(stdout, results, labels, *chartWidth); err != nil {
+				fmt.Fprintf(os.Stderr, "Error rendering chart: %v\n", err)
+				exit(exitFailure)
+			}
+			break
+		}
+
+		if promOutput {
+			writePromHistogram(*metric, values, results)
+			break
+		}
+		printHeader(opts, "start", "end", "count")
+		for _, bin := range results {
+			if opts.binaryOut != nil {
+				printValues(opts, bin.Start, bin.End, float64(bin.Count))
+				continue
+			}
+			start, errStart := opts.formatValue(bin.Start)
+			end, errEnd := opts.formatValue(bin.End)
+			if errStart != nil || errEnd != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not format bin %g %g, skipping\n", bin.Start, bin.End)
+				continue
+			}
+			printFields(opts, start, end, strconv.Itoa(bin.Count))
+		}
+	case *outliersMode != "":
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --outliers takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+		if *outliersMode != "drop" && *outliersMode != "keep" && *outliersMode != "mark" {
+			fmt.Fprintf(os.Stderr, "Error: unknown outliers mode '%s' (expected drop, keep, or mark)\n", *outliersMode)
+			exit(exitUsage)
+		}
+
+		values := readStream(opts)
+		var fence interval.OutlierFence
+		var err error
+		switch *outlierMethod {
+		case "iqr":
+			fence, err = interval.IQRFence(values, *outlierK)
+		case "zscore":
+			k := *outlierK
+			if !flag.CommandLine.Changed("k") {
+				k = 3
+			}
+			fence, err = interval.ZScoreFence(values, k)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown outlier method '%s' (expected iqr or zscore)\n", *outlierMethod)
+			exit(exitUsage)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		for _, val := range values {
+			isOutlier := fence.IsOutlier(val)
+			switch {
+			case *outliersMode == "mark" && isOutlier:
+				printLabeled(opts, "*", val)
+			case *outliersMode == "mark",
+				*outliersMode == "drop" && !isOutlier,
+				*outliersMode == "keep" && isOutlier:
+				printValues(opts, val)
+			}
+		}
+	case *downsampleFlag:
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Error: --downsample requires 1 argument: <n>")
+			usage()
+			exit(exitUsage)
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not parse count '%s'\n", args[0])
+			exit(exitUsage)
 		}
 
-		minVal, maxVal, err := interval.Encompass(bufio.NewScanner(os.Stdin))
+		var results []float64
+		switch *downsampleMethod {
+		case "lttb":
+			results, err = interval.LTTB(readStream(opts), n)
+		case "uniform":
+			results, err = interval.DownsampleUniform(readStream(opts), n)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown downsample method '%s' (expected lttb or uniform)\n", *downsampleMethod)
+			exit(exitUsage)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(exitCode(err))
 		}
 
-		outputFormat := *format + " " + *format + "\n"
-		fmt.Printf(outputFormat, minVal, maxVal)
+		for _, res := range results {
+			printValues(opts, res)
+		}
 	case *divideFlag:
 		if len(args) != 3 {
 			fmt.Fprintln(os.Stderr, "Error: -n, --divide requires 3 arguments: <steps> <a> <b>")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
 		steps, errS := strconv.Atoi(args[0])
 		a, errA := strconv.ParseFloat(args[1], 64)
 		b, errB := strconv.ParseFloat(args[2], 64)
 		if errS != nil || errA != nil || errB != nil {
 			fmt.Fprintln(os.Stderr, "Error: could not parse all divide arguments.")
-			os.Exit(1)
+			exit(exitUsage)
 		}
 
 		results, err := interval.Divide(steps, a, b)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(exitCode(err))
+		}
+
+		for _, res := range results {
+			printValues(opts, res)
+		}
+	case *divideEaseFlag:
+		if len(args) != 4 {
+			fmt.Fprintln(os.Stderr, "Error: --divide-ease requires 4 arguments: <name> <steps> <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		ease, err := interval.ParseEase(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (available: %s)\n", err, strings.Join(interval.EaseNames(), ", "))
+			exit(exitUsage)
+		}
+		steps, errS := strconv.Atoi(args[1])
+		a, errA := strconv.ParseFloat(args[2], 64)
+		b, errB := strconv.ParseFloat(args[3], 64)
+		if errS != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all divide-ease arguments.")
+			exit(exitUsage)
+		}
+
+		results, err := interval.DivideEase(steps, a, b, ease)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		for _, res := range results {
+			printValues(opts, res)
+		}
+	case *niceFlag:
+		nice := func(a, b float64) ([2]float64, error) {
+			a, b, err := interval.Nice(a, b)
+			return [2]float64{a, b}, err
+		}
+		if len(args) == 0 {
+			printHeader(opts, "start", "end")
+			forEachPair(opts, func(pair [2]float64) {
+				rounded, err := nice(pair[0], pair[1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not round interval %v %v, skipping: %v\n", pair[0], pair[1], err)
+					summary.errored++
+					return
+				}
+				printValues(opts, rounded[0], rounded[1])
+			})
+			break
+		}
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --nice requires 0 or 2 arguments: [<a> <b>]")
+			usage()
+			exit(exitUsage)
+		}
+		a, errA := strconv.ParseFloat(args[0], 64)
+		b, errB := strconv.ParseFloat(args[1], 64)
+		if errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all nice arguments as numbers.")
+			exit(exitUsage)
+		}
+
+		rounded, err := nice(a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+		printHeader(opts, "start", "end")
+		printValues(opts, rounded[0], rounded[1])
+	case *ticksFlag:
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --ticks requires 3 arguments: <count> <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		count, errC := strconv.Atoi(args[0])
+		a, errA := strconv.ParseFloat(args[1], 64)
+		b, errB := strconv.ParseFloat(args[2], 64)
+		if timeUnit != 0 {
+			a, errA = opts.parseValue(args[1])
+			b, errB = opts.parseValue(args[2])
+		}
+		if errC != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all ticks arguments.")
+			exit(exitUsage)
+		}
+
+		if timeUnit != 0 {
+			// Calendar ticks, on whole minutes, hours, days, months or years.
+			scale := interval.TimeScale{Domain: [2]time.Time{unixTime(a, timeUnit), unixTime(b, timeUnit)}}
+			ticks, err := scale.Ticks(count)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(exitCode(err))
+			}
+			for _, t := range ticks {
+				printValues(opts, interval.UnixValue(t, timeUnit))
+			}
+			break
+		}
+		results, err := interval.Ticks(count, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
 		}
 
-		outputFormat := *format + "\n"
 		for _, res := range results {
-			fmt.Printf(outputFormat, res)
+			printValues(opts, res)
 		}
 	case *evalFlag:
 		if len(args) != 2 {
 			fmt.Fprintln(os.Stderr, "Error: -e, --eval requires 2 arguments: <a> <b>")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
 		a, errA := strconv.ParseFloat(args[0], 64)
 		b, errB := strconv.ParseFloat(args[1], 64)
 		if errA != nil || errB != nil {
 			fmt.Fprintln(os.Stderr, "Error: could not parse all eval arguments as numbers.")
-			os.Exit(1)
+			exit(exitUsage)
 		}
-		processStream(*format, func(val float64) (float64, error) {
-			return interval.Eval(val, a, b), nil
-		})
+		// Evaluating is inverting the scale that de-evaluates.
+		scale := parseScale(*scaleSpec, [2]float64{a, b}, [2]float64{0, 1}, *clampFlag)
+		if *scaleSpec == "linear" && !*clampFlag {
+			// Eval passes NaN and infinite parameters through, which scales reject.
+			processStream(opts.clampTo(0, 1), func(val float64) (float64, error) {
+				return interval.Eval(val, a, b), nil
+			})
+			break
+		}
+		eval := failsAs("evaluate", scale.Invert)
+		if *clampFlag {
+			eval = countClamped(eval, 0, 1)
+		}
+		processStream(opts.clampTo(0, 1), eval)
 	case *devalFlag:
 		if len(args) != 2 {
 			fmt.Fprintln(os.Stderr, "Error: -d, --deval requires 2 arguments: <a> <b>")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
 		a, errA := strconv.ParseFloat(args[0], 64)
 		b, errB := strconv.ParseFloat(args[1], 64)
 		if errA != nil || errB != nil {
 			fmt.Fprintln(os.Stderr, "Error: could not parse all deval arguments as numbers.")
-			os.Exit(1)
+			exit(exitUsage)
 		}
-		processStream(*format, func(val float64) (float64, error) {
-			return interval.Deval(val, a, b)
-		})
+		if interval.ZeroTolerance.Empty(a, b) {
+			fmt.Fprintln(os.Stderr, "Error: cannot de-evaluate in an interval with zero delta")
+			exit(exitDomain)
+		}
+		scale := parseScale(*scaleSpec, [2]float64{a, b}, [2]float64{0, 1}, *clampFlag)
+		deval := failsAs("de-evaluate", scale.Map)
+		if *clampFlag {
+			deval = countClamped(deval, a, b)
+		}
+		processStream(opts.clampTo(a, b), deval)
 	case *randomFlag:
 		if len(args) != 3 {
 			fmt.Fprintln(os.Stderr, "Error: -R, --random requires 3 arguments: <count> <a> <b>")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
 		count, errC := strconv.Atoi(args[0])
 		a, errA := strconv.ParseFloat(args[1], 64)
 		b, errB := strconv.ParseFloat(args[2], 64)
 		if errC != nil || errA != nil || errB != nil {
 			fmt.Fprintln(os.Stderr, "Error: could not parse all random arguments.")
-			os.Exit(1)
+			exit(exitUsage)
 		}
 
-		// Seed the generator for non-deterministic output
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		r := newRand(*seed)
 
-		results, err := interval.Random(r, count, a, b)
+		var results []float64
+		var err error
+		if *dist == "uniform" {
+			results, err = interval.Random(r, count, a, b)
+		} else {
+			distFunc, parseErr := interval.ParseDist(*dist)
+			if parseErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", parseErr)
+				exit(exitUsage)
+			}
+			results, err = interval.RandomDist(r, count, a, b, distFunc)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(exitCode(err))
 		}
 
-		outputFormat := *format + "\n"
 		for _, res := range results {
-			fmt.Printf(outputFormat, res)
+			printValues(opts, res)
 		}
-	case *snapFlag:
+	case *randomIntFlag:
 		if len(args) != 3 {
-			fmt.Fprintln(os.Stderr, "Error: -S, --snap requires 3 arguments: <steps> <a> <b>")
+			fmt.Fprintln(os.Stderr, "Error: --random-int requires 3 arguments: <count> <a> <b>")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
-		steps, errS := strconv.Atoi(args[0])
-		a, errA := strconv.ParseFloat(args[1], 64)
-		b, errB := strconv.ParseFloat(args[2], 64)
-		if errS != nil || errA != nil || errB != nil {
-			fmt.Fprintln(os.Stderr, "Error: could not parse all snap arguments.")
-			os.Exit(1)
+		count, errC := strconv.Atoi(args[0])
+		a, errA := strconv.ParseInt(args[1], 10, 64)
+		b, errB := strconv.ParseInt(args[2], 10, 64)
+		if errC != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all random-int arguments as integers.")
+			exit(exitUsage)
 		}
-		processStream(*format, func(val float64) (float64, error) {
-			return interval.Snap(val, steps, a, b)
-		})
-	case *subintervalsFlag:
-		if len(args) != 3 {
-			fmt.Fprintln(os.Stderr, "Error: -s, --subintervals requires 3 arguments: <steps> <a> <b>")
+
+		results, err := interval.RandomInt(newRand(*seed), count, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		// Integers are printed as-is; the float format does not apply.
+		for _, res := range results {
+			if opts.binaryOut != nil {
+				printValues(opts, float64(res))
+			} else {
+				printFields(opts, strconv.FormatInt(res, 10))
+			}
+		}
+	case *randomNormalFlag:
+		if len(args) != 3 && len(args) != 5 {
+			fmt.Fprintln(os.Stderr, "Error: --random-normal requires 3 or 5 arguments: <count> <mean> <stddev> [<a> <b>]")
 			usage()
-			os.Exit(1)
+			exit(exitUsage)
 		}
-		steps, errS := strconv.Atoi(args[0])
-		a, errA := strconv.ParseFloat(args[1], 64)
-		b, errB := strconv.ParseFloat(args[2], 64)
-		if errS != nil || errA != nil || errB != nil {
-			fmt.Fprintln(os.Stderr, "Error: could not parse all subintervals arguments.")
-			os.Exit(1)
+		count, errC := strconv.Atoi(args[0])
+		mean, errM := strconv.ParseFloat(args[1], 64)
+		stddev, errS := strconv.ParseFloat(args[2], 64)
+		if errC != nil || errM != nil || errS != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all random-normal arguments.")
+			exit(exitUsage)
 		}
 
-		results, err := interval.Subintervals(steps, a, b)
+		r := newRand(*seed)
+
+		var results []float64
+		var err error
+		if len(args) == 5 {
+			a, errA := strconv.ParseFloat(args[3], 64)
+			b, errB := strconv.ParseFloat(args[4], 64)
+			if errA != nil || errB != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all random-normal arguments.")
+				exit(exitUsage)
+			}
+			results, err = interval.RandomNormalTruncated(r, count, mean, stddev, a, b)
+		} else {
+			results, err = interval.RandomNormal(r, count, mean, stddev)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			exit(exitCode(err))
 		}
 
-		outputFormat := *format + " " + *format + "\n"
 		for _, res := range results {
-			fmt.Printf(outputFormat, res[0], res[1])
+			printValues(opts, res)
+		}
+	case *lerpColumnsFlag:
+		if len(args) != 1 && len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --lerp-columns requires 1 or 3 arguments: <t-column> [<from> <to>]")
+			usage()
+			exit(exitUsage)
+		}
+		tCol, err := strconv.Atoi(args[0])
+		if err != nil || tCol < 1 {
+			fmt.Fprintf(os.Stderr, "Error: invalid t column '%s' (expected a 1-based column number)\n", args[0])
+			exit(exitUsage)
+		}
+		var from, to []float64
+		if len(args) == 3 {
+			for i, vec := range []*[]float64{&from, &to} {
+				if *vec, err = readVector(opts, args[i+1]); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					exit(exitFailure)
+				}
+			}
+			if len(from) != len(to) {
+				fmt.Fprintf(os.Stderr, "Error: %s has %d components and %s has %d\n", args[1], len(from), args[2], len(to))
+				exit(exitUsage)
+			}
+		}
+		lerpColumns(opts, tCol, from, to)
+	case *randomWeightedFlag:
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --random-weighted requires 2 arguments: <count> <file>")
+			usage()
+			exit(exitUsage)
+		}
+		count, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not parse count '%s'\n", args[0])
+			exit(exitUsage)
+		}
+
+		file, err := os.Open(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitFailure)
+		}
+		bins, err := interval.ReadWeights(opts.newScanner(file))
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", args[1], err)
+			exit(exitFailure)
+		}
+
+		results, err := interval.RandomWeighted(newRand(*seed), count, bins)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		for _, res := range results {
+			printValues(opts, res)
+		}
+	case *jitterFlag:
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --jitter requires 3 arguments: <steps> <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		steps, errS := strconv.Atoi(args[0])
+		a, errA := strconv.ParseFloat(args[1], 64)
+		b, errB := strconv.ParseFloat(args[2], 64)
+		if errS != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all jitter arguments.")
+			exit(exitUsage)
+		}
+
+		results, err := interval.Jitter(newRand(*seed), steps, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		for _, res := range results {
+			printValues(opts, res)
+		}
+	case *quasiFlag:
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --quasi requires 3 arguments: <count> <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		count, errC := strconv.Atoi(args[0])
+		a, errA := strconv.ParseFloat(args[1], 64)
+		b, errB := strconv.ParseFloat(args[2], 64)
+		if errC != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all quasi arguments.")
+			exit(exitUsage)
+		}
+
+		results, err := interval.Halton(count, *base, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		for _, res := range results {
+			printValues(opts, res)
+		}
+	case *smoothFlag:
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Error: --smooth requires 1 argument: <window>")
+			usage()
+			exit(exitUsage)
+		}
+		window, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not parse window '%s'\n", args[0])
+			exit(exitUsage)
+		}
+		average, err := interval.NewMovingAverage(window)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+		processStream(opts, average.Add)
+	case *emaFlag:
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Error: --ema requires 1 argument: <alpha>")
+			usage()
+			exit(exitUsage)
+		}
+		alpha, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not parse alpha '%s'\n", args[0])
+			exit(exitUsage)
+		}
+		ema, err := interval.NewEMA(alpha)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+		processStream(opts, ema.Add)
+	case *rollingNormalizeFlag:
+		if len(args) != 1 && len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --rolling-normalize requires 1 or 3 arguments: <window> [<a> <b>]")
+			usage()
+			exit(exitUsage)
+		}
+		window, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not parse window '%s'\n", args[0])
+			exit(exitUsage)
+		}
+		a, b := 0.0, 1.0
+		if len(args) == 3 {
+			var errA, errB error
+			a, errA = strconv.ParseFloat(args[1], 64)
+			b, errB = strconv.ParseFloat(args[2], 64)
+			if errA != nil || errB != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all rolling-normalize arguments.")
+				exit(exitUsage)
+			}
+		}
+		normalizer, err := interval.NewRollingNormalizer(window, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+		processStream(opts, normalizer.Add)
+	case *diffFlag:
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --diff takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+		if *diffFirst != "drop" && *diffFirst != "zero" {
+			fmt.Fprintf(os.Stderr, "Error: unknown --diff-first value '%s' (expected drop or zero)\n", *diffFirst)
+			exit(exitUsage)
+		}
+
+		var differ interval.Differ
+		forEachValue(opts, func(val float64) {
+			diff, ok := differ.Add(val)
+			if ok || *diffFirst == "zero" {
+				printValues(opts, diff)
+			}
+		})
+	case *fillSpec != "":
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --fill takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+		filler, err := interval.NewGapFiller(*fillSpec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitUsage)
+		}
+
+		printAll := func(values []float64) {
+			for _, val := range values {
+				printValues(opts, val)
+			}
+		}
+		scanner := opts.newScanner(os.Stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			val := math.NaN() // Blank lines are gaps, as are literal "nan" tokens.
+			if line != "" {
+				val, err = opts.parseValue(line)
+				if err != nil {
+					inputFailed(opts, "input value", line, err)
+					continue
+				}
+			}
+			printAll(filler.Add(val))
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", scanError(err))
+			exit(exitFailure)
+		}
+		printAll(filler.Flush())
+	case *snapFlag:
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: -S, --snap requires 3 arguments: <steps> <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		steps, errS := strconv.Atoi(args[0])
+		a, errA := strconv.ParseFloat(args[1], 64)
+		b, errB := strconv.ParseFloat(args[2], 64)
+		if errS != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all snap arguments.")
+			exit(exitUsage)
+		}
+		processStream(opts.clampTo(a, b), countClamped(func(val float64) (float64, error) {
+			return interval.Snap(val, steps, a, b)
+		}, a, b))
+	case *subintervalsFlag:
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: -s, --subintervals requires 3 arguments: <steps> <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		steps, errS := strconv.Atoi(args[0])
+		a, errA := strconv.ParseFloat(args[1], 64)
+		b, errB := strconv.ParseFloat(args[2], 64)
+		if errS != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all subintervals arguments.")
+			exit(exitUsage)
+		}
+
+		results, err := interval.SubintervalsOverlap(steps, a, b, *overlap)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		printHeader(opts, "start", "end")
+		for _, res := range results {
+			printValues(opts, res[0], res[1])
+		}
+	case *goldenFlag:
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --golden requires 3 arguments: <n> <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		n, errN := strconv.Atoi(args[0])
+		a, errA := strconv.ParseFloat(args[1], 64)
+		b, errB := strconv.ParseFloat(args[2], 64)
+		if errN != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all golden arguments.")
+			exit(exitUsage)
+		}
+
+		results, err := interval.Golden(n, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		printHeader(opts, "start", "end")
+		for _, res := range results {
+			printValues(opts, res[0], res[1])
+		}
+	case *fibonacciFlag:
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --fibonacci requires 3 arguments: <n> <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		n, errN := strconv.Atoi(args[0])
+		a, errA := strconv.ParseFloat(args[1], 64)
+		b, errB := strconv.ParseFloat(args[2], 64)
+		if errN != nil || errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all fibonacci arguments.")
+			exit(exitUsage)
+		}
+
+		results, err := interval.Fibonacci(n, a, b)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+
+		printHeader(opts, "start", "end")
+		for _, res := range results {
+			printValues(opts, res[0], res[1])
+		}
+	case *heatFlag:
+		config := interval.HeatConfig{Plain: !color}
+		var err error
+		config.Gradient, err = interval.ParsePalette(*palette)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		if len(args) == 2 {
+			var errMin, errMax error
+			config.Min, errMin = strconv.ParseFloat(args[0], 64)
+			config.Max, errMax = strconv.ParseFloat(args[1], 64)
+			if errMin != nil || errMax != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all heat arguments as numbers.")
+				exit(exitUsage)
+			}
+			config.HasRange = true
+		} else if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --heat requires 0 or 2 arguments: [<min> <max>]")
+			usage()
+			exit(exitUsage)
+		}
+
+		if *label != "" {
+			stdout.WriteString(*label + " ")
+		}
+		scanner := opts.newScanner(os.Stdin)
+		if err := interval.GenerateHeatStrip(scanner, stdout, config); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating heat strip: %v\n", scanError(err))
+			exit(exitFailure)
+		}
+		stdout.WriteByte('\n')
+	case *quantizeFlag:
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "Error: --quantize requires 3 arguments: <a> <b> <n|out1,out2,...>")
+			usage()
+			exit(exitUsage)
+		}
+		a, errA := strconv.ParseFloat(args[0], 64)
+		b, errB := strconv.ParseFloat(args[1], 64)
+		if errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all quantize arguments as numbers.")
+			exit(exitUsage)
+		}
+		labels := bucketLabels(args[2])
+		buckets := len(labels)
+		if !strings.Contains(args[2], ",") {
+			// A single field is the bucket count.
+			n, err := strconv.Atoi(args[2])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid bucket count or labels: %q\n", args[2])
+				exit(exitUsage)
+			}
+			buckets, labels = n, nil
+		}
+		scale, err := interval.NewQuantizeScale(a, b, buckets)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+
+		opts.render, opts.renderOutputOnly = renderBucket(buckets, labels), true
+		processStream(opts.clampTo(a, b), func(val float64) (float64, error) {
+			i, err := scale.Bucket(val)
+			return float64(i), err
+		})
+	case *thresholdFlag:
+		if len(args) != 1 && len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --threshold requires 1 or 2 arguments: <t1,t2,...> [<out0,out1,...>]")
+			usage()
+			exit(exitUsage)
+		}
+		var thresholds []float64
+		for _, field := range strings.Split(args[0], ",") {
+			t, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid threshold: %q\n", field)
+				exit(exitUsage)
+			}
+			thresholds = append(thresholds, t)
+		}
+		scale, err := interval.NewThresholdScale(thresholds)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		var labels []string
+		if len(args) == 2 {
+			labels = bucketLabels(args[1])
+			if len(labels) != scale.Buckets() {
+				fmt.Fprintf(os.Stderr, "Error: --threshold with %d thresholds requires %d labels, got %d\n", len(thresholds), scale.Buckets(), len(labels))
+				exit(exitUsage)
+			}
+		}
+
+		opts.render, opts.renderOutputOnly = renderBucket(scale.Buckets(), labels), true
+		processStream(opts, func(val float64) (float64, error) {
+			i, err := scale.Bucket(val)
+			return float64(i), err
+		})
+	case *colorizeFlag:
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --colorize requires 2 arguments: <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		a, errA := strconv.ParseFloat(args[0], 64)
+		b, errB := strconv.ParseFloat(args[1], 64)
+		if errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all colorize arguments as numbers.")
+			exit(exitUsage)
+		}
+		if interval.ZeroTolerance.Empty(a, b) {
+			fmt.Fprintln(os.Stderr, "Error: cannot colorize over an interval with zero delta")
+			exit(exitDomain)
+		}
+		gradient, err := interval.ParsePalette(*palette)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		if *colorFormat != "hex" && *colorFormat != "rgb" {
+			fmt.Fprintf(os.Stderr, "Error: unknown --color-format '%s' (expected hex or rgb)\n", *colorFormat)
+			exit(exitUsage)
+		}
+
+		// The operation yields the parameter t of each value, which is rendered as
+		// the color of the palette at t.
+		opts.render = func(t float64) (string, error) {
+			return formatColor(gradient.At(t), *colorFormat), nil
+		}
+		processStream(opts.clampTo(a, b), func(val float64) (float64, error) {
+			return interval.Deval(val, a, b)
+		})
+	case *lerpColorFlag:
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --lerp-color requires 2 arguments: <from> <to>")
+			usage()
+			exit(exitUsage)
+		}
+		from, err := interval.ParseRGB(args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		to, err := interval.ParseRGB(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			exit(exitUsage)
+		}
+		space, err := interval.ParseColorSpace(*colorSpace)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: --color-space:", err)
+			exit(exitUsage)
+		}
+		if *colorFormat != "hex" && *colorFormat != "rgb" {
+			fmt.Fprintf(os.Stderr, "Error: unknown --color-format '%s' (expected hex or rgb)\n", *colorFormat)
+			exit(exitUsage)
+		}
+
+		opts.render = func(t float64) (string, error) {
+			return formatColor(interval.LerpColor(from, to, t, space), *colorFormat), nil
+		}
+		processStream(opts.clampTo(0, 1), func(t float64) (float64, error) {
+			return t, nil
+		})
+	case *dashboardFlag:
+		config := interval.SparkConfig{ASCII: *ascii, Chars: []rune(*sparkChars)}
+		if len(args) == 2 {
+			var errMin, errMax error
+			config.Min, errMin = strconv.ParseFloat(args[0], 64)
+			config.Max, errMax = strconv.ParseFloat(args[1], 64)
+			if errMin != nil || errMax != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all dashboard arguments as numbers.")
+				exit(exitUsage)
+			}
+			config.HasMin, config.HasMax = true, true
+		} else if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --dashboard requires 0 or 2 arguments: [<min> <max>]")
+			usage()
+			exit(exitUsage)
+		}
+		if color {
+			var err error
+			if config.Color, err = interval.ParseColor(*sparkColor); err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				exit(exitUsage)
+			}
+		}
+		if *refresh <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+			exit(exitUsage)
+		}
+		runDashboard(opts, config, *label, *refresh)
+	case *exprSpec != "":
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --expr takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+		expr, err := interval.ParseExpr(*exprSpec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitUsage)
+		}
+		processStream(opts, expr.Apply)
+	case *mapCmd != "":
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --map-cmd takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+		if *csvFlag || *allFields || *binaryInSpec != "" {
+			fmt.Fprintln(os.Stderr, "Error: --map-cmd cannot be combined with --csv, --all-fields or --binary-in.")
+			exit(exitUsage)
+		}
+		if *chunk <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --chunk must be positive")
+			exit(exitUsage)
+		}
+		mapStream(opts, *mapCmd, *chunk)
+	case *serveAddr != "":
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --serve takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+		config := interval.SparkConfig{ASCII: *ascii, Chars: []rune(*sparkChars)}
+		if err := serveStream(opts, *serveAddr, config); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --serve: %v\n", err)
+			exit(exitFailure)
+		}
+	case *intersectFlag, *clipFlag, *hullFlag:
+		name := "intersect"
+		switch {
+		case *clipFlag:
+			name = "clip"
+		case *hullFlag:
+			name = "hull"
+		}
+		if len(args) != 2 {
+			fmt.Fprintf(os.Stderr, "Error: --%s requires 2 arguments: <a> <b>\n", name)
+			usage()
+			exit(exitUsage)
+		}
+		a, errA := strconv.ParseFloat(args[0], 64)
+		b, errB := strconv.ParseFloat(args[1], 64)
+		if errA != nil || errB != nil {
+			fmt.Fprintf(os.Stderr, "Error: could not parse all %s arguments as numbers.\n", name)
+			exit(exitUsage)
+		}
+
+		bounds := [2]float64{a, b}
+		printHeader(opts, "start", "end")
+		forEachPair(opts, func(pair [2]float64) {
+			ok := true
+			switch {
+			case *clipFlag:
+				pair, ok = interval.Clip(pair, bounds)
+			case *hullFlag:
+				pair = interval.Union(pair, bounds)
+			default:
+				pair, ok = interval.Intersect(pair, bounds)
+			}
+			if !ok {
+				return
+			}
+			printValues(opts, pair[0], pair[1])
+		})
+	case *splitSpec != "":
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --split takes no arguments.")
+			usage()
+			exit(exitUsage)
+		}
+		var points []float64
+		for _, field := range strings.Split(*splitSpec, ",") {
+			point, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil || math.IsNaN(point) {
+				fmt.Fprintf(os.Stderr, "Error: invalid split point: %q\n", field)
+				exit(exitUsage)
+			}
+			points = append(points, point)
+		}
+		printHeader(opts, "start", "end")
+		forEachPair(opts, func(pair [2]float64) {
+			for _, piece := range interval.Split(pair, points) {
+				printValues(opts, piece[0], piece[1])
+			}
+		})
+	case *relateFlag:
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --relate requires 2 arguments: <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		a, errA := strconv.ParseFloat(args[0], 64)
+		b, errB := strconv.ParseFloat(args[1], 64)
+		if errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all relate arguments as numbers.")
+			exit(exitUsage)
+		}
+		reference := [2]float64{a, b}
+		printHeader(opts, "relation")
+		forEachPair(opts, func(pair [2]float64) {
+			printFields(opts, string(interval.Relation(pair, reference)))
+		})
+	case *mergeFlag:
+		if len(args) != 0 {
+			fmt.Fprintln(os.Stderr, "Error: --merge takes no arguments")
+			usage()
+			exit(exitUsage)
+		}
+		var pairs [][2]float64
+		forEachPair(opts, func(pair [2]float64) {
+			pairs = append(pairs, pair)
+		})
+		printHeader(opts, "start", "end")
+		for _, pair := range interval.Merge(pairs) {
+			printValues(opts, pair[0], pair[1])
+		}
+	case *gapsFlag:
+		if len(args) != 0 && len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --gaps requires 0 or 2 arguments: [<a> <b>]")
+			usage()
+			exit(exitUsage)
+		}
+		var within [2]float64
+		if len(args) == 2 {
+			var errA, errB error
+			within[0], errA = strconv.ParseFloat(args[0], 64)
+			within[1], errB = strconv.ParseFloat(args[1], 64)
+			if errA != nil || errB != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all gaps arguments as numbers.")
+				exit(exitUsage)
+			}
+		}
+		var pairs [][2]float64
+		forEachPair(opts, func(pair [2]float64) {
+			pairs = append(pairs, pair)
+		})
+		gaps := interval.Gaps(pairs)
+		if len(args) == 2 {
+			gaps = interval.Complement(pairs, within)
+		}
+		printHeader(opts, "start", "end")
+		for _, gap := range gaps {
+			printValues(opts, gap[0], gap[1])
+		}
+	case *coverageFlag:
+		if len(args) != 0 && len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --coverage requires 0 or 2 arguments: [<a> <b>]")
+			usage()
+			exit(exitUsage)
+		}
+		var within [2]float64
+		if len(args) == 2 {
+			var errA, errB error
+			within[0], errA = strconv.ParseFloat(args[0], 64)
+			within[1], errB = strconv.ParseFloat(args[1], 64)
+			if errA != nil || errB != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all coverage arguments as numbers.")
+				exit(exitUsage)
+			}
+			if _, _, err := interval.Coverage(nil, within); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(exitDomain)
+			}
+		}
+		var pairs [][2]float64
+		forEachPair(opts, func(pair [2]float64) {
+			pairs = append(pairs, pair)
+		})
+		merged := interval.Merge(pairs)
+		if len(args) == 0 {
+			if len(merged) == 0 {
+				fmt.Fprintln(os.Stderr, "Error: no intervals found in input")
+				exit(exitEmpty)
+			}
+			within = [2]float64{merged[0][0], merged[len(merged)-1][1]}
+		}
+
+		length, fraction, err := interval.Coverage(merged, within)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitDomain)
+		}
+		printHeader(opts, "stat", "value")
+		printLabeled(opts, "covered", length)
+		printLabeled(opts, "fraction", fraction)
+	case *boxplotFlag:
+		if len(args) != 0 && len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --boxplot requires 0 or 2 arguments: [<min> <max>]")
+			usage()
+			exit(exitUsage)
+		}
+		if *chartWidth <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --chart-width must be a positive integer")
+			exit(exitUsage)
+		}
+		k := 1.5
+		if flag.CommandLine.Changed("k") {
+			k = *outlierK
+		}
+		var lo, hi float64
+		if len(args) == 2 {
+			var errMin, errMax error
+			lo, errMin = strconv.ParseFloat(args[0], 64)
+			hi, errMax = strconv.ParseFloat(args[1], 64)
+			if errMin != nil || errMax != nil {
+				fmt.Fprintln(os.Stderr, "Error: could not parse all boxplot arguments as numbers.")
+				exit(exitUsage)
+			}
+		}
+
+		values := readStream(opts)
+		box, err := interval.NewBoxPlot(values, k)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(exitCode(err))
+		}
+		if len(args) == 0 {
+			lo, hi = math.Min(box.LowerWhisker, box.Q1), math.Max(box.UpperWhisker, box.Q3)
+			if len(box.Outliers) > 0 {
+				lo = math.Min(lo, box.Outliers[0])
+				hi = math.Max(hi, box.Outliers[len(box.Outliers)-1])
+			}
+		}
+
+		var prefix, suffix string
+		if *label != "" {
+			prefix = *label + " "
+		}
+		if *sparkLabels {
+			format := formatLabel(opts)
+			prefix += format(lo) + " "
+			suffix = " " + format(hi)
+		}
+		width := *chartWidth
+		if !flag.CommandLine.Changed("chart-width") {
+			if cols := columns(); cols > 0 {
+				width = max(cols-len([]rune(prefix+suffix)), 1)
+			}
+		}
+		printLine(opts, prefix+box.Render(width, lo, hi)+suffix)
+	case *gaugeFlag:
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Error: --gauge requires 2 arguments: <a> <b>")
+			usage()
+			exit(exitUsage)
+		}
+		config := interval.GaugeConfig{Width: *chartWidth, ASCII: *ascii, Label: *label, Color: color, Format: formatLabel(opts)}
+		var errA, errB error
+		config.Min, errA = strconv.ParseFloat(args[0], 64)
+		config.Max, errB = strconv.ParseFloat(args[1], 64)
+		if errA != nil || errB != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not parse all gauge arguments as numbers.")
+			exit(exitUsage)
+		}
+		if *chartWidth <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --chart-width must be a positive integer")
+			exit(exitUsage)
+		}
+		if *refresh <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+			exit(exitUsage)
 		}
+		config.Warn, config.HasWarn = *warn, flag.CommandLine.Changed("warn")
+		config.Crit, config.HasCrit = *crit, flag.CommandLine.Changed("crit")
+		gaugeStream(opts, config, *refresh)
 	}
+	finishOutput(opts)
 }
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestMain runs span itself instead of the tests when SPAN_TEST_MAIN is set, so that
+// runSpan can test the command line in a process of its own.
+func TestMain(m *testing.M) {
+	if os.Getenv("SPAN_TEST_MAIN") != "" {
+		main()
+		exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runSpan runs span with args and input on stdin, and returns its stdout,
+// its stderr and its exit code.
+func runSpan(t *testing.T, input string, args ...string) (string, string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "SPAN_TEST_MAIN=1", "XDG_CONFIG_HOME="+t.TempDir(), "NO_COLOR=1")
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stdout.String(), stderr.String(), exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatalf("running span %q returned an unexpected error: %v", args, err)
+	}
+	return stdout.String(), stderr.String(), 0
+}
+
+func TestDetachedJoinSep(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+		ok   bool
+	}{
+		{"attached", []string{"-n", "4", "0", "1", "--join=,"}, "", false},
+		{"bare", []string{"-n", "4", "0", "1", "--join"}, "", false},
+		{"detached", []string{"-n", "4", "0", "1", "--join", ","}, ",", true},
+		{"detached spaces", []string{"--join", " | ", "-n", "4", "0", "1"}, " | ", true},
+		{"detached empty", []string{"--join", "", "-n", "4", "0", "1"}, "", true},
+		{"followed by a flag", []string{"--join", "-f", "%.1f"}, "", false},
+		{"followed by an argument", []string{"-n", "--join", "4", "0", "1"}, "", false},
+		{"after --", []string{"--", "--join", ","}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := detachedJoinSep(tt.args)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("detachedJoinSep(%q) = %q, %v, want %q, %v", tt.args, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestZeroDeltaIntervals(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"remap", []string{"-r", "1", "1.0000000000000002", "0", "10"}},
+		{"remap2", []string{"--remap2", "0", "1", "1", "1.0000000000000002", "0", "1", "0", "1"}},
+		{"deval", []string{"-d", "1", "1.0000000000000002"}},
+		{"colorize", []string{"--colorize", "1", "1.0000000000000002"}},
+		{"remap with a larger epsilon", []string{"--epsilon", "0.01", "-r", "1", "1.001", "0", "10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, code := runSpan(t, "1\n", tt.args...); code != exitDomain {
+				t.Errorf("span %q exit code = %d, want %d", tt.args, code, exitDomain)
+			}
+		})
+	}
+	if got, _, code := runSpan(t, "1\n", "--epsilon", "0", "-r", "1", "1.0000000000000002", "0", "10"); code != 0 || got != "0\n" {
+		t.Errorf("span --epsilon 0 -r = %q, exit code %d, want %q, exit code 0", got, code, "0\n")
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		args       []string
+		want       int
+		wantOutput string
+	}{
+		{"success", "5\n", []string{"-r", "0", "10", "0", "100"}, 0, "50\n"},
+		{"skipped input", "x\n5\n", []string{"-r", "0", "10", "0", "100"}, 0, "50\n"},
+		{"missing arguments", "5\n", []string{"-r", "0", "10"}, exitUsage, ""},
+		{"unknown flag", "5\n", []string{"--no-such-flag"}, exitUsage, ""},
+		{"strict", "5\nx\n", []string{"--strict", "-r", "0", "10", "0", "100"}, exitParse, "50\n"},
+		{"strict sparkline", "1 2\nabc 3\n", []string{"--spark", "--strict"}, exitParse, ""},
+		{"strict series", "1 2\n3 4\nabc 5\n", []string{"--spark", "--series", "--strict"}, exitParse, ""},
+		{"empty input", "", []string{"--stats"}, exitEmpty, ""},
+		{"no numbers", "x\n", []string{"-E"}, exitEmpty, ""},
+		{"zero delta", "5\n", []string{"-r", "1", "1", "0", "100"}, exitDomain, ""},
+		{"rejected NaN", "5\nnan\n", []string{"--nan", "error", "-r", "0", "10", "0", "100"}, exitDomain, "50\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, code := runSpan(t, tt.input, tt.args...)
+			if code != tt.want {
+				t.Errorf("span %q exit code = %d, want %d", tt.args, code, tt.want)
+			}
+			if got != tt.wantOutput {
+				t.Errorf("span %q output = %q, want %q", tt.args, got, tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestEvalNonFinite(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"linear passes them through", []string{"-e", "0", "10"}, "NaN\n+Inf\n-Inf\n5\n"},
+		{"reversed interval", []string{"-e", "10", "0"}, "NaN\n-Inf\n+Inf\n5\n"},
+		{"skip policy", []string{"--nan", "skip", "-e", "0", "10"}, "5\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, code := runSpan(t, "nan\ninf\n-inf\n0.5\n", tt.args...)
+			if code != 0 || got != tt.want {
+				t.Errorf("span %q = %q, exit code %d, want %q, exit code 0", tt.args, got, code, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaleErrorsNameTheOperation(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-r", "0", "10", "0", "1"}, "cannot remap: NaN values are not supported"},
+		{[]string{"-e", "0", "10", "--clamp"}, "cannot evaluate: NaN values are not supported"},
+		{[]string{"-d", "0", "10"}, "cannot de-evaluate: NaN values are not supported"},
+	}
+	for _, tt := range tests {
+		_, stderr, _ := runSpan(t, "nan\n", tt.args...)
+		if !strings.Contains(stderr, tt.want) {
+			t.Errorf("span %q stderr = %q, want it to contain %q", tt.args, stderr, tt.want)
+		}
+	}
+}
+
+// TestArgumentsBeforeInput checks that invalid arguments are reported before
+// the input is read, rather than after an empty input is.
+func TestArgumentsBeforeInput(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"hist bounds", []string{"--hist", "4", "0", "x"}, exitUsage},
+		{"hist bins", []string{"--hist", "0", "0", "1"}, exitDomain},
+		{"hist bins over the range", []string{"--hist", "0"}, exitDomain},
+		{"boxplot bounds", []string{"--boxplot", "0", "x"}, exitUsage},
+		{"coverage bounds", []string{"--coverage", "0", "x"}, exitUsage},
+		{"coverage of an empty interval", []string{"--coverage", "1", "1"}, exitDomain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, code := runSpan(t, "", tt.args...); code != tt.want {
+				t.Errorf("span %q exit code = %d, want %d", tt.args, code, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncompassEveryDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		args     []string
+		want     string
+		wantCode int
+	}{
+		{"end of the stream", "1\n5\n-2\n", nil, "-2 5\n", 0},
+		{"strict", "1\nx\n5\n", []string{"--strict"}, "", exitParse},
+		{"rejected NaN", "1\nnan\n5\n", []string{"--nan", "error"}, "", exitDomain},
+		{"empty", "x\n", nil, "", exitEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-E", "--every", "1h"}, tt.args...)
+			got, _, code := runSpan(t, tt.input, args...)
+			if code != tt.wantCode || got != tt.want {
+				t.Errorf("span %q = %q, exit code %d, want %q, exit code %d", args, got, code, tt.want, tt.wantCode)
+			}
+		})
+	}
+}
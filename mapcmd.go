@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// mapResult is the output of the --map-cmd command for one value.
+type mapResult struct {
+	val float64
+	err error
+}
+
+// mapStream processes the lines of stdin as processStream does, with the values
+// transformed by an external command. The values are handed to the command in
+// chunks of chunkSize, one per line on its stdin, and the command must print one
+// number per line for each of them, in the same order. It is run with sh -c once
+// per chunk, so it may be any filter (awk, bc, a script...).
+func mapStream(opts streamOptions, command string, chunkSize int) {
+	var lines []string
+	run := func() {
+		// Collect the values of the chunk that reach the operation, then replay
+		// the chunk with the results of the command in their place.
+		var values []float64
+		collect := opts.nan.Apply(func(val float64) (float64, error) {
+			values = append(values, val)
+			return val, nil
+		}, opts.nanLo, opts.nanHi)
+		for _, line := range lines {
+			processLine(line, opts, collect)
+		}
+		results, err := runFilter(command, values)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --map-cmd: %v\n", err)
+			exit(exitFailure)
+		}
+		replay := opts.nan.Apply(func(float64) (float64, error) {
+			result := results[0]
+			results = results[1:]
+			return result.val, result.err
+		}, opts.nanLo, opts.nanHi)
+		for _, line := range lines {
+			reportLine(opts, processLine(line, opts, replay))
+		}
+		lines = lines[:0]
+	}
+
+	scanner := opts.newScanner(os.Stdin)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+		if len(lines) == chunkSize {
+			run()
+		}
+	}
+	if len(lines) > 0 {
+		run()
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", scanError(err))
+		exit(exitFailure)
+	}
+}
+
+// runFilter runs command with values on its stdin, one per line, and returns the
+// numbers it prints, one per value. Lines that are not numbers give an error for
+// their value, and the command failing or printing another number of lines is an
+// error.
+func runFilter(command string, values []float64) ([]mapResult, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	var input bytes.Buffer
+	for _, val := range values {
+		input.WriteString(strconv.FormatFloat(val, 'g', -1, 64))
+		input.WriteByte('\n')
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = &input
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", command, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	if len(lines) != len(values) {
+		return nil, fmt.Errorf("%s printed %d line(s) for %d value(s)", command, len(lines), len(values))
+	}
+	results := make([]mapResult, len(lines))
+	for i, line := range lines {
+		val, err := strconv.ParseFloat(strings.TrimSpace(line), 64)
+		if err != nil {
+			err = fmt.Errorf("invalid output of the command: %q", line)
+		}
+		results[i] = mapResult{val: val, err: err}
+	}
+	return results, nil
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// presetsPath returns the path of the presets file: span/presets.toml in
+// $XDG_CONFIG_HOME, or in ~/.config when it is unset.
+func presetsPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "span", "presets.toml"), nil
+}
+
+// expandPresets replaces each "--preset <name>" (or "--preset=<name>") of args
+// with the arguments of the named preset, read from the presets file. The file is
+// only read when a preset is used.
+func expandPresets(args []string) []string {
+	var presets map[string][]string
+	var expanded []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return append(expanded, args[i:]...)
+		}
+		name, ok := strings.CutPrefix(arg, "--preset=")
+		if !ok && arg == "--preset" {
+			if i+1 == len(args) {
+				fmt.Fprintln(os.Stderr, "Error: --preset requires a preset name")
+				exit(exitUsage)
+			}
+			i++
+			name, ok = args[i], true
+		}
+		if !ok {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		if presets == nil {
+			path, err := presetsPath()
+			if err == nil {
+				presets, err = readPresets(path)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error: --preset:", err)
+				exit(exitUsage)
+			}
+		}
+		preset, ok := presets[name]
+		if !ok {
+			names := make([]string, 0, len(presets))
+			for name := range presets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			fmt.Fprintf(os.Stderr, "Error: unknown preset '%s' (defined: %s)\n", name, strings.Join(names, ", "))
+			exit(exitUsage)
+		}
+		expanded = append(expanded, preset...)
+	}
+	return expanded
+}
+
+// readPresets reads the presets file at path.
+func readPresets(path string) (map[string][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	presets, err := parsePresets(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return presets, nil
+}
+
+// parsePresets parses presets written in TOML, one table per preset, and returns
+// the arguments of each one. A key of a table is a flag, given as --<key> when
+// its value is true and as --<key>=<value> otherwise, and the array "args" holds
+// the positional arguments:
+//
+//	[percent]
+//	remap = true
+//	args = [0, 1, 0, 100]
+//	int = true
+//
+// Only the subset of TOML this needs is supported: tables, comments, and values
+// that are strings, numbers, booleans or single-line arrays of them.
+func parsePresets(r io.Reader) (map[string][]string, error) {
+	presets := make(map[string][]string)
+	name := ""
+	var keys map[string]bool // The keys of the current table.
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, "["); ok {
+			table, rest, ok := strings.Cut(rest, "]")
+			if !ok || !isComment(rest) {
+				return nil, fmt.Errorf("line %d: invalid table header: %s", lineNo, line)
+			}
+			var err error
+			if name, err = tomlKey(table); err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			if _, ok := presets[name]; ok {
+				return nil, fmt.Errorf("line %d: preset %q is defined twice", lineNo, name)
+			}
+			presets[name] = []string{}
+			keys = make(map[string]bool)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a key = value pair or a [table]: %s", lineNo, line)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("line %d: key outside of a preset table", lineNo)
+		}
+		key, err := tomlKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		if keys[key] {
+			return nil, fmt.Errorf("line %d: key %q is defined twice in preset %q", lineNo, key, name)
+		}
+		keys[key] = true
+		values, isArray, err := tomlValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+
+		switch {
+		case key == "args":
+			if !isArray {
+				return nil, fmt.Errorf("line %d: args must be an array", lineNo)
+			}
+			for _, val := range values {
+				presets[name] = append(presets[name], val.text)
+			}
+		case key == "preset":
+			return nil, fmt.Errorf("line %d: a preset cannot use another preset", lineNo)
+		case isArray:
+			return nil, fmt.Errorf("line %d: only args can be an array", lineNo)
+		case values[0].isBool && values[0].text == "true":
+			presets[name] = append(presets[name], "--"+key)
+		default:
+			presets[name] = append(presets[name], "--"+key+"="+values[0].text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return presets, nil
+}
+
+// isComment reports whether the rest of a line is blank or a comment.
+func isComment(rest string) bool {
+	rest = strings.TrimSpace(rest)
+	return rest == "" || strings.HasPrefix(rest, "#")
+}
+
+// tomlKey parses a bare or quoted key.
+func tomlKey(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "\"") || strings.HasPrefix(s, "'") {
+		val, rest, err := tomlScalar(s)
+		if err != nil || strings.TrimSpace(rest) != "" {
+			return "", fmt.Errorf("invalid key: %s", s)
+		}
+		return val.text, nil
+	}
+	if s == "" || strings.ContainsAny(s, " \t\"'.[]#=") {
+		return "", fmt.Errorf("invalid key: %q", s)
+	}
+	return s, nil
+}
+
+// tomlVal is a scalar value, a string, number or boolean.
+type tomlVal struct {
+	text   string
+	isBool bool
+}
+
+// tomlValue parses the value of a key: a scalar, or a single-line array of them.
+func tomlValue(s string) ([]tomlVal, bool, error) {
+	rest, isArray := strings.CutPrefix(s, "[")
+	if !isArray {
+		val, rest, err := tomlScalar(s)
+		if err != nil {
+			return nil, false, err
+		}
+		if !isComment(rest) {
+			return nil, false, fmt.Errorf("unexpected text after the value: %s", rest)
+		}
+		return []tomlVal{val}, false, nil
+	}
+
+	var values []tomlVal
+	for {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return nil, false, fmt.Errorf("unterminated array (arrays must fit on one line)")
+		}
+		if after, ok := strings.CutPrefix(rest, "]"); ok {
+			if !isComment(after) {
+				return nil, false, fmt.Errorf("unexpected text after the array: %s", after)
+			}
+			return values, true, nil
+		}
+		val, after, err := tomlScalar(rest)
+		if err != nil {
+			return nil, false, err
+		}
+		values = append(values, val)
+		rest = strings.TrimSpace(after)
+		if after, ok := strings.CutPrefix(rest, ","); ok {
+			rest = after
+		} else if !strings.HasPrefix(rest, "]") {
+			return nil, false, fmt.Errorf("unterminated array (arrays must fit on one line)")
+		}
+	}
+}
+
+// tomlScalar parses the scalar at the start of s and returns the text after it.
+func tomlScalar(s string) (tomlVal, string, error) {
+	switch {
+	case strings.HasPrefix(s, "'"):
+		text, rest, ok := strings.Cut(s[1:], "'")
+		if !ok {
+			return tomlVal{}, "", fmt.Errorf("unterminated string: %s", s)
+		}
+		return tomlVal{text: text}, rest, nil
+	case strings.HasPrefix(s, "\""):
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				text, err := strconv.Unquote(s[:i+1])
+				if err != nil {
+					return tomlVal{}, "", fmt.Errorf("invalid string: %s", s[:i+1])
+				}
+				return tomlVal{text: text}, s[i+1:], nil
+			}
+		}
+		return tomlVal{}, "", fmt.Errorf("unterminated string: %s", s)
+	}
+
+	end := strings.IndexAny(s, ",]# \t")
+	if end < 0 {
+		end = len(s)
+	}
+	text := s[:end]
+	if text == "true" || text == "false" {
+		return tomlVal{text: text, isBool: true}, s[end:], nil
+	}
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64); err != nil {
+		return tomlVal{}, "", fmt.Errorf("invalid value: %q (strings must be quoted)", text)
+	}
+	return tomlVal{text: strings.ReplaceAll(text, "_", "")}, s[end:], nil
+}
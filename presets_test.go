@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePresets(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string][]string
+	}{
+		{"flags and args", "[percent]\nremap = true\nargs = [0, 1, 0, 100]\nint = true\n",
+			map[string][]string{"percent": {"--remap", "0", "1", "0", "100", "--int"}}},
+		{"values", "[p]\nformat = \"%.2f\"\nscale = 'log'\nprecision = 1_000\noff = false\n",
+			map[string][]string{"p": {"--format=%.2f", "--scale=log", "--precision=1000", "--off=false"}}},
+		{"escapes in basic strings", "[p]\nlabel = \"a \\\"b\\\"\\tc\"\n",
+			map[string][]string{"p": {"--label=a \"b\"\tc"}}},
+		{"literal strings keep backslashes", "[p]\nlabel = 'C:\\temp'\n",
+			map[string][]string{"p": {`--label=C:\temp`}}},
+		{"array of strings", "[p]\nargs = [\"-1\", 'x', 2.5]\n",
+			map[string][]string{"p": {"-1", "x", "2.5"}}},
+		{"empty array", "[p]\nargs = []\n",
+			map[string][]string{"p": {}}},
+		{"comments", "# presets\n[p] # the table\n\n  # indented\nint = true # trailing\nargs = [1, 2] # too\nlabel = \"a # b\"\n",
+			map[string][]string{"p": {"--int", "1", "2", "--label=a # b"}}},
+		{"quoted keys and tables", "[\"my preset\"]\n'int' = true\n",
+			map[string][]string{"my preset": {"--int"}}},
+		{"several presets", "[a]\nint = true\n[b]\n[c]\nargs = [1]\n",
+			map[string][]string{"a": {"--int"}, "b": {}, "c": {"1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePresets(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("parsePresets() returned an unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePresets() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePresetsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"duplicate preset", "[a]\n[b]\n[a]\n", `line 3: preset "a" is defined twice`},
+		{"duplicate key", "[a]\nint = true\nargs = [1]\nint = false\n", `line 4: key "int" is defined twice in preset "a"`},
+		{"duplicate args", "[a]\nargs = [1]\nargs = [2]\n", `line 3: key "args" is defined twice in preset "a"`},
+		{"unterminated table", "[a\n", "line 1: invalid table header: [a"},
+		{"text after a table", "[a] b\n", "line 1: invalid table header: [a] b"},
+		{"dotted table", "[a.b]\n", `line 1: invalid key: "a.b"`},
+		{"no value", "[a]\n\nint\n", "line 3: expected a key = value pair or a [table]: int"},
+		{"key outside of a table", "int = true\n", "line 1: key outside of a preset table"},
+		{"empty key", "[a]\n= 1\n", `line 2: invalid key: ""`},
+		{"unquoted string", "[a]\nscale = log\n", `line 2: invalid value: "log" (strings must be quoted)`},
+		{"unterminated string", "[a]\nlabel = \"abc\n", "line 2: unterminated string: \"abc"},
+		{"invalid escape", "[a]\nlabel = \"\\q\"\n", `line 2: invalid string: "\q"`},
+		{"text after a value", "[a]\nint = true false\n", "line 2: unexpected text after the value:  false"},
+		{"multi-line array", "[a]\nargs = [1,\n2]\n", "line 2: unterminated array (arrays must fit on one line)"},
+		{"text after an array", "[a]\nargs = [1] 2\n", "line 2: unexpected text after the array:  2"},
+		{"args not an array", "[a]\nargs = 1\n", "line 2: args must be an array"},
+		{"other array", "[a]\nlabels = [\"x\"]\n", "line 2: only args can be an array"},
+		{"nested preset", "[a]\npreset = \"b\"\n", "line 2: a preset cannot use another preset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parsePresets(strings.NewReader(tt.input))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("parsePresets() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPresets(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "span"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	presets := "[percent]\nremap = true\nargs = [0, 1, 0, 100]\n[int]\nint = true\n"
+	if err := os.WriteFile(filepath.Join(dir, "span", "presets.toml"), []byte(presets), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"no preset", []string{"-r", "0", "1", "0", "2"}, []string{"-r", "0", "1", "0", "2"}},
+		{"in place of the option", []string{"-f", "%.1f", "--preset", "percent", "-w"},
+			[]string{"-f", "%.1f", "--remap", "0", "1", "0", "100", "-w"}},
+		{"equals form", []string{"--preset=int"}, []string{"--int"}},
+		{"in the order given", []string{"--preset", "int", "--preset=percent", "--preset", "int"},
+			[]string{"--int", "--remap", "0", "1", "0", "100", "--int"}},
+		{"not after --", []string{"--preset", "int", "--", "--preset", "percent"},
+			[]string{"--int", "--", "--preset", "percent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandPresets(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandPresets(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
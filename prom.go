@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// promMetricName matches the valid names of Prometheus metrics.
+var promMetricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// promLabelEscaper and promHelpEscaper escape label values and HELP texts as
+// the exposition format requires.
+var (
+	promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	promHelpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+// promLabel formats the label name="value", escaping the value.
+func promLabel(name, value string) string {
+	return name + `="` + promLabelEscaper.Replace(value) + `"`
+}
+
+// promSample is a sample of a metric family: the metric name is the name of the
+// family followed by suffix.
+type promSample struct {
+	suffix string
+	labels string // e.g. `quantile="0.5"`, or empty.
+	val    float64
+}
+
+// promFloat formats a sample value as the exposition format expects it.
+func promFloat(val float64) string {
+	switch {
+	case math.IsInf(val, 1):
+		return "+Inf"
+	case math.IsInf(val, -1):
+		return "-Inf"
+	case math.IsNaN(val):
+		return "NaN"
+	}
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+// writePromFamily writes a metric family in the Prometheus text exposition
+// format: its HELP and TYPE lines, then its samples.
+func writePromFamily(name, kind, help string, samples ...promSample) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, promHelpEscaper.Replace(help), name, kind)
+	for _, s := range samples {
+		b.WriteString(name + s.suffix)
+		if s.labels != "" {
+			b.WriteString("{" + s.labels + "}")
+		}
+		b.WriteString(" " + promFloat(s.val) + "\n")
+	}
+	stdout.WriteString(b.String())
+}
+
+// writePromGauges writes one gauge per named value, named metric_<name>.
+func writePromGauges(metric string, names []string, values ...float64) {
+	for i, name := range names {
+		writePromFamily(metric+"_"+name, "gauge", "The "+name+" of the input values.", promSample{val: values[i]})
+	}
+}
+
+// writePromStats writes the statistics of --stats: the quartiles and upper
+// percentiles as a summary, with the sum and count of the values, and the
+// min, max, mean and standard deviation as gauges.
+func writePromStats(metric string, values []float64, s interval.Summary) {
+	sum := 0.0
+	for _, val := range values {
+		if !math.IsNaN(val) {
+			sum += val
+		}
+	}
+	writePromFamily(metric, "summary", "Quantiles of the input values.",
+		promSample{labels: promLabel("quantile", "0.25"), val: s.P25},
+		promSample{labels: promLabel("quantile", "0.5"), val: s.Median},
+		promSample{labels: promLabel("quantile", "0.75"), val: s.P75},
+		promSample{labels: promLabel("quantile", "0.9"), val: s.P90},
+		promSample{labels: promLabel("quantile", "0.95"), val: s.P95},
+		promSample{labels: promLabel("quantile", "0.99"), val: s.P99},
+		promSample{suffix: "_sum", val: sum},
+		promSample{suffix: "_count", val: float64(s.Count)},
+	)
+	writePromGauges(metric, []string{"min", "max", "mean", "stddev"}, s.Min, s.Max, s.Mean, s.Stddev)
+}
+
+// writePromHistogram writes a histogram whose buckets end at the upper edges
+// of bins. As Prometheus buckets are cumulative, each one counts every value up
+// to its edge, values below the first bin included.
+func writePromHistogram(metric string, values []float64, bins []interval.Bin) {
+	var sorted []float64
+	sum := 0.0
+	for _, val := range values {
+		if !math.IsNaN(val) {
+			sorted = append(sorted, val)
+			sum += val
+		}
+	}
+	sort.Float64s(sorted)
+
+	samples := make([]promSample, 0, len(bins)+3)
+	for _, bin := range bins {
+		n := sort.Search(len(sorted), func(i int) bool { return sorted[i] > bin.End })
+		samples = append(samples, promSample{suffix: "_bucket", labels: promLabel("le", promFloat(bin.End)), val: float64(n)})
+	}
+	samples = append(samples,
+		promSample{suffix: "_bucket", labels: promLabel("le", "+Inf"), val: float64(len(sorted))},
+		promSample{suffix: "_sum", val: sum},
+		promSample{suffix: "_count", val: float64(len(sorted))},
+	)
+	writePromFamily(metric, "histogram", "Distribution of the input values.", samples...)
+}
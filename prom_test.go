@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// captureStdout returns what fn writes to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	defer func(w *bufio.Writer) { stdout = w }(stdout)
+	var b bytes.Buffer
+	stdout = bufio.NewWriter(&b)
+	fn()
+	stdout.Flush()
+	return b.String()
+}
+
+func TestPromLabel(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"0.5", `le="0.5"`},
+		{`say "hi"`, `le="say \"hi\""`},
+		{`C:\tmp`, `le="C:\\tmp"`},
+		{"two\nlines", `le="two\nlines"`},
+	}
+	for _, tt := range tests {
+		if got := promLabel("le", tt.value); got != tt.want {
+			t.Errorf("promLabel(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWritePromFamily(t *testing.T) {
+	got := captureStdout(t, func() {
+		writePromFamily("span_test", "gauge", "Help with a \\ and\na line feed.",
+			promSample{val: 1.5},
+			promSample{suffix: "_x", labels: promLabel("path", `/a "b"`), val: math.NaN()},
+			promSample{suffix: "_x", labels: promLabel("path", "/c"), val: math.Inf(1)},
+			promSample{suffix: "_x", labels: promLabel("path", "/d"), val: math.Inf(-1)},
+		)
+	})
+	want := `# HELP span_test Help with a \\ and\na line feed.
+# TYPE span_test gauge
+span_test 1.5
+span_test_x{path="/a \"b\""} NaN
+span_test_x{path="/c"} +Inf
+span_test_x{path="/d"} -Inf
+`
+	if got != want {
+		t.Errorf("writePromFamily() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWritePromStats(t *testing.T) {
+	values := []float64{1, 2, math.NaN(), 3, 4}
+	got := captureStdout(t, func() { writePromStats("latency", values, interval.Describe(values)) })
+	want := `# HELP latency Quantiles of the input values.
+# TYPE latency summary
+latency{quantile="0.25"} 1.75
+latency{quantile="0.5"} 2.5
+latency{quantile="0.75"} 3.25
+latency{quantile="0.9"} 3.7
+latency{quantile="0.95"} 3.8499999999999996
+latency{quantile="0.99"} 3.9699999999999998
+latency_sum 10
+latency_count 4
+# HELP latency_min The min of the input values.
+# TYPE latency_min gauge
+latency_min 1
+# HELP latency_max The max of the input values.
+# TYPE latency_max gauge
+latency_max 4
+# HELP latency_mean The mean of the input values.
+# TYPE latency_mean gauge
+latency_mean 2.5
+# HELP latency_stddev The stddev of the input values.
+# TYPE latency_stddev gauge
+latency_stddev 1.2909944487358056
+`
+	if got != want {
+		t.Errorf("writePromStats() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWritePromHistogram(t *testing.T) {
+	values := []float64{-1, 0.5, 1, 2.5, math.NaN(), 4, math.Inf(1)}
+	bins := []interval.Bin{{Start: 0, End: 2, Count: 2}, {Start: 2, End: 4, Count: 2}}
+	got := captureStdout(t, func() { writePromHistogram("size", values, bins) })
+	want := `# HELP size Distribution of the input values.
+# TYPE size histogram
+size_bucket{le="2"} 3
+size_bucket{le="4"} 5
+size_bucket{le="+Inf"} 6
+size_sum +Inf
+size_count 6
+`
+	if got != want {
+		t.Errorf("writePromHistogram() =\n%s\nwant:\n%s", got, want)
+	}
+}
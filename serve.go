@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// WebSocket opcodes of RFC 6455.
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// wsGUID is appended to the key of a client to compute the accept header.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsMaxMessage is the largest message a client may send, in bytes.
+const wsMaxMessage = 1 << 20
+
+// serveStream listens on addr and serves a WebSocket endpoint at /ws, the
+// server mode of span. Each text message a client sends is a list of numbers
+// separated by whitespace, answered by one message: the numbers transformed by
+// the pipeline of the expr query parameter, formatted as on stdout, or with a
+// spark parameter, the frame of a sliding-window sparkline of that width fed
+// with them, scaled to the min and max parameters when given. The --nan policy
+// applies as for the other operations. Numbers that do not parse, or that the
+// pipeline fails on or the policy drops, are skipped; a value that --nan error
+// rejects answers the message with the error instead.
+func serveStream(opts streamOptions, addr string, config interval.SparkConfig) error {
+	fmt.Fprintf(os.Stderr, "Serving WebSocket streams on ws://%s/ws\n", addr)
+	return http.ListenAndServe(addr, newServeMux(opts, config))
+}
+
+// newServeMux returns the handler of the server mode.
+func newServeMux(opts streamOptions, config interval.SparkConfig) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		handle, err := newStreamHandler(opts, r.URL.Query(), config)
+		if err != nil {
+			http.Error(w, "Error: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		ws, err := upgradeWebSocket(w, r)
+		if err != nil {
+			http.Error(w, "Error: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer ws.Close()
+		for {
+			message, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := ws.WriteMessage(wsText, []byte(handle(message))); err != nil {
+				return
+			}
+		}
+	})
+	return mux
+}
+
+// newStreamHandler returns the function answering the messages of a client,
+// as set by the query parameters of its connection.
+func newStreamHandler(opts streamOptions, query url.Values, config interval.SparkConfig) (func(string) string, error) {
+	apply := func(val float64) (float64, error) { return val, nil }
+	if spec := query.Get("expr"); spec != "" {
+		expr, err := interval.ParseExpr(spec)
+		if err != nil {
+			return nil, err
+		}
+		apply = expr.Apply
+	}
+	apply = opts.nan.Apply(apply, opts.nanLo, opts.nanHi)
+	if opts.nan == interval.NaNPropagate && config.Gap == 0 {
+		config.Gap = ' ' // Propagated values are drawn as blanks, as with --spark.
+	}
+	var sparkline *interval.Sparkline
+	if width := query.Get("spark"); width != "" {
+		var err error
+		if config.Width, err = strconv.Atoi(width); err != nil || config.Width <= 0 {
+			return nil, fmt.Errorf("spark must be a positive number of characters, not '%s'", width)
+		}
+		for _, bound := range []struct {
+			name  string
+			val   *float64
+			isSet *bool
+		}{{"min", &config.Min, &config.HasMin}, {"max", &config.Max, &config.HasMax}} {
+			if s := query.Get(bound.name); s != "" {
+				if *bound.val, err = strconv.ParseFloat(s, 64); err != nil {
+					return nil, fmt.Errorf("could not parse %s '%s' as a number", bound.name, s)
+				}
+				*bound.isSet = true
+			}
+		}
+		if sparkline, err = interval.NewSparkline(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(message string) string {
+		var output []string
+		for _, field := range strings.Fields(message) {
+			val, err := opts.parseValue(field)
+			if err != nil {
+				continue
+			}
+			if val, err = apply(val); errors.Is(err, interval.ErrNonFinite) {
+				return "Error: " + err.Error()
+			} else if err != nil {
+				continue
+			}
+			if sparkline != nil {
+				sparkline.Add(val)
+			} else if s, err := opts.formatValue(val); err == nil {
+				output = append(output, s)
+			}
+		}
+		if sparkline != nil {
+			return sparkline.Render()
+		}
+		return strings.Join(output, " ")
+	}, nil
+}
+
+// wsConn is the server side of a WebSocket connection, with just what the
+// server mode needs: reading the messages of the client and answering them.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+// upgradeWebSocket answers the opening handshake of a WebSocket client and
+// takes over its connection.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet:
+		return nil, fmt.Errorf("a WebSocket handshake must use GET")
+	case !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket"):
+		return nil, fmt.Errorf("expected a WebSocket upgrade request")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		return nil, fmt.Errorf("unsupported WebSocket version (expected 13)")
+	case key == "":
+		return nil, fmt.Errorf("missing Sec-WebSocket-Key")
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("the connection cannot be upgraded")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum([]byte(key + wsGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// headerHasToken reports whether one of the comma-separated tokens of a header
+// is token, ignoring case.
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage returns the next text or binary message of the client, put back
+// together from its fragments. It answers pings as it goes, and returns io.EOF
+// once the client closes the connection.
+func (c *wsConn) ReadMessage() (string, error) {
+	var message []byte
+	started := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return "", err
+		}
+		switch opcode {
+		case wsClose:
+			c.WriteMessage(wsClose, nil)
+			return "", io.EOF
+		case wsPing:
+			if err := c.WriteMessage(wsPong, payload); err != nil {
+				return "", err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsText, wsBinary:
+			if started {
+				return "", errors.New("websocket: new message within a fragmented one")
+			}
+			started = true
+		case wsContinuation:
+			if !started {
+				return "", errors.New("websocket: continuation without a message")
+			}
+		default:
+			return "", fmt.Errorf("websocket: unknown opcode %d", opcode)
+		}
+		if len(message)+len(payload) > wsMaxMessage {
+			return "", errors.New("websocket: message too large")
+		}
+		message = append(message, payload...)
+		if fin {
+			return string(message), nil
+		}
+	}
+}
+
+// readFrame reads a frame of the client, which must be masked, and unmasks its
+// payload.
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.rw, header[:]); err != nil {
+		return
+	}
+	fin, opcode = header[0]&0x80 != 0, header[0]&0x0F
+	if header[1]&0x80 == 0 {
+		return fin, opcode, nil, errors.New("websocket: client frames must be masked")
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.rw, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.rw, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= wsClose && (!fin || length > 125) {
+		return fin, opcode, nil, errors.New("websocket: invalid control frame")
+	}
+	if length > wsMaxMessage {
+		return fin, opcode, nil, errors.New("websocket: message too large")
+	}
+	var mask [4]byte
+	if _, err = io.ReadFull(c.rw, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.rw, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends payload to the client in a single frame.
+func (c *wsConn) WriteMessage(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n <= 125:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	c.rw.Write(header)
+	c.rw.Write(payload)
+	return c.rw.Flush()
+}
+
+// Close closes the connection.
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// wsClient is the client side of a WebSocket connection to the test server.
+type wsClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialWS opens a WebSocket connection to path on server, and checks the
+// handshake.
+func dialWS(t *testing.T, server *httptest.Server, path string) *wsClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() returned an unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	io.WriteString(conn, "GET "+path+" HTTP/1.1\r\nHost: span\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("http.ReadResponse() returned an unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+	// The accept key of the sample handshake of RFC 6455.
+	if got, want := resp.Header.Get("Sec-WebSocket-Accept"), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("Sec-WebSocket-Accept = %q, want %q", got, want)
+	}
+	return &wsClient{conn: conn, reader: reader}
+}
+
+// writeFrame sends a frame, masked as clients must unless masked is false.
+func (c *wsClient) writeFrame(t *testing.T, fin bool, opcode byte, payload []byte, masked bool) {
+	t.Helper()
+	header := []byte{opcode}
+	if fin {
+		header[0] |= 0x80
+	}
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		header = append(header, maskBit|byte(n))
+	case n <= 0xFFFF:
+		header = append(header, maskBit|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, maskBit|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	data := payload
+	if masked {
+		var mask [4]byte
+		rand.Read(mask[:])
+		header = append(header, mask[:]...)
+		data = make([]byte, len(payload))
+		for i := range payload {
+			data[i] = payload[i] ^ mask[i%4]
+		}
+	}
+	if _, err := c.conn.Write(append(header, data...)); err != nil {
+		t.Fatalf("writing a frame returned an unexpected error: %v", err)
+	}
+}
+
+// send sends a text message in a single frame.
+func (c *wsClient) send(t *testing.T, message string) {
+	t.Helper()
+	c.writeFrame(t, true, wsText, []byte(message), true)
+}
+
+// readFrame reads a frame of the server, which must not be masked.
+func (c *wsClient) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	if header[0]&0x80 == 0 || header[1]&0x80 != 0 {
+		return 0, nil, errors.New("server frames must be final and unmasked")
+	}
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+		if length <= 125 {
+			return 0, nil, errors.New("16-bit length used for a short payload")
+		}
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+		if length <= 0xFFFF {
+			return 0, nil, errors.New("64-bit length used for a short payload")
+		}
+	}
+	payload := make([]byte, length)
+	_, err := io.ReadFull(c.reader, payload)
+	return header[0] & 0x0F, payload, err
+}
+
+// receive reads a text message of the server.
+func (c *wsClient) receive(t *testing.T) string {
+	t.Helper()
+	opcode, payload, err := c.readFrame()
+	if err != nil {
+		t.Fatalf("reading a frame returned an unexpected error: %v", err)
+	}
+	if opcode != wsText {
+		t.Fatalf("opcode = %d, want a text message", opcode)
+	}
+	return string(payload)
+}
+
+// expectClosed checks that the server dropped the connection.
+func (c *wsClient) expectClosed(t *testing.T) {
+	t.Helper()
+	if opcode, _, err := c.readFrame(); err == nil {
+		t.Errorf("read a frame of opcode %d, want the connection closed", opcode)
+	}
+}
+
+func newTestServer(t *testing.T, opts streamOptions) *httptest.Server {
+	if opts.format == "" {
+		opts.format = "%g"
+	}
+	server := httptest.NewServer(newServeMux(opts, interval.SparkConfig{}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestServeHandshake(t *testing.T) {
+	server := newTestServer(t, streamOptions{})
+	dialWS(t, server, "/ws")
+
+	upgrade := func(r *http.Request) {
+		r.Header.Set("Upgrade", "websocket")
+		r.Header.Set("Connection", "keep-alive, Upgrade")
+		r.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		r.Header.Set("Sec-WebSocket-Version", "13")
+	}
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		edit   func(r *http.Request)
+	}{
+		{"not an upgrade", http.MethodGet, "/ws", func(r *http.Request) {}},
+		{"POST", http.MethodPost, "/ws", upgrade},
+		{"no Upgrade header", http.MethodGet, "/ws", func(r *http.Request) { upgrade(r); r.Header.Del("Upgrade") }},
+		{"other version", http.MethodGet, "/ws", func(r *http.Request) { upgrade(r); r.Header.Set("Sec-WebSocket-Version", "8") }},
+		{"no key", http.MethodGet, "/ws", func(r *http.Request) { upgrade(r); r.Header.Del("Sec-WebSocket-Key") }},
+		{"invalid spark width", http.MethodGet, "/ws?spark=x", upgrade},
+		{"invalid expr", http.MethodGet, "/ws?expr=nope(", upgrade},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.edit(r)
+			resp, err := http.DefaultClient.Do(r)
+			if err != nil {
+				t.Fatalf("Do() returned an unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestServeFrames(t *testing.T) {
+	server := newTestServer(t, streamOptions{})
+
+	t.Run("values", func(t *testing.T) {
+		c := dialWS(t, server, "/ws?expr="+url.QueryEscape("remap(0,10,0,100)"))
+		c.send(t, "1 x 2.5")
+		if got := c.receive(t); got != "10 25" {
+			t.Errorf("answer = %q, want %q", got, "10 25")
+		}
+	})
+
+	t.Run("sparkline frames", func(t *testing.T) {
+		c := dialWS(t, server, "/ws?spark=3&min=0&max=8")
+		for _, tt := range []struct{ message, want string }{{"0", " "}, {"4 8", " ▄█"}, {"8", "▄██"}} {
+			c.send(t, tt.message)
+			if got := c.receive(t); got != tt.want {
+				t.Errorf("frame after %q = %q, want %q", tt.message, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("extended lengths", func(t *testing.T) {
+		c := dialWS(t, server, "/ws")
+		for _, n := range []int{100, 40000} { // 16-bit and 64-bit lengths, both ways.
+			message := strings.TrimSpace(strings.Repeat("1 ", n))
+			c.send(t, message)
+			if got := c.receive(t); got != message {
+				t.Errorf("answer to %d values has %d bytes, want %d", n, len(got), len(message))
+			}
+		}
+	})
+
+	t.Run("fragmented message", func(t *testing.T) {
+		c := dialWS(t, server, "/ws")
+		c.writeFrame(t, false, wsText, []byte("1 2"), true)
+		c.writeFrame(t, false, wsContinuation, []byte("3 "), true)
+		c.writeFrame(t, true, wsPing, []byte("ping"), true) // Control frames may come between fragments.
+		if opcode, payload, err := c.readFrame(); err != nil || opcode != wsPong || string(payload) != "ping" {
+			t.Fatalf("answer to the ping = %d %q %v, want a pong", opcode, payload, err)
+		}
+		c.writeFrame(t, true, wsContinuation, []byte("4"), true)
+		if got := c.receive(t); got != "1 23 4" {
+			t.Errorf("answer = %q, want %q", got, "1 23 4")
+		}
+	})
+
+	t.Run("ping and pong", func(t *testing.T) {
+		c := dialWS(t, server, "/ws")
+		c.writeFrame(t, true, wsPong, nil, true) // Unsolicited pongs are ignored.
+		c.writeFrame(t, true, wsPing, []byte("hello"), true)
+		if opcode, payload, err := c.readFrame(); err != nil || opcode != wsPong || string(payload) != "hello" {
+			t.Errorf("answer to the ping = %d %q %v, want a pong with its payload", opcode, payload, err)
+		}
+		c.send(t, "7")
+		if got := c.receive(t); got != "7" {
+			t.Errorf("answer = %q, want %q", got, "7")
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		c := dialWS(t, server, "/ws")
+		c.writeFrame(t, true, wsClose, nil, true)
+		if opcode, _, err := c.readFrame(); err != nil || opcode != wsClose {
+			t.Errorf("answer to the close = %d %v, want a close frame", opcode, err)
+		}
+		c.expectClosed(t)
+	})
+
+	errorCases := []struct {
+		name  string
+		write func(t *testing.T, c *wsClient)
+	}{
+		{"unmasked frame", func(t *testing.T, c *wsClient) { c.writeFrame(t, true, wsText, []byte("1"), false) }},
+		{"frame over the cap", func(t *testing.T, c *wsClient) {
+			c.writeFrame(t, true, wsText, make([]byte, wsMaxMessage+1), true)
+		}},
+		{"fragments over the cap", func(t *testing.T, c *wsClient) {
+			c.writeFrame(t, false, wsText, make([]byte, wsMaxMessage), true)
+			c.writeFrame(t, true, wsContinuation, []byte("1"), true)
+		}},
+		{"continuation without a message", func(t *testing.T, c *wsClient) {
+			c.writeFrame(t, true, wsContinuation, []byte("1"), true)
+		}},
+		{"message within a fragmented one", func(t *testing.T, c *wsClient) {
+			c.writeFrame(t, false, wsText, []byte("1"), true)
+			c.writeFrame(t, true, wsText, []byte("2"), true)
+		}},
+		{"fragmented control frame", func(t *testing.T, c *wsClient) { c.writeFrame(t, false, wsPing, nil, true) }},
+		{"unknown opcode", func(t *testing.T, c *wsClient) { c.writeFrame(t, true, 0x3, nil, true) }},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			c := dialWS(t, server, "/ws")
+			tt.write(t, c)
+			c.expectClosed(t)
+		})
+	}
+}
+
+func TestServeNaNPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy interval.NaNPolicy
+		query  string
+		want   string
+	}{
+		{"default", interval.NaNDefault, "", "1 NaN +Inf 2"},
+		{"skip", interval.NaNSkip, "", "1 2"},
+		{"zero", interval.NaNZero, "", "1 0 0 2"},
+		{"clamp", interval.NaNClamp, "", "1 1.7976931348623157e+308 2"},
+		{"error", interval.NaNError, "", "Error: non-finite value: NaN"},
+		{"propagate through the pipeline", interval.NaNPropagate, "expr=clamp(0,1)", "1 NaN +Inf 1"},
+		{"propagate as a gap", interval.NaNPropagate, "spark=4&min=0&max=2", "▄  █"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := streamOptions{format: "%g", nan: tt.policy, nanLo: -math.MaxFloat64, nanHi: math.MaxFloat64}
+			query, _ := url.ParseQuery(tt.query)
+			handle, err := newStreamHandler(opts, query, interval.SparkConfig{})
+			if err != nil {
+				t.Fatalf("newStreamHandler() returned an unexpected error: %v", err)
+			}
+			if got := handle("1 nan inf 2"); got != tt.want {
+				t.Errorf("answer = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,1005 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// processFunc defines a function signature for processing a single float64 value.
+// It's used to pass different interval operations to the stream processor.
+type processFunc func(float64) (float64, error)
+
+// streamOptions holds the input and output settings shared by the streaming operations.
+type streamOptions struct {
+	format    string        // printf format of the output values
+	field     int           // 1-based field holding the value; 0 uses the whole line
+	delimiter string        // field delimiter; empty splits on runs of whitespace
+	csv       bool          // parse the input as RFC 4180 CSV
+	header    bool          // for csv: the first record is a header row
+	column    string        // for csv: column holding the value, by header name or 1-based index
+	jsonKey   string        // dotted path of the value in NDJSON input; empty for plain text
+	bare      bool          // for json: print bare numbers instead of the updated objects
+	allFields bool          // apply the operation to every number of each line
+	withInput bool          // print each input line before its output, separated by a tab
+	binaryIn  *binaryFormat // reads packed floats instead of text lines when set
+	binaryOut *binaryFormat // writes packed floats instead of text lines when set
+
+	output       string        // output table format: "csv", "tsv", or empty for plain lines
+	outputHeader bool          // for output: print a header row for multi-value results
+	join         bool          // print all output records on one line
+	joinSep      string        // for join: separator between records
+	flushEvery   int           // flush the output every n records; 0 only flushes when the buffer is full
+	summary      bool          // print a report of the run to stderr at the end
+	strict       bool          // unparsable input is fatal instead of skipped
+	parallel     int           // process lines with this many workers; 0 processes them in turn
+	pace         time.Duration // minimum time between two output records; 0 writes them as they come
+	emit         *emitter      // also sends the output values over UDP when set
+
+	split       bufio.SplitFunc // splits the input into records; nil uses bufio.ScanLines
+	maxLineSize int             // longest record the scanners accept, in bytes
+
+	nan          interval.NaNPolicy // how NaN and infinite input values are treated
+	nanLo, nanHi float64            // bounds infinities are clamped to under the clamp policy
+
+	// onExit, when set, is handed the exit code of a reader that fails, which
+	// then ends its goroutine instead of the program, so that a stream read in
+	// a goroutine of its own only exits from the main one.
+	onExit func(code int)
+
+	parse  func(string) (float64, error) // parses input values; nil uses interval.ParseHuman
+	render func(float64) (string, error) // renders output values; nil uses format
+	// renderOutputOnly keeps render off input values, which are printed with
+	// format, as when output values are bucket indexes.
+	renderOutputOnly bool
+}
+
+// clampTo returns a copy of the options that clamps infinite inputs to [a, b]
+// under the clamp policy, for operations working on that interval.
+func (o streamOptions) clampTo(a, b float64) streamOptions {
+	o.nanLo, o.nanHi = a, b
+	return o
+}
+
+// exit exits with code, as the function exit does, or hands code to onExit
+// when set and ends the calling goroutine.
+func (o streamOptions) exit(code int) {
+	if o.onExit != nil {
+		o.onExit(code)
+		runtime.Goexit()
+	}
+	exit(code)
+}
+
+// resolve applies the NaN policy to an input value. It reports whether the value
+// should be kept, and exits if the policy rejects it.
+func (o streamOptions) resolve(val float64) (float64, bool) {
+	resolved, err := o.nan.Resolve(val, o.nanLo, o.nanHi)
+	if errors.Is(err, interval.ErrNonFinite) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		o.exit(exitDomain)
+	}
+	if err != nil {
+		summary.dropped++
+	}
+	return resolved, err == nil
+}
+
+// outputComma returns the field separator of table output.
+func (o streamOptions) outputComma() rune {
+	if o.output == "tsv" {
+		return '\t'
+	}
+	return ','
+}
+
+// newScanner returns a scanner reading records from r.
+func (o streamOptions) newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(o.maxLineSize, bufio.MaxScanTokenSize)), o.maxLineSize)
+	if o.split != nil {
+		scanner.Split(o.split)
+	}
+	return scanner
+}
+
+// scanError adds a hint to the error of a scanner that hit the line size limit.
+func scanError(err error) error {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("%w (raise the limit with --max-line-size)", err)
+	}
+	return err
+}
+
+// sparkExitCode returns the exit code for the error of a sparkline: a domain
+// error for a value that --nan error rejects, a parse error for a field that
+// --strict rejects, a failure otherwise.
+func sparkExitCode(err error) int {
+	if errors.Is(err, interval.ErrNonFinite) {
+		return exitDomain
+	}
+	if errors.Is(err, interval.ErrUnparsable) {
+		return exitParse
+	}
+	return exitFailure
+}
+
+// parseRate parses an output rate, as a number of records per second, minute or
+// hour ("10/s", "30/m", "100/h"; a bare number is per second), and returns the
+// time between two records.
+func parseRate(spec string) (time.Duration, error) {
+	count, per, hasUnit := strings.Cut(spec, "/")
+	period := time.Second
+	if hasUnit {
+		switch per {
+		case "s":
+		case "m":
+			period = time.Minute
+		case "h":
+			period = time.Hour
+		default:
+			return 0, fmt.Errorf("invalid rate '%s' (expected <n>/s, <n>/m or <n>/h)", spec)
+		}
+	}
+	n, err := strconv.ParseFloat(count, 64)
+	if err != nil || !(n > 0) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("invalid rate '%s' (expected a positive number of records, e.g. 10/s)", spec)
+	}
+	return time.Duration(float64(period) / n), nil
+}
+
+// parseRecordDelim parses a record delimiter: a single character, or "nul" for NUL bytes.
+func parseRecordDelim(spec string) (bufio.SplitFunc, error) {
+	switch {
+	case strings.EqualFold(spec, "nul"):
+		return scanDelimited(0), nil
+	case len(spec) == 1:
+		return scanDelimited(spec[0]), nil
+	default:
+		return nil, fmt.Errorf("invalid record delimiter '%s' (expected a single character or nul)", spec)
+	}
+}
+
+// scanDelimited returns a split function that splits the input on delim instead of
+// newlines. Trailing carriage returns and newlines are trimmed from each record,
+// so that the last record of "1,2,3\n" is "3" rather than "3\n".
+func scanDelimited(delim byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.IndexByte(data, delim); i >= 0 {
+			return i + 1, bytes.TrimRight(data[:i], "\r\n"), nil
+		}
+		if atEOF {
+			return len(data), bytes.TrimRight(data, "\r\n"), nil
+		}
+		return 0, nil, nil
+	}
+}
+
+// parseValue parses an input value.
+func (o streamOptions) parseValue(s string) (float64, error) {
+	if o.parse != nil {
+		return o.parse(s)
+	}
+	return interval.ParseHuman(s)
+}
+
+// formatInput renders an input value.
+func (o streamOptions) formatInput(val float64) (string, error) {
+	if o.renderOutputOnly {
+		return fmt.Sprintf(o.format, val), nil
+	}
+	return o.formatValue(val)
+}
+
+// formatValue renders an output value.
+func (o streamOptions) formatValue(val float64) (string, error) {
+	if o.render != nil {
+		return o.render(val)
+	}
+	return fmt.Sprintf(o.format, val), nil
+}
+
+// record is a line of input with the position of its value, so the line can be
+// re-emitted with the value replaced.
+type record struct {
+	line       string
+	start, end int // Byte offsets of the value within the line.
+}
+
+// splitRecord locates the value of a line according to the field options.
+func splitRecord(line string, opts streamOptions) (record, error) {
+	if opts.jsonKey != "" {
+		return findJSONNumber(line, opts.jsonKey)
+	}
+	if opts.field <= 0 {
+		return record{line: line, start: 0, end: len(line)}, nil
+	}
+
+	fields := splitFields(line, opts.delimiter)
+	if opts.field > len(fields) {
+		return record{}, fmt.Errorf("line has %d field(s), field %d not found", len(fields), opts.field)
+	}
+	return fields[opts.field-1], nil
+}
+
+// splitFields splits a line into fields separated by delimiter, or by runs of
+// whitespace if delimiter is empty.
+func splitFields(line, delimiter string) []record {
+	var fields []record
+	if delimiter != "" {
+		start := 0
+		for {
+			end := strings.Index(line[start:], delimiter)
+			if end < 0 {
+				return append(fields, record{line: line, start: start, end: len(line)})
+			}
+			fields = append(fields, record{line: line, start: start, end: start + end})
+			start += end + len(delimiter)
+		}
+	}
+
+	inField := false
+	start := 0
+	for i, r := range line {
+		if unicode.IsSpace(r) {
+			if inField {
+				fields = append(fields, record{line: line, start: start, end: i})
+			}
+			inField = false
+			continue
+		}
+		if !inField {
+			inField = true
+			start = i
+		}
+	}
+	if inField {
+		fields = append(fields, record{line: line, start: start, end: len(line)})
+	}
+	return fields
+}
+
+// value returns the text of the record's value.
+func (r record) value() string {
+	return strings.TrimSpace(r.line[r.start:r.end])
+}
+
+// replace returns the line with its value replaced by s.
+func (r record) replace(s string) string {
+	return r.line[:r.start] + s + r.line[r.end:]
+}
+
+// processStream reads numbers from stdin, applies a processing function to each,
+// and prints the result to stdout. When a field is selected, the whole line is
+// printed with that field replaced.
+func processStream(opts streamOptions, proc processFunc) {
+	apply := opts.nan.Apply(proc, opts.nanLo, opts.nanHi)
+	if opts.parallel > 0 {
+		processParallel(opts, apply)
+		return
+	}
+	proc = func(val float64) (float64, error) {
+		summary.addInput(opts, val)
+		processedVal, err := apply(val)
+		if err == nil {
+			summary.out.Add(processedVal)
+		}
+		return processedVal, err
+	}
+	if opts.binaryIn != nil {
+		readBinaryStream(opts, func(val float64) {
+			processedVal, err := proc(val)
+			if err != nil {
+				processFailed(val, err)
+				return
+			}
+			if opts.binaryOut != nil {
+				printResult(opts, "", "", []float64{val}, []float64{processedVal})
+				return
+			}
+			var input string
+			var errIn error
+			if opts.withInput {
+				input, errIn = opts.formatInput(val)
+			}
+			output, errOut := opts.formatValue(processedVal)
+			if errIn != nil || errOut != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not format value %f, skipping: %v\n", val, errors.Join(errIn, errOut))
+				return
+			}
+			printResult(opts, input, output, []float64{val}, []float64{processedVal})
+		})
+		return
+	}
+	if opts.csv {
+		writer := csv.NewWriter(stdout)
+		writer.Comma = opts.outputComma()
+		scanCSV(opts, func(row []string) {
+			if opts.withInput {
+				row = append([]string{"input"}, row...)
+			}
+			if opts.binaryOut == nil {
+				writer.Write(row)
+				writer.Flush()
+				endRecord(opts)
+			}
+		}, func(row []string, col int) {
+			val, err := opts.parseValue(strings.TrimSpace(row[col]))
+			if err != nil {
+				inputFailed(opts, "input value", row[col], err)
+				return
+			}
+			processedVal, err := proc(val)
+			if err != nil {
+				processFailed(val, err)
+				return
+			}
+			output, err := opts.formatValue(processedVal)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not process value %f, skipping: %v\n", val, err)
+				summary.errored++
+				return
+			}
+			if opts.binaryOut != nil {
+				printResult(opts, "", "", []float64{val}, []float64{processedVal})
+				return
+			}
+			input := row[col]
+			row[col] = output
+			if opts.withInput {
+				row = append([]string{input}, row...)
+			}
+			writer.Write(row)
+			writer.Flush()
+			endRecord(opts)
+		})
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing to stdout: %v\n", err)
+			exit(exitFailure)
+		}
+		return
+	}
+
+	scanner := opts.newScanner(os.Stdin)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if opts.allFields {
+			processFields(line, opts, proc)
+			continue
+		}
+		reportLine(opts, processLine(line, opts, apply))
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", scanError(err))
+		exit(exitFailure)
+	}
+}
+
+// Stages of processLine, recorded in a lineOutcome: done, or the stage that failed.
+const (
+	lineDone        = iota
+	lineUnsplit     // The field of the value was not found.
+	lineUnparsed    // The value could not be parsed.
+	lineFailed      // The operation failed on the value.
+	lineUnformatted // The result could not be formatted.
+)
+
+// lineOutcome is the outcome of processing an input line with processLine.
+type lineOutcome struct {
+	stage          int
+	line, output   string
+	text           string // The text of the value.
+	val, processed float64
+	err            error
+}
+
+// processLine applies apply to the value of a line and formats the output line.
+// It only computes the outcome, which reportLine reports, so that lines can be
+// processed concurrently and reported in order.
+func processLine(line string, opts streamOptions, apply processFunc) lineOutcome {
+	rec, err := splitRecord(line, opts)
+	if err != nil {
+		return lineOutcome{stage: lineUnsplit, line: line, err: err}
+	}
+	o := lineOutcome{line: line, text: rec.value()}
+	if o.val, err = opts.parseValue(o.text); err != nil {
+		o.stage, o.err = lineUnparsed, err
+		return o
+	}
+	if o.processed, err = apply(o.val); err != nil {
+		o.stage, o.err = lineFailed, err
+		return o
+	}
+	output, err := opts.formatValue(o.processed)
+	if err == nil && opts.jsonKey != "" && !opts.bare {
+		output, err = jsonNumber(output)
+	}
+	if err != nil {
+		o.stage, o.err = lineUnformatted, err
+		return o
+	}
+	o.output = output
+	if !opts.bare {
+		o.output = rec.replace(output)
+	}
+	return o
+}
+
+// reportLine prints the output line of an outcome, or reports its failure, and
+// updates the summary.
+func reportLine(opts streamOptions, o lineOutcome) {
+	switch o.stage {
+	case lineUnsplit:
+		inputFailed(opts, "input line", o.line, o.err)
+		return
+	case lineUnparsed:
+		inputFailed(opts, "input value", o.text, o.err)
+		return
+	}
+	summary.addInput(opts, o.val)
+	if o.stage == lineFailed {
+		processFailed(o.val, o.err)
+		return
+	}
+	summary.out.Add(o.processed)
+	if o.stage == lineUnformatted {
+		fmt.Fprintf(os.Stderr, "Warning: could not process value %f, skipping: %v\n", o.val, o.err)
+		summary.errored++
+		return
+	}
+	printResult(opts, o.line, o.output, []float64{o.val}, []float64{o.processed})
+}
+
+// parallelChunk is the number of lines a worker of processParallel processes at
+// a time.
+const parallelChunk = 4096
+
+// processParallel processes the lines of stdin as processStream does, spreading
+// chunks of lines over opts.parallel workers. apply must be safe for concurrent
+// use. Outcomes are reported in the order of the input.
+func processParallel(opts streamOptions, apply processFunc) {
+	type chunk struct {
+		lines    []string
+		outcomes chan []lineOutcome
+	}
+	jobs := make(chan chunk, opts.parallel)
+	pending := make(chan chunk, 2*opts.parallel) // Chunks in input order.
+	for i := 0; i < opts.parallel; i++ {
+		go func() {
+			for c := range jobs {
+				outcomes := make([]lineOutcome, len(c.lines))
+				for i, line := range c.lines {
+					outcomes[i] = processLine(line, opts, apply)
+				}
+				c.outcomes <- outcomes
+			}
+		}()
+	}
+
+	var scanErr error
+	go func() {
+		defer close(pending)
+		defer close(jobs)
+		scanner := opts.newScanner(os.Stdin)
+		lines := make([]string, 0, parallelChunk)
+		send := func() {
+			c := chunk{lines: lines, outcomes: make(chan []lineOutcome, 1)}
+			pending <- c
+			jobs <- c
+			lines = make([]string, 0, parallelChunk)
+		}
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				lines = append(lines, line)
+			}
+			if len(lines) == parallelChunk {
+				send()
+			}
+		}
+		if len(lines) > 0 {
+			send()
+		}
+		scanErr = scanner.Err()
+	}()
+
+	for c := range pending {
+		for _, o := range <-c.outcomes {
+			reportLine(opts, o)
+		}
+	}
+	if scanErr != nil {
+		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", scanError(scanErr))
+		exit(exitFailure)
+	}
+}
+
+// processFields applies proc to every number of a line and prints the line with
+// the numbers replaced and the separators kept. Fields that are not numbers are
+// left as they are.
+func processFields(line string, opts streamOptions, proc processFunc) {
+	var b strings.Builder
+	var inputs, values []float64
+	last := 0
+	for _, f := range splitFields(line, opts.delimiter) {
+		val, err := opts.parseValue(f.value())
+		if err != nil {
+			continue
+		}
+		processedVal, err := proc(val)
+		if err != nil {
+			processFailed(val, err)
+			continue
+		}
+		output, err := opts.formatValue(processedVal)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not process value %f, skipping: %v\n", val, err)
+			summary.errored++
+			continue
+		}
+		b.WriteString(line[last:f.start])
+		b.WriteString(output)
+		last = f.end
+		inputs = append(inputs, val)
+		values = append(values, processedVal)
+	}
+	b.WriteString(line[last:])
+	printResult(opts, line, b.String(), inputs, values)
+}
+
+// printResult prints the output line of an input line, after the input line and
+// a tab with --with-input. Binary output writes the values instead, and table
+// output writes the fields of the lines. The values are also emitted with --emit.
+func printResult(opts streamOptions, input, output string, inputs, values []float64) {
+	opts.emit.send("", values...)
+	switch {
+	case opts.binaryOut != nil:
+		// The values were already recorded in the summary by the caller.
+		if opts.withInput {
+			writeBinary(opts, inputs...)
+		}
+		writeBinary(opts, values...)
+		endRecord(opts)
+	case opts.output != "":
+		var fields []string
+		if opts.withInput {
+			fields = opts.outputFields(input)
+		}
+		printFields(opts, append(fields, opts.outputFields(output)...)...)
+	case opts.withInput:
+		printLine(opts, input+"\t"+output)
+	default:
+		printLine(opts, output)
+	}
+}
+
+// outputFields splits a line into the fields of a table record. Lines are kept
+// whole unless fields were selected.
+func (o streamOptions) outputFields(line string) []string {
+	if o.field == 0 && !o.allFields {
+		return []string{line}
+	}
+	fields := splitFields(line, o.delimiter)
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		values[i] = f.value()
+	}
+	return values
+}
+
+// inputFailed reports an input record that could not be parsed. It is skipped
+// with a warning, or ends the program in strict mode.
+func inputFailed(opts streamOptions, what, text string, err error) {
+	if opts.strict {
+		fmt.Fprintf(os.Stderr, "Error: could not parse %s '%s': %v\n", what, text, err)
+		opts.exit(exitParse)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: could not parse %s '%s', skipping: %v\n", what, text, err)
+	summary.unparsed++
+}
+
+// processFailed reports a value that could not be processed. Values dropped by
+// the NaN policy are skipped silently, and values it rejects end the program.
+func processFailed(val float64, err error) {
+	switch {
+	case errors.Is(err, interval.ErrSkipped):
+		summary.dropped++
+	case errors.Is(err, interval.ErrNonFinite):
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exit(exitDomain)
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: could not process value %f, skipping: %v\n", val, err)
+		summary.errored++
+	}
+}
+
+// forEachValue reads numbers from stdin, one per line (or from the selected field),
+// and calls fn for each of them. Lines that cannot be parsed are skipped with a warning.
+func forEachValue(opts streamOptions, fn func(float64)) {
+	each := fn
+	fn = func(val float64) {
+		summary.addInput(opts, val)
+		if opts.nan == interval.NaNDefault {
+			each(val)
+		} else if resolved, ok := opts.resolve(val); ok {
+			each(resolved)
+		}
+	}
+	if opts.binaryIn != nil {
+		readBinaryStream(opts, fn)
+		return
+	}
+	if opts.csv {
+		scanCSV(opts, nil, func(row []string, col int) {
+			val, err := opts.parseValue(strings.TrimSpace(row[col]))
+			if err != nil {
+				inputFailed(opts, "input value", row[col], err)
+				return
+			}
+			fn(val)
+		})
+		return
+	}
+
+	scanner := opts.newScanner(os.Stdin)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if opts.allFields {
+			for _, f := range splitFields(line, opts.delimiter) {
+				if val, err := opts.parseValue(f.value()); err == nil {
+					fn(val)
+				}
+			}
+			continue
+		}
+		rec, err := splitRecord(line, opts)
+		if err != nil {
+			inputFailed(opts, "input line", line, err)
+			continue
+		}
+		val, err := opts.parseValue(rec.value())
+		if err != nil {
+			inputFailed(opts, "input value", rec.value(), err)
+			continue
+		}
+		fn(val)
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", scanError(err))
+		opts.exit(exitFailure)
+	}
+}
+
+// forEachPair reads intervals from stdin, one "<start> <end>" pair per line
+// (fields split as for --delimiter), and calls fn for each of them. Fields past
+// the first two are ignored. Lines that cannot be parsed are skipped with a warning.
+func forEachPair(opts streamOptions, fn func(pair [2]float64)) {
+	forEachRow(opts, 2, "interval", "a start and an end", func(row []float64) {
+		fn([2]float64{row[0], row[1]})
+	})
+}
+
+// forEachRow reads rows of n numbers from stdin, one per line (fields split as
+// for --delimiter), and calls fn for each of them. Fields past the first n are
+// ignored. Lines that cannot be parsed are skipped with a warning naming what a
+// row is, and what the line was expected to hold.
+func forEachRow(opts streamOptions, n int, what, expected string, fn func(row []float64)) {
+	scanner := opts.newScanner(os.Stdin)
+	row := make([]float64, n)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := splitFields(line, opts.delimiter)
+		if len(fields) < n {
+			inputFailed(opts, what, line, fmt.Errorf("expected %s", expected))
+			continue
+		}
+		var err error
+		for i := range row {
+			if row[i], err = opts.parseValue(fields[i].value()); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			inputFailed(opts, what, line, err)
+			continue
+		}
+		fn(row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading from stdin: %v\n", scanError(err))
+		exit(exitFailure)
+	}
+}
+
+// scanCSV reads CSV records from stdin. The header row, if any, is passed to
+// onHeader (when not nil), and every other record to onRow together with the
+// index of the selected column. Records too short to hold the column are
+// skipped with a warning.
+func scanCSV(opts streamOptions, onHeader func([]string), onRow func(row []string, col int)) {
+	reader := csv.NewReader(os.Stdin)
+	reader.FieldsPerRecord = -1
+
+	col := -1
+	if opts.column == "" {
+		col = max(opts.field, 1) - 1
+	} else if n, err := strconv.Atoi(opts.column); err == nil && n > 0 {
+		col = n - 1
+	}
+
+	first := true
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading CSV from stdin: %v\n", err)
+			opts.exit(exitFailure)
+		}
+
+		if first && opts.header {
+			first = false
+			if col < 0 {
+				col = slices.Index(row, opts.column)
+				if col < 0 {
+					fmt.Fprintf(os.Stderr, "Error: column '%s' not found in CSV header\n", opts.column)
+					opts.exit(exitUsage)
+				}
+			}
+			if onHeader != nil {
+				onHeader(row)
+			}
+			continue
+		}
+		first = false
+
+		if col < 0 {
+			fmt.Fprintf(os.Stderr, "Error: selecting column '%s' by name requires --header\n", opts.column)
+			opts.exit(exitUsage)
+		}
+		if col >= len(row) {
+			fmt.Fprintf(os.Stderr, "Warning: CSV record has %d field(s), column %d not found, skipping\n", len(row), col+1)
+			continue
+		}
+		onRow(row, col)
+	}
+}
+
+// readStream reads all numbers from stdin, for operations that need the whole
+// stream at once.
+func readStream(opts streamOptions) []float64 {
+	var values []float64
+	forEachValue(opts, func(val float64) {
+		values = append(values, val)
+	})
+	return values
+}
+
+// writeBinary writes values to stdout in the binary output format.
+func writeBinary(opts streamOptions, values ...float64) {
+	for _, val := range values {
+		if err := opts.binaryOut.write(stdout, val); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing to stdout: %v\n", err)
+			exit(exitFailure)
+		}
+	}
+}
+
+// printValues prints values on one line, separated by spaces.
+func printValues(opts streamOptions, values ...float64) {
+	printLabeled(opts, "", values...)
+}
+
+// printLabeled prints values on one line, separated by spaces, after a label.
+// An empty label is omitted. In binary output, values are written one after the
+// other and the label is dropped.
+func printLabeled(opts streamOptions, label string, values ...float64) {
+	for _, val := range values {
+		summary.out.Add(val)
+	}
+	opts.emit.send(label, values...)
+	if opts.binaryOut != nil {
+		writeBinary(opts, values...)
+		endRecord(opts)
+		return
+	}
+
+	fields := make([]string, 0, len(values)+1)
+	if label != "" {
+		fields = append(fields, label)
+	}
+	for _, val := range values {
+		output, err := opts.formatValue(val)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not format value %f, skipping: %v\n", val, err)
+			return
+		}
+		fields = append(fields, output)
+	}
+	printFields(opts, fields...)
+}
+
+// printHeader prints the column names of a multi-value result, when a header
+// row was asked for in table output.
+func printHeader(opts streamOptions, names ...string) {
+	if opts.output != "" && opts.outputHeader && opts.binaryOut == nil {
+		printFields(opts, names...)
+	}
+}
+
+// printFields prints the fields of an output line, separated by spaces, or as a
+// CSV or TSV record in table output.
+func printFields(opts streamOptions, fields ...string) {
+	if opts.output == "" {
+		printLine(opts, strings.Join(fields, " "))
+		return
+	}
+
+	var b strings.Builder
+	writer := csv.NewWriter(&b)
+	writer.Comma = opts.outputComma()
+	writer.Write(fields)
+	writer.Flush()
+	printLine(opts, strings.TrimSuffix(b.String(), "\n"))
+}
+
+// stdout buffers all output. It is flushed at the end by finishOutput, and
+// every opts.flushEvery records for live pipelines.
+var stdout = bufio.NewWriter(os.Stdout)
+
+// unflushed counts the records written since the last flush.
+var unflushed int
+
+// joinStarted reports whether a record was already printed on the joined line.
+var joinStarted bool
+
+// nextRecord is the earliest time the next record may be written, with opts.pace.
+var nextRecord time.Time
+
+// printLine prints an output record on its own line, or after the separator on
+// the joined line.
+func printLine(opts streamOptions, line string) {
+	if !opts.join {
+		stdout.WriteString(line)
+		stdout.WriteByte('\n')
+	} else {
+		if joinStarted {
+			stdout.WriteString(opts.joinSep)
+		}
+		stdout.WriteString(line)
+		joinStarted = true
+	}
+	endRecord(opts)
+}
+
+// endRecord flushes the output if opts.flushEvery records were written since
+// the last flush. With opts.pace, every record is flushed once its time has come.
+func endRecord(opts streamOptions) {
+	unflushed++
+	if opts.pace > 0 {
+		time.Sleep(time.Until(nextRecord))
+		flushOutput()
+		if now := time.Now(); nextRecord.Before(now.Add(-opts.pace)) { // The input fell behind: do not catch up in a burst.
+			nextRecord = now
+		}
+		nextRecord = nextRecord.Add(opts.pace)
+		return
+	}
+	if opts.flushEvery > 0 && unflushed >= opts.flushEvery {
+		flushOutput()
+	}
+}
+
+// flushOutput writes the buffered output to stdout.
+func flushOutput() {
+	unflushed = 0
+	if err := stdout.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing to stdout: %v\n", err)
+		os.Exit(exitFailure)
+	}
+}
+
+// finishOutput ends the joined line, flushes the output and prints the summary
+// report if asked for. It must be called once all results are printed.
+func finishOutput(opts streamOptions) {
+	if joinStarted {
+		stdout.WriteByte('\n')
+	}
+	flushOutput()
+	if opts.summary {
+		summary.report(opts)
+	}
+}
+
+// runSummary counts what happened to the input values, for --summary.
+type runSummary struct {
+	parsed   int          // Values parsed from the input.
+	unparsed int          // Input records that could not be parsed.
+	dropped  int          // Values skipped by the NaN policy.
+	clamped  atomic.Int64 // Values clamped into the interval of the operation, also by --parallel workers.
+	errored  int          // Values the operation failed on.
+	in, out  interval.RunningRange
+}
+
+// summary is updated as the stream is read and written.
+var summary runSummary
+
+// countClamped wraps fn, which clamps its results to the interval it maps [a, b]
+// to, so that the values outside [a, b] count as clamped in the summary.
+func countClamped(fn processFunc, a, b float64) processFunc {
+	lo, hi := min(a, b), max(a, b)
+	return func(val float64) (float64, error) {
+		res, err := fn(val)
+		if err == nil && (val < lo || val > hi) {
+			summary.clamped.Add(1)
+		}
+		return res, err
+	}
+}
+
+// addInput records a parsed input value.
+func (s *runSummary) addInput(opts streamOptions, val float64) {
+	s.parsed++
+	s.in.Add(val)
+	if opts.nan == interval.NaNClamp && math.IsInf(val, 0) {
+		s.clamped.Add(1)
+	}
+}
+
+// report prints the summary to stderr.
+func (s *runSummary) report(opts streamOptions) {
+	fmt.Fprintf(os.Stderr, "read %d, skipped %d, clamped %d, errored %d\n",
+		s.parsed+s.unparsed, s.unparsed+s.dropped, s.clamped.Load(), s.errored)
+	for _, r := range []struct {
+		name string
+		rng  interval.RunningRange
+	}{{"input", s.in}, {"output", s.out}} {
+		if r.rng.Count == 0 {
+			continue
+		}
+		format := opts.formatValue
+		if r.name == "input" {
+			format = opts.formatInput
+		}
+		lo, errLo := format(r.rng.Min)
+		hi, errHi := format(r.rng.Max)
+		if errLo == nil && errHi == nil {
+			fmt.Fprintf(os.Stderr, "%s min %s max %s\n", r.name, lo, hi)
+		}
+	}
+}
+
+// exit flushes the output printed so far and exits with the given code.
+func exit(code int) {
+	stdout.Flush()
+	os.Exit(code)
+}
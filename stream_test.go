@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPrintFields(t *testing.T) {
+	defer func(w *bufio.Writer) { stdout = w }(stdout)
+	tests := []struct {
+		name   string
+		output string
+		fields []string
+		want   string
+	}{
+		{"plain", "", []string{"a,b", "1"}, "a,b 1\n"},
+		{"csv", "csv", []string{"a", "1"}, "a,1\n"},
+		{"csv quotes the delimiter", "csv", []string{"a,b", "1"}, "\"a,b\",1\n"},
+		{"csv doubles quotes", "csv", []string{`say "hi"`, "1"}, "\"say \"\"hi\"\"\",1\n"},
+		{"csv quotes newlines", "csv", []string{"two\nlines", "1"}, "\"two\nlines\",1\n"},
+		{"tsv quotes tabs", "tsv", []string{"a\tb", "a,b"}, "\"a\tb\"\ta,b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			stdout = bufio.NewWriter(&b)
+			printFields(streamOptions{output: tt.output}, tt.fields...)
+			stdout.Flush()
+			if got := b.String(); got != tt.want {
+				t.Errorf("printFields(%q) = %q, want %q", tt.fields, got, tt.want)
+			}
+		})
+	}
+}
+
+// parallelInput returns n lines of values spanning several chunks of
+// processParallel, with a line that is not a number every 1000 lines.
+func parallelInput(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i%1000 == 999 {
+			b.WriteString("x\n")
+			continue
+		}
+		fmt.Fprintf(&b, "%g\n", float64(i%250)-20.5)
+	}
+	return b.String()
+}
+
+func TestProcessParallel(t *testing.T) {
+	input := parallelInput(3*parallelChunk + 123)
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"remap", []string{"-r", "0", "200", "0", "1"}},
+		{"remap with clamp", []string{"-r", "0", "200", "0", "1", "--clamp"}},
+		{"limit", []string{"-l", "0", "100"}},
+		{"expr", []string{"--expr", "clamp(0,200) | remap(0,200,0,1) | ease(inOutQuad)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--summary"}, tt.args...)
+			want, wantSummary, wantCode := runSpan(t, input, args...)
+			got, gotSummary, code := runSpan(t, input, append([]string{"--parallel=4"}, args...)...)
+			if code != wantCode {
+				t.Errorf("exit code with --parallel = %d, want %d", code, wantCode)
+			}
+			if got != want {
+				t.Errorf("output with --parallel differs from the serial output (%d bytes, want %d)", len(got), len(want))
+			}
+			if gotSummary != wantSummary {
+				t.Errorf("stderr with --parallel = %q, want %q", lastLines(gotSummary, 3), lastLines(wantSummary, 3))
+			}
+		})
+	}
+}
+
+func TestProcessParallelStrict(t *testing.T) {
+	// The line that fails is in the middle of the second of four chunks.
+	lines := strings.SplitAfter(parallelInput(4*parallelChunk), "\n")
+	bad := parallelChunk + parallelChunk/2
+	lines = append(lines[:bad], append([]string{"oops\n"}, lines[bad:]...)...)
+	for i := range lines[:bad] {
+		if lines[i] == "x\n" {
+			lines[i] = "0\n"
+		}
+	}
+	args := []string{"--strict", "-r", "0", "200", "0", "1"}
+	want, _, _ := runSpan(t, strings.Join(lines[:bad], ""), args...)
+	got, stderr, code := runSpan(t, strings.Join(lines, ""), append([]string{"--parallel=4"}, args...)...)
+	if code != exitParse {
+		t.Errorf("exit code = %d, want %d", code, exitParse)
+	}
+	if got != want {
+		t.Errorf("output before the failing line has %d bytes, want the %d bytes of the lines before it", len(got), len(want))
+	}
+	if !strings.Contains(stderr, "'oops'") {
+		t.Errorf("stderr = %q, want the failing line reported", stderr)
+	}
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	return strings.Join(lines[max(0, len(lines)-n):], "\n")
+}
+
+func TestSummaryClamped(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"remap", []string{"-r", "0", "10", "0", "1"}, "read 4, skipped 0, clamped 0, errored 0"},
+		{"remap with clamp", []string{"-r", "0", "10", "0", "1", "--clamp"}, "read 4, skipped 0, clamped 2, errored 0"},
+		{"remap with clamp in parallel", []string{"--parallel=2", "-r", "0", "10", "0", "1", "--clamp"}, "read 4, skipped 0, clamped 2, errored 0"},
+		{"eval with clamp", []string{"-e", "0", "10", "--clamp"}, "read 4, skipped 0, clamped 3, errored 0"},
+		{"deval with clamp", []string{"-d", "10", "0", "--clamp"}, "read 4, skipped 0, clamped 2, errored 0"},
+		{"limit", []string{"-l", "0", "10"}, "read 4, skipped 0, clamped 2, errored 0"},
+		{"snap", []string{"-S", "4", "0", "10"}, "read 4, skipped 0, clamped 2, errored 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, code := runSpan(t, "-5\n5\n15\n0.5\n", append([]string{"--summary"}, tt.args...)...)
+			if code != 0 {
+				t.Fatalf("span %q exit code = %d, want 0", tt.args, code)
+			}
+			if got, _, _ := strings.Cut(stderr, "\n"); got != tt.want {
+				t.Errorf("span %q summary = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package main
+
+import "os"
+
+// terminalWidth returns 0, as the size of the terminal is not queried on this
+// platform; the COLUMNS environment variable is used instead.
+func terminalWidth(f *os.File) int {
+	return 0
+}
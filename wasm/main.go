@@ -0,0 +1,143 @@
+//go:build js && wasm
+
+// Command wasm exposes the interval package to JavaScript, so web pages can use
+// the same math and sparklines as span without reimplementing them. Once the
+// module is running, the functions are available on the global object span:
+//
+//	span.remap(val, srcA, srcB, dstA, dstB)
+//	span.eval(t, a, b)
+//	span.snap(val, steps, a, b)
+//	span.sparkline(values, {min, max, style})
+//
+// Invalid input, such as an argument that is not a number or a source interval
+// with a zero delta, returns an Error object rather than throwing, as a Go
+// function cannot throw into JavaScript.
+//
+// Build it with: GOOS=js GOARCH=wasm go build -o span.wasm ./wasm
+package main
+
+import (
+	"fmt"
+	"math"
+	"syscall/js"
+
+	"github.com/gregory-chatelier/span/interval"
+)
+
+// jsFunc wraps fn as a JavaScript function taking at least min arguments. An
+// error returned by fn is returned to JavaScript as an Error object.
+func jsFunc(min int, fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) < min {
+			return jsError(fmt.Errorf("expected %d arguments, got %d", min, len(args)))
+		}
+		result, err := fn(args)
+		if err != nil {
+			return jsError(err)
+		}
+		return result
+	})
+}
+
+// jsNumbers returns the first n arguments, which must be numbers. jsFunc has
+// already checked that there are enough of them.
+func jsNumbers(args []js.Value, n int) ([]float64, error) {
+	numbers := make([]float64, n)
+	for i := range numbers {
+		if args[i].Type() != js.TypeNumber {
+			return nil, fmt.Errorf("argument %d must be a number, got %s", i+1, args[i].Type())
+		}
+		numbers[i] = args[i].Float()
+	}
+	return numbers, nil
+}
+
+// jsError returns err as a JavaScript Error.
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
+
+// sparkConfig reads the options of span.sparkline: min and max fix the scale,
+// and style is one of the --style names.
+func sparkConfig(options js.Value) (interval.SparkConfig, error) {
+	var config interval.SparkConfig
+	if options.Type() != js.TypeObject {
+		return config, nil
+	}
+	if min := options.Get("min"); min.Type() == js.TypeNumber {
+		config.Min, config.HasMin = min.Float(), true
+	}
+	if max := options.Get("max"); max.Type() == js.TypeNumber {
+		config.Max, config.HasMax = max.Float(), true
+	}
+	if style := options.Get("style"); style.Type() == js.TypeString {
+		var err error
+		if config.Style, err = interval.ParseStyle(style.String()); err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
+
+// newSpan returns the object holding the functions exposed to JavaScript.
+func newSpan() js.Value {
+	span := js.Global().Get("Object").New()
+	span.Set("remap", jsFunc(5, func(args []js.Value) (any, error) {
+		n, err := jsNumbers(args, 5)
+		if err != nil {
+			return nil, err
+		}
+		return interval.Remap(n[0], n[1], n[2], n[3], n[4])
+	}))
+	span.Set("eval", jsFunc(3, func(args []js.Value) (any, error) {
+		n, err := jsNumbers(args, 3)
+		if err != nil {
+			return nil, err
+		}
+		return interval.Eval(n[0], n[1], n[2]), nil
+	}))
+	span.Set("snap", jsFunc(4, func(args []js.Value) (any, error) {
+		n, err := jsNumbers(args, 4)
+		if err != nil {
+			return nil, err
+		}
+		if n[1] != math.Trunc(n[1]) {
+			return nil, fmt.Errorf("steps must be an integer, got %v", n[1])
+		}
+		return interval.Snap(n[0], int(n[1]), n[2], n[3])
+	}))
+	span.Set("sparkline", jsFunc(1, func(args []js.Value) (any, error) {
+		values := args[0]
+		if !values.InstanceOf(js.Global().Get("Array")) {
+			return nil, fmt.Errorf("expected an array of numbers")
+		}
+		var options js.Value
+		if len(args) > 1 {
+			options = args[1]
+		}
+		config, err := sparkConfig(options)
+		if err != nil {
+			return nil, err
+		}
+		spark, err := interval.NewSparkline(config)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < values.Length(); i++ {
+			val := values.Index(i)
+			if val.Type() != js.TypeNumber {
+				return nil, fmt.Errorf("value %d of the array must be a number, got %s", i, val.Type())
+			}
+			spark.Add(val.Float())
+		}
+		return spark.Render(), nil
+	}))
+	return span
+}
+
+func main() {
+	js.Global().Set("span", newSpan())
+
+	// Keep the functions alive for the lifetime of the page.
+	select {}
+}
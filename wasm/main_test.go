@@ -0,0 +1,62 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"strings"
+	"syscall/js"
+	"testing"
+)
+
+func TestFunctions(t *testing.T) {
+	span := newSpan()
+	object := func(fields map[string]any) js.Value {
+		o := js.Global().Get("Object").New()
+		for k, v := range fields {
+			o.Set(k, v)
+		}
+		return o
+	}
+	array := func(values ...any) js.Value { return js.ValueOf(values) }
+	tests := []struct {
+		name    string
+		fn      string
+		args    []any
+		want    any    // The number or string returned, when no error is.
+		wantErr string // Part of the message of the Error returned.
+	}{
+		{"remap", "remap", []any{5, 0, 10, 0, 100}, 50.0, ""},
+		{"remap zero delta", "remap", []any{5, 3, 3, 0, 100}, nil, "zero delta"},
+		{"remap string", "remap", []any{"5", 0, 10, 0, 100}, nil, "argument 1 must be a number, got string"},
+		{"remap too few", "remap", []any{5, 0, 10}, nil, "expected 5 arguments, got 3"},
+		{"eval", "eval", []any{0.25, 10, 20}, 12.5, ""},
+		{"eval undefined", "eval", []any{0.25, js.Undefined(), 20}, nil, "argument 2 must be a number, got undefined"},
+		{"snap", "snap", []any{0.3, 4, 0, 1}, 0.25, ""},
+		{"snap fractional steps", "snap", []any{0.3, 2.5, 0, 1}, nil, "steps must be an integer"},
+		{"snap no steps", "snap", []any{0.3, 0, 0, 1}, nil, "positive"},
+		{"sparkline", "sparkline", []any{array(0, 4, 8), object(map[string]any{"min": 0, "max": 8})}, " ▄█", ""},
+		{"sparkline without options", "sparkline", []any{array(1, 2)}, " █", ""},
+		{"sparkline style", "sparkline", []any{array(1, -1), object(map[string]any{"style": "winloss"})}, "▀▄", ""},
+		{"sparkline unknown style", "sparkline", []any{array(1), object(map[string]any{"style": "dots"})}, nil, "dots"},
+		{"sparkline not an array", "sparkline", []any{5}, nil, "expected an array of numbers"},
+		{"sparkline string value", "sparkline", []any{array(1, "2")}, nil, "value 1 of the array must be a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := span.Call(tt.fn, tt.args...)
+			isErr := got.InstanceOf(js.Global().Get("Error"))
+			switch {
+			case tt.wantErr != "":
+				if !isErr || !strings.Contains(got.Get("message").String(), tt.wantErr) {
+					t.Errorf("span.%s() = %v, want an Error containing %q", tt.fn, got, tt.wantErr)
+				}
+			case isErr:
+				t.Errorf("span.%s() returned an unexpected error: %s", tt.fn, got.Get("message").String())
+			case got.Type() == js.TypeNumber && got.Float() != tt.want:
+				t.Errorf("span.%s() = %v, want %v", tt.fn, got.Float(), tt.want)
+			case got.Type() == js.TypeString && got.String() != tt.want:
+				t.Errorf("span.%s() = %q, want %q", tt.fn, got.String(), tt.want)
+			}
+		})
+	}
+}